@@ -1,6 +1,7 @@
 package codeGenerator
 
 import (
+	data "github.com/DanielNos/neco/dataStructures"
 	"github.com/DanielNos/neco/parser"
 	VM "github.com/DanielNos/neco/virtualMachine"
 )
@@ -16,7 +17,12 @@ func (cg *CodeGenerator) storeTop(to *parser.Node, pop bool) {
 	} else if to.NodeType == parser.NT_ListValue {
 		listAssignNode := to.Value.(*parser.TypedBinaryNode)
 		cg.generateExpression(listAssignNode.Right)
-		cg.addInstruction(VM.IT_SetListAtAToB, cg.findVariableIdentifier(listAssignNode.Left.Value.(*parser.VariableNode).Identifier))
+
+		if listAssignNode.Left.Value.(*parser.VariableNode).DataType.Type == data.DT_Map {
+			cg.addInstruction(VM.IT_SetMapAtAToB, cg.findVariableIdentifier(listAssignNode.Left.Value.(*parser.VariableNode).Identifier))
+		} else {
+			cg.addInstruction(VM.IT_SetListAtAToB, cg.findVariableIdentifier(listAssignNode.Left.Value.(*parser.VariableNode).Identifier))
+		}
 	}
 }
 
@@ -19,22 +19,99 @@ var builtInFunctions = map[string]byte{
 	"ceilToInt":  VM.BIF_CeilToInt,
 	"round":      VM.BIF_Round,
 	"roundToInt": VM.BIF_RoundToInt,
-	"absInt":     VM.BIF_AbsInt,
-	"absFlt":     VM.BIF_AbsFloat,
+	"abs.int":    VM.BIF_AbsInt,
+	"abs.float":  VM.BIF_AbsFloat,
+
+	"sqrt":            VM.BIF_Sqrt,
+	"pow.int.int":     VM.BIF_PowerInt,
+	"pow.float.float": VM.BIF_PowerFloat,
+	"exp":             VM.BIF_Exp,
+	"log":             VM.BIF_Log,
+	"log2":            VM.BIF_Log2,
+	"log10":           VM.BIF_Log10,
+
+	"sin":   VM.BIF_Sin,
+	"cos":   VM.BIF_Cos,
+	"tan":   VM.BIF_Tan,
+	"asin":  VM.BIF_Asin,
+	"acos":  VM.BIF_Acos,
+	"atan":  VM.BIF_Atan,
+	"atan2": VM.BIF_Atan2,
+
+	"min.int.int":             VM.BIF_MinInt,
+	"min.float.float":         VM.BIF_MinFloat,
+	"max.int.int":             VM.BIF_MaxInt,
+	"max.float.float":         VM.BIF_MaxFloat,
+	"clamp.int.int.int":       VM.BIF_ClampInt,
+	"clamp.float.float.float": VM.BIF_ClampFloat,
+	"sign.int":                VM.BIF_SignInt,
+	"sign.float":              VM.BIF_SignFloat,
+
+	"isNaN": VM.BIF_IsNaN,
+	"isInf": VM.BIF_IsInf,
 
 	"readLine": VM.BIF_ReadLine,
 	"readChar": VM.BIF_ReadChar,
 
+	"readFile":   VM.BIF_ReadFile,
+	"readLines":  VM.BIF_ReadLines,
+	"listDir":    VM.BIF_ListDir,
+	"fileExists": VM.BIF_FileExists,
+	"writeFile":  VM.BIF_WriteFile,
+	"appendFile": VM.BIF_AppendFile,
+	"makeDir":    VM.BIF_MakeDir,
+	"removeFile": VM.BIF_RemoveFile,
+
+	"getEnv": VM.BIF_GetEnv,
+	"setEnv": VM.BIF_SetEnv,
+
 	"length": VM.BIF_StringLength,
 	"size":   VM.BIF_ListLength,
 
+	"insert":  VM.BIF_Insert,
+	"pop":     VM.BIF_Pop,
+	"reverse": VM.BIF_Reverse,
+	"sort":    VM.BIF_Sort,
+	"clear":   VM.BIF_Clear,
+	"sorted":  VM.BIF_Sorted,
+	"copy":    VM.BIF_Copy,
+	"range":   VM.BIF_Range,
+
+	"keys":   VM.BIF_MapKeys,
+	"values": VM.BIF_MapValues,
+
 	"toLower": VM.BIF_ToLower,
 	"toUpper": VM.BIF_ToUpper,
 
+	"split":      VM.BIF_Split,
+	"join":       VM.BIF_Join,
+	"replace":    VM.BIF_Replace,
+	"contains":   VM.BIF_Contains,
+	"startsWith": VM.BIF_StartsWith,
+	"endsWith":   VM.BIF_EndsWith,
+	"indexOf":    VM.BIF_IndexOf,
+	"trim":       VM.BIF_Trim,
+	"trimLeft":   VM.BIF_TrimLeft,
+	"trimRight":  VM.BIF_TrimRight,
+	"repeat":     VM.BIF_Repeat,
+	"padLeft":    VM.BIF_PadLeft,
+	"padRight":   VM.BIF_PadRight,
+	"format":     VM.BIF_Format,
+
 	"randomInt":      VM.BIF_RandomInt,
 	"randomFlt":      VM.BIF_RandomFloat,
 	"randomRangeInt": VM.BIF_RandomRangeInt,
 
+	"toJson":   VM.BIF_ToJson,
+	"fromJson": VM.BIF_FromJson,
+
+	"now":        VM.BIF_Now,
+	"nowFlt":     VM.BIF_NowFloat,
+	"monotonic":  VM.BIF_Monotonic,
+	"sleep":      VM.BIF_Sleep,
+	"formatTime": VM.BIF_FormatTime,
+	"parseTime":  VM.BIF_ParseTime,
+
 	"parseInt": VM.BIF_ParseInt,
 	"parseFlt": VM.BIF_ParseFloat,
 
@@ -43,5 +120,11 @@ var builtInFunctions = map[string]byte{
 }
 
 var overloadedBuiltInFunctions = map[string]struct{}{
-	"int": {},
+	"int":   {},
+	"abs":   {},
+	"pow":   {},
+	"min":   {},
+	"max":   {},
+	"clamp": {},
+	"sign":  {},
 }
@@ -26,8 +26,15 @@ const (
 	ST_Root ScopeType = iota
 	ST_Unnamed
 	ST_Function
+	ST_Try
 )
 
+// Lambda which body will be generated after all named functions
+type Lambda struct {
+	node   *parser.Node
+	scopes data.Stack // Scopes surrounding the lambda
+}
+
 type Scope struct {
 	scopeType                 ScopeType
 	variableIdentifierCounter uint8
@@ -53,7 +60,12 @@ type CodeGenerator struct {
 
 	functions []int // Function number : function start
 
+	namedFunctionCount int
+	lambdas            []Lambda
+	isInEntry          bool // Returned values of entry function are used as exit codes
+
 	scopeBreaks     *data.Stack // break
+	scopeContinues  *data.Stack // continue
 	loopScopeDepths *data.Stack // int
 
 	scopes *data.Stack // Scope
@@ -80,7 +92,12 @@ func NewGenerator(tree *parser.Node, intConstants map[int64]int, floatConstants
 
 		functions: []int{},
 
+		namedFunctionCount: 0,
+		lambdas:            []Lambda{},
+		isInEntry:          false,
+
 		scopeBreaks:     data.NewStack(),
+		scopeContinues:  data.NewStack(),
 		loopScopeDepths: data.NewStack(),
 
 		scopes:     data.NewStack(),
@@ -108,11 +125,19 @@ func (cg *CodeGenerator) Generate() {
 		return
 	}
 
+	// Count named functions, lambdas are numbered after them
+	for _, node := range statements {
+		if node.NodeType == parser.NT_FunctionDeclaration {
+			cg.namedFunctionCount++
+		}
+	}
+
 	// Generate code
 	cg.generateGlobals(statements)
 
 	// Generate call to entry function
 	cg.generateFunctions(statements)
+	cg.generateLambdas()
 
 	// Optimize instructions
 	if cg.optimize {
@@ -125,6 +150,77 @@ func (cg *CodeGenerator) Generate() {
 	}
 }
 
+// Generates instructions of input of an interactive session. Functions of the input are generated to FunctionsInstructions,
+// other statements to GlobalsInstructions. Values returned by called functions are printed.
+func (cg *CodeGenerator) GenerateInput(tree *parser.Node) {
+	cg.generateInputConstantIDs()
+
+	statements := tree.Value.(*parser.ModuleNode).Statements.Statements
+
+	cg.GlobalsInstructions = []VM.Instruction{}
+	cg.FunctionsInstructions = []VM.Instruction{}
+	cg.lambdas = []Lambda{}
+
+	// Lambdas are numbered after named functions of all inputs
+	cg.namedFunctionCount = len(cg.functions)
+	for _, node := range statements {
+		if node.NodeType == parser.NT_FunctionDeclaration {
+			cg.namedFunctionCount++
+		}
+	}
+
+	// Generate functions
+	cg.target = &cg.FunctionsInstructions
+	cg.currentFile = ""
+
+	for _, node := range statements {
+		if node.NodeType == parser.NT_FunctionDeclaration {
+			cg.updateFileAndLine(node)
+			cg.generateFunction(node)
+		}
+	}
+
+	// Generate statements
+	cg.target = &cg.GlobalsInstructions
+	cg.currentFile = ""
+
+	for _, node := range statements {
+		if node.NodeType == parser.NT_FunctionDeclaration {
+			continue
+		}
+
+		cg.generateNode(node)
+
+		// Print returned value, functions without return type have unknown return type
+		var returnType *data.DataType
+		if node.NodeType == parser.NT_FunctionCall {
+			returnType = node.Value.(*parser.FunctionCallNode).ReturnType
+		}
+
+		if returnType != nil && returnType.Type != data.DT_Unknown {
+			cg.addInstruction(VM.IT_CallBuiltInFunc, VM.BIF_AnyToString)
+			cg.addInstruction(VM.IT_CallBuiltInFunc, VM.BIF_PrintLine)
+		}
+	}
+
+	// Generate lambdas, they change scopes to scopes surrounding them
+	scopes := cg.scopes
+	cg.target = &cg.FunctionsInstructions
+
+	cg.generateLambdas()
+	cg.scopes = scopes
+}
+
+// Returns number of generated functions, including lambdas.
+func (cg *CodeGenerator) FunctionCount() int {
+	return len(cg.functions)
+}
+
+// Returns start positions of functions generated from the last input. They are relative to it's FunctionsInstructions.
+func (cg *CodeGenerator) InputFunctions(previousCount int) []int {
+	return cg.functions[previousCount:]
+}
+
 func (cg *CodeGenerator) currentLine(node *parser.Node) uint {
 	return uint(cg.currentLines[*node.Position.File])
 }
@@ -209,24 +305,70 @@ func (cg *CodeGenerator) generateConstantIDs() {
 	}
 }
 
+// Assigns IDs to constants of input of an interactive session. Constants of previous inputs keep their IDs.
+func (cg *CodeGenerator) generateInputConstantIDs() {
+	for key := range cg.stringConstants {
+		cg.stringConstants[key] = cg.inputConstantID(key)
+	}
+
+	for key := range cg.intConstants {
+		cg.intConstants[key] = cg.inputConstantID(key)
+	}
+
+	for key := range cg.floatConstants {
+		cg.floatConstants[key] = cg.inputConstantID(key)
+	}
+
+	// More than 255 constants
+	if len(cg.Constants) >= math.MaxUint8 {
+		cg.newError(fmt.Sprintf("Constant pool overflow with %d constants. Constant pool can only contain maximum of %d constants.", len(cg.Constants), math.MaxUint8))
+	}
+}
+
+func (cg *CodeGenerator) inputConstantID(constant any) int {
+	for id, existing := range cg.Constants {
+		if existing == constant {
+			return id
+		}
+	}
+
+	cg.Constants = append(cg.Constants, constant)
+	return len(cg.Constants) - 1
+}
+
 func (cg *CodeGenerator) generateFunctions(statements []*parser.Node) {
 	// Reset line
 	cg.target = &cg.FunctionsInstructions
+
+	// Program arguments are passed to entry as a list of strings
+	for _, node := range statements {
+		if node.NodeType == parser.NT_FunctionDeclaration {
+			function := node.Value.(*parser.FunctionDeclareNode)
+
+			if function.Identifier == "entry" && len(function.Parameters) != 0 {
+				cg.addInstruction(VM.IT_PushArguments)
+			}
+		}
+	}
+
+	entryCall := len(cg.FunctionsInstructions)
 	cg.addInstruction(IGNORE_INSTRUCTION)
 
 	for _, node := range statements {
 		if node.NodeType == parser.NT_FunctionDeclaration {
 			cg.updateFileAndLine(node)
+			cg.isInEntry = node.Value.(*parser.FunctionDeclareNode).Identifier == "entry"
 
 			// Set first function call function id
-			if node.Value.(*parser.FunctionDeclareNode).Identifier == "entry" {
-				cg.FunctionsInstructions[0].InstructionType = VM.IT_Call
-				cg.FunctionsInstructions[0].InstructionValue = append(cg.FunctionsInstructions[0].InstructionValue, byte(len(cg.functions)))
+			if cg.isInEntry {
+				cg.FunctionsInstructions[entryCall].InstructionType = VM.IT_Call
+				cg.FunctionsInstructions[entryCall].InstructionValue = append(cg.FunctionsInstructions[entryCall].InstructionValue, byte(len(cg.functions)))
 			}
 
 			cg.generateFunction(node)
 		}
 	}
+	cg.isInEntry = false
 }
 
 func (cg *CodeGenerator) generateNode(node *parser.Node) {
@@ -258,12 +400,19 @@ func (cg *CodeGenerator) generateNode(node *parser.Node) {
 
 		// Drop unnamed scopes
 		currentScope := cg.scopes.Top
+		scopeCount := 0
 		for currentScope.Value.(*Scope).scopeType != ST_Function {
-			cg.addInstruction(VM.IT_PopScope)
+			scopeCount++
 			currentScope = currentScope.Previous
 		}
+		cg.dropScopes(scopeCount)
 
-		cg.addInstruction(VM.IT_Return)
+		// Value returned from entry is the exit code
+		if cg.isInEntry && node.Value != nil {
+			cg.addInstruction(VM.IT_Exit)
+		} else {
+			cg.addInstruction(VM.IT_Return)
+		}
 
 	// Scope
 	case parser.NT_Scope:
@@ -279,9 +428,7 @@ func (cg *CodeGenerator) generateNode(node *parser.Node) {
 	// Break
 	case parser.NT_Break:
 		// Generate scope drops
-		for i := 0; i < cg.scopes.Size-cg.loopScopeDepths.Top.Value.(int)+1; i++ {
-			cg.addInstruction(VM.IT_PopScope)
-		}
+		cg.dropScopes(cg.scopes.Size - cg.loopScopeDepths.Top.Value.(int) + 1)
 
 		// Generate jump
 		cg.addInstruction(VM.IT_Jump, 0)
@@ -289,6 +436,17 @@ func (cg *CodeGenerator) generateNode(node *parser.Node) {
 		// Store it so it's destination can be set at the end of the loop
 		cg.scopeBreaks.Top.Value = append(cg.scopeBreaks.Top.Value.([]Break), Break{&(*cg.target)[len(*cg.target)-1], len(*cg.target)})
 
+	// Continue
+	case parser.NT_Continue:
+		// Generate scope drops, loop scope is left by the loop itself
+		cg.dropScopes(cg.scopes.Size - cg.loopScopeDepths.Top.Value.(int))
+
+		// Generate jump
+		cg.addInstruction(VM.IT_Jump, 0)
+
+		// Store it so it's destination can be set at the end of the loop body
+		cg.scopeContinues.Top.Value = append(cg.scopeContinues.Top.Value.([]Break), Break{&(*cg.target)[len(*cg.target)-1], len(*cg.target)})
+
 	case parser.NT_ListAssign:
 		// Generate index expression
 		cg.generateExpression(node.Value.(*parser.ListAssignNode).IndexExpression)
@@ -307,6 +465,15 @@ func (cg *CodeGenerator) generateNode(node *parser.Node) {
 	case parser.NT_Match:
 		cg.generateMatch(node.Value.(*parser.MatchNode), false)
 
+	// Try
+	case parser.NT_Try:
+		cg.generateTry(node.Value.(*parser.TryNode))
+
+	// Throw
+	case parser.NT_Throw:
+		cg.generateExpression(node.Value.(*parser.Node))
+		cg.addInstruction(VM.IT_Throw)
+
 	default:
 		panic("Unknown node " + parser.NodeTypeToString[node.NodeType])
 	}
@@ -41,15 +41,18 @@ var comparisonOperatorToFloatInstruction = map[parser.NodeType]byte{
 }
 
 var dataTypeToDeclareInstruction = map[data.PrimitiveType]byte{
-	data.DT_Bool:   VM.IT_DeclareBool,
-	data.DT_Int:    VM.IT_DeclareInt,
-	data.DT_Float:  VM.IT_DeclareFloat,
-	data.DT_String: VM.IT_DeclareString,
-	data.DT_List:   VM.IT_DeclareList,
-	data.DT_Enum:   VM.IT_DeclareInt,
-	data.DT_Object: VM.IT_DeclareObject,
-	data.DT_Set:    VM.IT_DeclareSet,
-	data.DT_Option: VM.IT_DeclareOption,
+	data.DT_Bool:     VM.IT_DeclareBool,
+	data.DT_Int:      VM.IT_DeclareInt,
+	data.DT_Float:    VM.IT_DeclareFloat,
+	data.DT_String:   VM.IT_DeclareString,
+	data.DT_List:     VM.IT_DeclareList,
+	data.DT_Enum:     VM.IT_DeclareInt,
+	data.DT_Object:   VM.IT_DeclareObject,
+	data.DT_Set:      VM.IT_DeclareSet,
+	data.DT_Option:   VM.IT_DeclareOption,
+	data.DT_Map:      VM.IT_DeclareMap,
+	data.DT_Function: VM.IT_DeclareFunction,
+	data.DT_Generic:  VM.IT_DeclareAny,
 }
 
 func (cg *CodeGenerator) lineToInstruction(line byte) byte {
@@ -54,9 +54,12 @@ func (cg *CodeGenerator) generateDeletion(target *parser.Node) {
 			cg.generateExpression(inNode.Right) // Generate element
 
 			// Remove it
-			if inNode.Left.Value.(*parser.VariableNode).DataType.Type == data.DT_Set {
+			switch inNode.Left.Value.(*parser.VariableNode).DataType.Type {
+			case data.DT_Set:
 				cg.addInstruction(VM.IT_RemoveSetElement)
-			} else {
+			case data.DT_Map:
+				cg.addInstruction(VM.IT_RemoveMapElement)
+			default:
 				cg.addInstruction(VM.IT_RemoveListElement)
 			}
 
@@ -16,6 +16,10 @@ func (cg *CodeGenerator) generateExpression(node *parser.Node) {
 	case parser.NT_FunctionCall:
 		cg.generateFunctionCall(node)
 
+	// Function value
+	case parser.NT_Lambda, parser.NT_FunctionReference:
+		cg.generateClosure(node)
+
 	// Operators
 	case parser.NT_Add, parser.NT_Subtract, parser.NT_Multiply, parser.NT_Divide, parser.NT_Power, parser.NT_Modulo:
 		// Generate left side
@@ -124,12 +128,36 @@ func (cg *CodeGenerator) generateExpression(node *parser.Node) {
 		cg.generateExpression(node.Value.(*parser.TypedBinaryNode).Right)
 
 		// Generate indexing instruction
-		if parser.GetExpressionType(node.Value.(*parser.TypedBinaryNode).Left).Type == data.DT_String {
+		switch parser.GetExpressionType(node.Value.(*parser.TypedBinaryNode).Left).Type {
+		case data.DT_String:
 			cg.addInstruction(VM.IT_IndexString)
-		} else {
+		case data.DT_Map:
+			cg.addInstruction(VM.IT_IndexMap)
+		default:
 			cg.addInstruction(VM.IT_IndexList)
 		}
 
+	// Slices
+	case parser.NT_Slice:
+		sliceNode := node.Value.(*parser.SliceNode)
+		cg.generateExpression(sliceNode.Expression)
+
+		// Generate indexes, omitted indexes are none
+		for _, index := range []*parser.Node{sliceNode.Start, sliceNode.End} {
+			if index == nil {
+				cg.addInstruction(VM.IT_PushNone)
+			} else {
+				cg.generateExpression(index)
+			}
+		}
+
+		// Generate slicing instruction
+		if sliceNode.DataType.Type == data.DT_String {
+			cg.addInstruction(VM.IT_SliceString)
+		} else {
+			cg.addInstruction(VM.IT_SliceList)
+		}
+
 	// Logical not
 	case parser.NT_Not:
 		cg.generateExpression(node.Value.(*parser.TypedBinaryNode).Right)
@@ -139,6 +167,28 @@ func (cg *CodeGenerator) generateExpression(node *parser.Node) {
 	case parser.NT_Enum:
 		cg.addInstruction(VM.IT_LoadConst, uint8(cg.intConstants[node.Value.(*parser.EnumNode).Value]))
 
+	// Tagged union variants
+	case parser.NT_Variant:
+		variantNode := node.Value.(*parser.VariantNode)
+
+		// Create variant
+		cg.addInstruction(VM.IT_LoadConst, uint8(cg.intConstants[variantNode.Tag]))
+		cg.addInstruction(VM.IT_CreateVariant, byte(cg.stringConstants[variantNode.Identifier]))
+
+		// Generate payload
+		for _, payload := range variantNode.Payload {
+			cg.generateExpression(payload)
+			cg.addInstruction(VM.IT_AddPayload)
+		}
+
+	// Variant payloads
+	case parser.NT_VariantPayload:
+		variantPayloadNode := node.Value.(*parser.VariantPayloadNode)
+
+		cg.generateExpression(variantPayloadNode.Variant)
+
+		cg.addInstruction(VM.IT_GetPayloadAndPop, byte(variantPayloadNode.PayloadIndex))
+
 	// Objects
 	case parser.NT_Object:
 		ObjectNode := node.Value.(*parser.ObjectNode)
@@ -182,15 +232,54 @@ func (cg *CodeGenerator) generateExpression(node *parser.Node) {
 			cg.addInstruction(VM.IT_InsertToSet)
 		}
 
+	// Map literals
+	case parser.NT_Map:
+		cg.addInstruction(VM.IT_CreateMap)
+
+		mapNode := node.Value.(*parser.MapNode)
+
+		for i, key := range mapNode.Keys {
+			cg.generateExpression(key)
+			cg.generateExpression(mapNode.Values[i])
+			cg.addInstruction(VM.IT_InsertToMap)
+		}
+
 	// Set contains
 	case parser.NT_In:
 		binaryNode := node.Value.(*parser.TypedBinaryNode)
+
+		// Range is checked without creating it
+		if binaryNode.Right.NodeType == parser.NT_Range {
+			rangeNode := binaryNode.Right.Value.(*parser.RangeNode)
+
+			cg.generateExpression(binaryNode.Left)
+			cg.generateExpression(rangeNode.Start)
+			cg.generateExpression(rangeNode.End)
+
+			// Omitted step is none
+			if rangeNode.Step == nil {
+				cg.addInstruction(VM.IT_PushNone)
+			} else {
+				cg.generateExpression(rangeNode.Step)
+			}
+
+			if rangeNode.Inclusive {
+				cg.addInstruction(VM.IT_RangeContains, 1)
+			} else {
+				cg.addInstruction(VM.IT_RangeContains, 0)
+			}
+			break
+		}
+
 		cg.generateExpression(binaryNode.Right)
 		cg.generateExpression(binaryNode.Left)
 
-		if binaryNode.Right.GetNodeDataType().Type == data.DT_Set {
+		switch parser.GetExpressionType(binaryNode.Right).Type {
+		case data.DT_Set:
 			cg.addInstruction(VM.IT_SetContains)
-		} else {
+		case data.DT_Map:
+			cg.addInstruction(VM.IT_MapContains)
+		default:
 			cg.addInstruction(VM.IT_ListContains)
 		}
 
@@ -1,6 +1,9 @@
 package codeGenerator
 
 import (
+	"fmt"
+	"math"
+
 	"github.com/DanielNos/neco/parser"
 	VM "github.com/DanielNos/neco/virtualMachine"
 )
@@ -43,11 +46,58 @@ func (cg *CodeGenerator) generateFunction(functionNode *parser.Node) {
 	cg.addInstruction(VM.IT_Return)
 }
 
+func (cg *CodeGenerator) generateLambdas() {
+	// Lambdas can contain other lambdas, so the queue can grow while it's processed
+	for i := 0; i < len(cg.lambdas); i++ {
+		// Restore scopes surrounding the lambda
+		scopes := cg.lambdas[i].scopes
+		cg.scopes = &scopes
+
+		// Reset file so line of the lambda is generated again
+		cg.currentFile = ""
+		cg.updateFileAndLine(cg.lambdas[i].node)
+
+		cg.generateFunction(cg.lambdas[i].node)
+	}
+}
+
+func (cg *CodeGenerator) generateClosure(node *parser.Node) {
+	// Named function
+	if node.NodeType == parser.NT_FunctionReference {
+		cg.addInstruction(VM.IT_CreateClosure, byte(node.Value.(*parser.FunctionReferenceNode).Number))
+		return
+	}
+
+	// Lambda, it's body is generated later
+	function := cg.namedFunctionCount + len(cg.lambdas)
+
+	// Function index has to fit into one byte
+	if function > math.MaxUint8 {
+		cg.newError(fmt.Sprintf("Function overflow with %d functions. Program can only contain maximum of %d functions including lambdas.", function+1, math.MaxUint8+1))
+	}
+
+	cg.addInstruction(VM.IT_CreateClosure, byte(function))
+	cg.lambdas = append(cg.lambdas, Lambda{node, *cg.scopes})
+}
+
 func (cg *CodeGenerator) generateFunctionCall(node *parser.Node) {
-	// Generate arguments
 	functionCall := node.Value.(*parser.FunctionCallNode)
+
+	// Receiver of method is passed as the first argument
+	if functionCall.Receiver != nil {
+		cg.generateExpression(functionCall.Receiver)
+	}
+
+	// Generate arguments
 	cg.generateArguments(functionCall.Arguments)
 
+	// Call function value
+	if functionCall.Function != nil {
+		cg.generateExpression(functionCall.Function)
+		cg.addInstruction(VM.IT_CallValue)
+		return
+	}
+
 	// Call user defined function
 	if functionCall.Number != -1 {
 		cg.addInstruction(VM.IT_Call, byte(functionCall.Number))
@@ -72,16 +122,33 @@ func (cg *CodeGenerator) generateFunctionCall(node *parser.Node) {
 	// It's a built-in function
 	if exists {
 		cg.addInstruction(VM.IT_CallBuiltInFunc, builtInFunction)
+
+		// Modified list is stored back to it's variable
+		if _, isMutating := parser.MUTATING_BUILT_IN_FUNCTIONS[functionCall.Identifier]; isMutating {
+			cg.addInstruction(VM.IT_StoreAndPop, cg.findVariableIdentifier(functionCall.Arguments[0].Value.(*parser.VariableNode).Identifier))
+		}
 		// Function is exit()
 	} else if functionCall.Identifier == "exit" {
-		// Convert exit function to halt instruction
-		cg.addInstruction(VM.IT_Halt, byte(functionCall.Arguments[0].Value.(*parser.LiteralNode).Value.(int64)))
+		cg.generateExit(functionCall.Arguments[0])
 		// Unknown function
 	} else {
 		panic("Unknown function.")
 	}
 }
 
+func (cg *CodeGenerator) generateExit(exitCode *parser.Node) {
+	// Convert exit with literal exit code to halt instruction
+	if exitCode.NodeType == parser.NT_Literal {
+		if code := exitCode.Value.(*parser.LiteralNode).Value.(int64); code >= 0 && code <= math.MaxUint8 {
+			cg.addInstruction(VM.IT_Halt, byte(code))
+			return
+		}
+	}
+
+	// Computed exit code is checked at runtime
+	cg.addInstruction(VM.IT_Exit)
+}
+
 func (cg *CodeGenerator) generateArguments(arguments []*parser.Node) {
 	for _, argument := range arguments {
 		cg.generateExpression(argument)
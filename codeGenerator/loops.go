@@ -12,13 +12,17 @@ func (cg *CodeGenerator) generateLoop(node *parser.Node) {
 	// Enter scope
 	cg.enterScope(nil)
 
-	// Create break array and record loop scope
+	// Create break and continue arrays and record loop scope
 	cg.scopeBreaks.Push([]Break{})
+	cg.scopeContinues.Push([]Break{})
 	cg.loopScopeDepths.Push(cg.scopes.Size)
 
 	// Generate loop body
 	cg.generateStatements(node.Value.(*parser.ScopeNode))
 
+	// Continues jump to leaving of loop scope
+	cg.setContinueDestinations()
+
 	// Leave loop scope
 	cg.leaveScope()
 
@@ -46,8 +50,9 @@ func (cg *CodeGenerator) generateForLoop(node *parser.Node) {
 	// Enter scope
 	cg.enterScope(nil)
 
-	// Create break array and record loop scope
+	// Create break and continue arrays and record loop scope
 	cg.scopeBreaks.Push([]Break{})
+	cg.scopeContinues.Push([]Break{})
 	cg.loopScopeDepths.Push(cg.scopes.Size)
 
 	// Generate init statement
@@ -58,9 +63,23 @@ func (cg *CodeGenerator) generateForLoop(node *parser.Node) {
 	// Record start position of loop
 	startPosition := len(*cg.target) - 1
 
+	// Body has it's own scope, so every iteration has new variables that closures can capture
+	cg.enterScope(nil)
+
 	// Generate loop body
 	cg.generateStatements(forLoop.Body.Value.(*parser.ScopeNode))
 
+	// Leave body scope, continues drop it themselves
+	cg.leaveScope()
+
+	// Continues jump to step statement
+	cg.setContinueDestinations()
+
+	// Generate step statement
+	for _, node := range forLoop.StepStatement {
+		cg.generateNode(node)
+	}
+
 	// Generate line offset if line changed
 	if cg.currentLine(node) < node.Position.EndLine {
 		cg.addInstruction(VM.IT_LineOffset, byte(node.Position.EndLine-cg.currentLine(node)))
@@ -90,3 +109,11 @@ func (cg *CodeGenerator) generateForLoop(node *parser.Node) {
 	}
 	cg.loopScopeDepths.Pop()
 }
+
+func (cg *CodeGenerator) setContinueDestinations() {
+	instructionCount := len(*cg.target)
+
+	for _, c := range cg.scopeContinues.Pop().([]Break) {
+		updateJumpDistance(c.instruction, instructionCount-c.instructionPosition, VM.IT_JumpEx)
+	}
+}
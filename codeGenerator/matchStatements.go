@@ -22,8 +22,14 @@ func (cg *CodeGenerator) generateMatch(matchNode *parser.MatchNode, isExpression
 			if caseIndex < len(matchNode.Cases)-1 || expressionIndex < len(caseNode.Expressions)-1 {
 				cg.addInstruction(VM.IT_DuplicateTop)
 			}
-			cg.generateExpression(expression)
-			cg.addInstruction(VM.IT_Equal)
+
+			// Variant patterns test tag of matched tagged union
+			if expression.NodeType == parser.NT_Variant {
+				cg.addInstruction(VM.IT_TestVariant, byte(cg.intConstants[expression.Value.(*parser.VariantNode).Tag]))
+			} else {
+				cg.generateExpression(expression)
+				cg.addInstruction(VM.IT_Equal)
+			}
 
 			// Generate conditional jump
 			cg.addInstruction(VM.IT_JumpIfTrue, 0)
@@ -53,6 +59,14 @@ func (cg *CodeGenerator) generateMatch(matchNode *parser.MatchNode, isExpression
 			jumpIndex++
 		}
 
+		// Generate payload bindings in case scope
+		if len(caseNode.Bindings) != 0 {
+			cg.enterScope(nil)
+			for _, binding := range caseNode.Bindings {
+				cg.generateNode(binding)
+			}
+		}
+
 		// Generate case body/expression
 		if isExpression {
 			cg.generateExpression(matchCase.Value.(*parser.CaseNode).Statement)
@@ -60,6 +74,10 @@ func (cg *CodeGenerator) generateMatch(matchNode *parser.MatchNode, isExpression
 			cg.generateNode(matchCase.Value.(*parser.CaseNode).Statement)
 		}
 
+		if len(caseNode.Bindings) != 0 {
+			cg.leaveScope()
+		}
+
 		// Generate jump instruction to the end of case bodies
 		cg.addInstruction(VM.IT_Jump, 0)
 
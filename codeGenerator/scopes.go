@@ -29,6 +29,20 @@ func (cg *CodeGenerator) enterScope(name *string) {
 	}
 }
 
+// Generates instructions that leave count innermost scopes. Leaving a try scope also removes it's error handler.
+func (cg *CodeGenerator) dropScopes(count int) {
+	currentScope := cg.scopes.Top
+
+	for i := 0; i < count; i++ {
+		if currentScope.Value.(*Scope).scopeType == ST_Try {
+			cg.addInstruction(VM.IT_PopHandler)
+		}
+		cg.addInstruction(VM.IT_PopScope)
+
+		currentScope = currentScope.Previous
+	}
+}
+
 func (cg *CodeGenerator) leaveScope() {
 	cg.addInstruction(VM.IT_PopScope)
 	cg.scopes.Pop()
@@ -0,0 +1,55 @@
+package codeGenerator
+
+import (
+	data "github.com/DanielNos/neco/dataStructures"
+	"github.com/DanielNos/neco/parser"
+	VM "github.com/DanielNos/neco/virtualMachine"
+)
+
+func (cg *CodeGenerator) generateTry(tryNode *parser.TryNode) {
+	// Push error handler, catch block starts after the following jump
+	cg.addInstruction(VM.IT_PushHandler)
+
+	// Generate jump over catch block
+	cg.addInstruction(VM.IT_Jump, 0)
+	jumpOverCatch := &(*cg.target)[len(*cg.target)-1]
+	jumpOverCatchPosition := len(*cg.target)
+
+	// Generate catch block, thrown error is on top of the stack
+	cg.enterScope(nil)
+
+	if tryNode.ErrorIdentifier != "" {
+		// Declare error variable and store error in it
+		id := cg.scopes.Top.Value.(*Scope).variableIdentifierCounter
+		cg.scopes.Top.Value.(*Scope).variableIdentifiers[tryNode.ErrorIdentifier] = id
+
+		cg.generateVariableDeclarator(&data.DataType{data.DT_Object, "Error"}, &id)
+		cg.scopes.Top.Value.(*Scope).variableIdentifierCounter++
+
+		cg.addInstruction(VM.IT_StoreAndPop, id)
+	} else {
+		cg.addInstruction(VM.IT_Pop)
+	}
+
+	cg.generateStatements(tryNode.CatchBody.Value.(*parser.ScopeNode))
+	cg.leaveScope()
+
+	// Generate jump over try body
+	cg.addInstruction(VM.IT_Jump, 0)
+	jumpOverTry := &(*cg.target)[len(*cg.target)-1]
+	jumpOverTryPosition := len(*cg.target)
+
+	updateJumpDistance(jumpOverCatch, len(*cg.target)-jumpOverCatchPosition, VM.IT_JumpEx)
+
+	// Generate try body
+	cg.addInstruction(VM.IT_PushScopeUnnamed)
+	cg.pushScope(ST_Try)
+
+	cg.generateStatements(tryNode.Body.Value.(*parser.ScopeNode))
+
+	// Leave try body and remove it's handler
+	cg.leaveScope()
+	cg.addInstruction(VM.IT_PopHandler)
+
+	updateJumpDistance(jumpOverTry, len(*cg.target)-jumpOverTryPosition, VM.IT_JumpEx)
+}
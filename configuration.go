@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/DanielNos/neco/errors"
+	"github.com/DanielNos/neco/linter"
 	"github.com/DanielNos/neco/logger"
 )
 
@@ -18,6 +19,11 @@ const (
 	A_Run
 	A_Analyze
 	A_BuildAndRun
+	A_Repl
+	A_Lsp
+	A_Fmt
+	A_Lint
+	A_Disasm
 )
 
 type Configuration struct {
@@ -27,10 +33,17 @@ type Configuration struct {
 	Optimize          bool
 	Silent            bool
 	PrintConstants    bool
+	CheckFormat       bool
+	WriteFormat       bool
 
-	Action     Action
-	TargetPath string
-	OutputPath string
+	Action       Action
+	TargetPath   string
+	OutputPath   string
+	IncludePaths []string
+	FormatPaths  []string       // Files and directories formatted by action fmt
+	Linter       *linter.Linter // Rules checked by action lint
+
+	ProgramArguments []string // Arguments passed to entry() of the executed program
 }
 
 func processArguments() *Configuration {
@@ -64,6 +77,45 @@ func processArguments() *Configuration {
 			configuration.Action = A_Analyze
 		}
 
+	case "repl":
+		configuration.Action = A_Repl
+
+		if len(args) > 1 {
+			logger.Fatal(errors.INVALID_FLAGS, "Invalid flag \""+args[1]+"\" for action repl.")
+		}
+
+	case "lsp":
+		configuration.Action = A_Lsp
+
+	case "fmt":
+		configuration.Action = A_Fmt
+
+	case "lint":
+		if len(args) == 1 {
+			logger.Fatal(errors.INVALID_FLAGS, "No target specified.")
+		}
+
+		if args[1] == "--rules" {
+			printLintRules()
+			os.Exit(0)
+		}
+
+		configuration.Action = A_Lint
+		configuration.TargetPath = args[1]
+		configuration.Linter = linter.NewLinter()
+
+	case "disasm":
+		configuration.Action = A_Disasm
+
+		if len(args) == 1 {
+			logger.Fatal(errors.INVALID_FLAGS, "No target specified.")
+		}
+		configuration.TargetPath = args[1]
+
+		if len(args) > 2 {
+			logger.Fatal(errors.INVALID_FLAGS, "Invalid flag \""+args[2]+"\" for action disasm.")
+		}
+
 	case "help", "--help", "-h":
 		printHelp()
 		os.Exit(0)
@@ -106,31 +158,7 @@ func processArguments() *Configuration {
 				logger.LoggingLevel = logger.LL_NoLog
 
 			case "--log-level", "-l":
-				if i+1 == len(args) {
-					logger.Fatal(errors.INVALID_FLAGS, "No logging level provided after "+args[i]+" flag.")
-				}
-				i++
-
-				// Try to get logging level from name
-				level, isName := logger.StringToLogLevel[args[i]]
-
-				if isName {
-					logger.LoggingLevel = level
-					continue
-				}
-
-				// Get logging level from number
-				loggingLevel, err := strconv.Atoi(args[i])
-
-				if err != nil {
-					logger.Fatal(errors.INVALID_FLAGS, "Logging level has to be a number.")
-				}
-
-				if loggingLevel < 0 || loggingLevel > 5 {
-					logger.Fatal(errors.INVALID_FLAGS, "Invalid logging level "+fmt.Sprintf("%d.", loggingLevel))
-				}
-
-				logger.LoggingLevel = byte(loggingLevel)
+				logger.LoggingLevel = collectLogLevel(args, &i)
 
 			case "--out", "-o":
 				if i+1 == len(args) {
@@ -143,22 +171,29 @@ func processArguments() *Configuration {
 			case "--constants", "-c":
 				configuration.PrintConstants = true
 
+			case "--include", "-I":
+				configuration.IncludePaths = append(configuration.IncludePaths, collectIncludePath(args, &i))
+
+			// Rest of arguments is passed to the program
+			case "--":
+				configuration.ProgramArguments = args[i+1:]
+				i = len(args)
+
 			default:
 				logger.Fatal(errors.INVALID_FLAGS, "Invalid flag \""+args[i]+"\" for action build.")
 			}
 		}
-	// Run flags
+	// Run passes all arguments after target to the program
 	case A_Run:
-		for _, flag := range args[argumentsStart:] {
-			switch flag {
-			default:
-				logger.Fatal(errors.INVALID_FLAGS, "Invalid flag \""+flag+"\" for action run.")
-			}
+		configuration.ProgramArguments = args[argumentsStart:]
+
+		if len(configuration.ProgramArguments) != 0 && configuration.ProgramArguments[0] == "--" {
+			configuration.ProgramArguments = configuration.ProgramArguments[1:]
 		}
 	// Analyze flags
 	case A_Analyze:
-		for _, flag := range args[2:] {
-			switch flag {
+		for i := 2; i < len(args); i++ {
+			switch args[i] {
 			case "--tokens", "-to":
 				configuration.PrintTokens = true
 
@@ -168,8 +203,79 @@ func processArguments() *Configuration {
 			case "--dont-optimize", "-d":
 				configuration.Optimize = false
 
+			case "--include", "-I":
+				configuration.IncludePaths = append(configuration.IncludePaths, collectIncludePath(args, &i))
+
+			default:
+				logger.Fatal(errors.INVALID_FLAGS, "Invalid flag \""+args[i]+"\" for action analyze.")
+			}
+		}
+	// Language server flags
+	case A_Lsp:
+		for i := 1; i < len(args); i++ {
+			switch args[i] {
+			case "--include", "-I":
+				configuration.IncludePaths = append(configuration.IncludePaths, collectIncludePath(args, &i))
+
+			// Standard input and output are the only supported transport
+			case "--stdio":
+
 			default:
-				logger.Fatal(errors.INVALID_FLAGS, "Invalid flag \""+flag+"\" for action analyze.")
+				logger.Fatal(errors.INVALID_FLAGS, "Invalid flag \""+args[i]+"\" for action lsp.")
+			}
+		}
+	// Format flags
+	case A_Fmt:
+		for i := 1; i < len(args); i++ {
+			switch args[i] {
+			case "--check":
+				configuration.CheckFormat = true
+
+			case "--write", "-w":
+				configuration.WriteFormat = true
+
+			case "--include", "-I":
+				configuration.IncludePaths = append(configuration.IncludePaths, collectIncludePath(args, &i))
+
+			default:
+				if strings.HasPrefix(args[i], "-") {
+					logger.Fatal(errors.INVALID_FLAGS, "Invalid flag \""+args[i]+"\" for action fmt.")
+				}
+				configuration.FormatPaths = append(configuration.FormatPaths, args[i])
+			}
+		}
+
+		if len(configuration.FormatPaths) == 0 {
+			logger.Fatal(errors.INVALID_FLAGS, "No target specified.")
+		}
+
+		if configuration.CheckFormat && configuration.WriteFormat {
+			logger.Fatal(errors.INVALID_FLAGS, "Flags --check and --write can't be used together.")
+		}
+	// Lint flags
+	case A_Lint:
+		for i := 2; i < len(args); i++ {
+			switch args[i] {
+			case "--enable", "-e":
+				collectLintRules(args, &i, configuration.Linter, true)
+
+			case "--disable", "-d":
+				collectLintRules(args, &i, configuration.Linter, false)
+
+			case "--silent", "-s":
+				logger.LoggingLevel = logger.LL_Error
+
+			case "--no-log", "-n":
+				logger.LoggingLevel = logger.LL_NoLog
+
+			case "--log-level", "-l":
+				logger.LoggingLevel = collectLogLevel(args, &i)
+
+			case "--include", "-I":
+				configuration.IncludePaths = append(configuration.IncludePaths, collectIncludePath(args, &i))
+
+			default:
+				logger.Fatal(errors.INVALID_FLAGS, "Invalid flag \""+args[i]+"\" for action lint.")
 			}
 		}
 	}
@@ -191,3 +297,53 @@ func processArguments() *Configuration {
 
 	return configuration
 }
+
+func collectIncludePath(args []string, i *int) string {
+	if *i+1 == len(args) {
+		logger.Fatal(errors.INVALID_FLAGS, "No include path provided after "+args[*i]+" flag.")
+	}
+	*i++
+
+	return args[*i]
+}
+
+func collectLogLevel(args []string, i *int) byte {
+	if *i+1 == len(args) {
+		logger.Fatal(errors.INVALID_FLAGS, "No logging level provided after "+args[*i]+" flag.")
+	}
+	*i++
+
+	// Try to get logging level from name
+	level, isName := logger.StringToLogLevel[args[*i]]
+
+	if isName {
+		return level
+	}
+
+	// Get logging level from number
+	loggingLevel, err := strconv.Atoi(args[*i])
+
+	if err != nil {
+		logger.Fatal(errors.INVALID_FLAGS, "Logging level has to be a number.")
+	}
+
+	if loggingLevel < 0 || loggingLevel > 5 {
+		logger.Fatal(errors.INVALID_FLAGS, "Invalid logging level "+fmt.Sprintf("%d.", loggingLevel))
+	}
+
+	return byte(loggingLevel)
+}
+
+// Enables or disables comma separated rules of linter.
+func collectLintRules(args []string, i *int, rules *linter.Linter, enabled bool) {
+	if *i+1 == len(args) {
+		logger.Fatal(errors.INVALID_FLAGS, "No rules provided after "+args[*i]+" flag.")
+	}
+	*i++
+
+	for _, rule := range strings.Split(args[*i], ",") {
+		if !rules.SetEnabled(strings.TrimSpace(rule), enabled) {
+			logger.Fatal(errors.INVALID_FLAGS, "Unknown lint rule \""+rule+"\". Use neco lint --rules to list them.")
+		}
+	}
+}
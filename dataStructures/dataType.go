@@ -14,7 +14,10 @@ const (
 	DT_Object
 	DT_List
 	DT_Set
+	DT_Map
 	DT_Option
+	DT_Function
+	DT_Generic
 )
 
 func (pt PrimitiveType) String() string {
@@ -41,8 +44,14 @@ func (pt PrimitiveType) String() string {
 		return "list"
 	case DT_Set:
 		return "set"
+	case DT_Map:
+		return "map"
 	case DT_Option:
 		return "opt"
+	case DT_Function:
+		return "fun"
+	case DT_Generic:
+		return "generic"
 	}
 
 	return "[INVALID DATA TYPE]"
@@ -53,6 +62,24 @@ type DataType struct {
 	SubType any
 }
 
+// Sub-type of maps
+type MapType struct {
+	Key   *DataType
+	Value *DataType
+}
+
+// Sub-type of objects of generic structs
+type ObjectType struct {
+	Name          string
+	TypeArguments []*DataType
+}
+
+// Sub-type of functions. Return type is nil if function doesn't return anything.
+type FunctionType struct {
+	Parameters []*DataType
+	ReturnType *DataType
+}
+
 func (dt *DataType) CanBeAssigned(other *DataType) bool {
 	// No type can't equal any other type
 	if dt.Type == DT_Unknown || other.Type == DT_Unknown {
@@ -74,8 +101,13 @@ func (dt *DataType) CanBeAssigned(other *DataType) bool {
 		return dt.SubType.(*DataType).CanBeAssigned(other.SubType.(*DataType))
 	}
 
-	// Compare struct names
+	// Compare struct names and type arguments
 	if dt.Type == DT_Object && other.Type == DT_Object {
+		return dt.Equals(other)
+	}
+
+	// Compare type parameter names
+	if dt.Type == DT_Generic && other.Type == DT_Generic {
 		return dt.SubType == other.SubType
 	}
 
@@ -92,13 +124,42 @@ func (dt *DataType) CanBeAssigned(other *DataType) bool {
 		return dt.SubType.(*DataType).CanBeAssigned(other.SubType.(*DataType))
 	}
 
+	// Compare maps
+	if dt.Type == DT_Map && other.Type == DT_Map {
+		mapType, otherMapType := dt.SubType.(*MapType), other.SubType.(*MapType)
+		return mapType.Key.CanBeAssigned(otherMapType.Key) && mapType.Value.CanBeAssigned(otherMapType.Value)
+	}
+
+	// Compare functions
+	if dt.Type == DT_Function && other.Type == DT_Function {
+		functionType, otherFunctionType := dt.SubType.(*FunctionType), other.SubType.(*FunctionType)
+
+		// Parameters have to be the same
+		if len(functionType.Parameters) != len(otherFunctionType.Parameters) {
+			return false
+		}
+
+		for i, parameter := range functionType.Parameters {
+			if !parameter.Equals(otherFunctionType.Parameters[i]) {
+				return false
+			}
+		}
+
+		// Compare return types
+		if functionType.ReturnType == nil || otherFunctionType.ReturnType == nil {
+			return functionType.ReturnType == otherFunctionType.ReturnType
+		}
+
+		return functionType.ReturnType.CanBeAssigned(otherFunctionType.ReturnType)
+	}
+
 	// Compare options/nones
 	if dt.Type == DT_Option {
 		if other.Type == DT_None {
 			return true
 		}
 
-		if other.IsCompositeType() {
+		if other.Type == DT_Option {
 			return dt.SubType.(*DataType).CanBeAssigned(other.SubType.(*DataType))
 		}
 
@@ -120,9 +181,22 @@ func (dt *DataType) Equals(other *DataType) bool {
 
 	if dt.Type <= DT_None {
 		return true
-		// User defined types
-	} else if dt.Type <= DT_Object {
+		// Objects of generic structs
+	} else if dt.Type == DT_Object && (isObjectType(dt.SubType) || isObjectType(other.SubType)) {
+		if !isObjectType(dt.SubType) || !isObjectType(other.SubType) {
+			return false
+		}
+		return dt.SubType.(*ObjectType).Equals(other.SubType.(*ObjectType))
+		// User defined types and type parameters
+	} else if dt.Type <= DT_Object || dt.Type == DT_Generic {
 		return dt.SubType == other.SubType
+		// Maps
+	} else if dt.Type == DT_Map {
+		mapType, otherMapType := dt.SubType.(*MapType), other.SubType.(*MapType)
+		return mapType.Key.Equals(otherMapType.Key) && mapType.Value.Equals(otherMapType.Value)
+		// Functions
+	} else if dt.Type == DT_Function {
+		return dt.SubType.(*FunctionType).Equals(other.SubType.(*FunctionType))
 		// Composite types
 	} else {
 		return dt.SubType.(*DataType).Equals(other.SubType.(*DataType))
@@ -130,18 +204,44 @@ func (dt *DataType) Equals(other *DataType) bool {
 }
 
 func (dt *DataType) IsComplete() bool {
-	if dt.SubType == nil || dt.Type == DT_Enum || dt.Type == DT_Object {
+	if dt.SubType == nil || dt.Type == DT_Enum || dt.Type == DT_Object || dt.Type == DT_Generic {
 		return dt.Type != DT_Unknown
 	}
 
+	if dt.Type == DT_Map {
+		return dt.SubType.(*MapType).Key.IsComplete() && dt.SubType.(*MapType).Value.IsComplete()
+	}
+
+	if dt.Type == DT_Function {
+		for _, parameter := range dt.SubType.(*FunctionType).Parameters {
+			if !parameter.IsComplete() {
+				return false
+			}
+		}
+
+		return dt.SubType.(*FunctionType).ReturnType == nil || dt.SubType.(*FunctionType).ReturnType.IsComplete()
+	}
+
 	return dt.SubType.(*DataType).IsComplete()
 }
 
 func (dt *DataType) Copy() *DataType {
-	if dt.Type <= DT_Object {
+	if dt.Type == DT_Object && isObjectType(dt.SubType) {
+		return &DataType{DT_Object, dt.SubType.(*ObjectType).Copy()}
+	}
+
+	if dt.Type <= DT_Object || dt.Type == DT_Generic {
 		return &DataType{dt.Type, dt.SubType}
 	}
 
+	if dt.Type == DT_Map {
+		return &DataType{DT_Map, &MapType{dt.SubType.(*MapType).Key.Copy(), dt.SubType.(*MapType).Value.Copy()}}
+	}
+
+	if dt.Type == DT_Function {
+		return &DataType{DT_Function, dt.SubType.(*FunctionType).Copy()}
+	}
+
 	return &DataType{dt.Type, dt.SubType.(*DataType).Copy()}
 }
 
@@ -152,6 +252,13 @@ func (dt *DataType) TryCompleteFrom(from *DataType) {
 		return
 	}
 
+	// Complete key and value types of maps
+	if dt.Type == DT_Map && from.Type == DT_Map {
+		dt.SubType.(*MapType).Key.TryCompleteFrom(from.SubType.(*MapType).Key)
+		dt.SubType.(*MapType).Value.TryCompleteFrom(from.SubType.(*MapType).Value)
+		return
+	}
+
 	if dt.IsCompositeType() && dt.SubType != nil {
 		dt.SubType.(*DataType).TryCompleteFrom(from.SubType.(*DataType))
 	} else {
@@ -161,6 +268,10 @@ func (dt *DataType) TryCompleteFrom(from *DataType) {
 }
 
 func (dt *DataType) GetDepth() int {
+	if dt.Type == DT_Map {
+		return 1 + dt.SubType.(*MapType).Value.GetDepth()
+	}
+
 	if dt.IsCompositeType() {
 		return 1 + dt.SubType.(*DataType).GetDepth()
 	}
@@ -169,6 +280,10 @@ func (dt *DataType) GetDepth() int {
 }
 
 func (dt *DataType) GetLeafType() *DataType {
+	if dt.Type == DT_Map {
+		return dt.SubType.(*MapType).Value.GetLeafType()
+	}
+
 	if dt.IsCompositeType() {
 		return dt.SubType.(*DataType).GetLeafType()
 	}
@@ -177,7 +292,9 @@ func (dt *DataType) GetLeafType() *DataType {
 }
 
 func (dt *DataType) SetLeafType(dataType *DataType) {
-	if dt.IsCompositeType() {
+	if dt.Type == DT_Map {
+		dt.SubType.(*MapType).Value.SetLeafType(dataType)
+	} else if dt.IsCompositeType() {
 		dt.SubType.(*DataType).SetLeafType(dataType)
 	} else {
 		*dt = *dataType
@@ -197,6 +314,10 @@ func (dt *DataType) String() string {
 		if dt.SubType == nil {
 			return "any"
 		}
+
+		if isObjectType(dt.SubType) {
+			return dt.SubType.(*ObjectType).String()
+		}
 		return dt.SubType.(string)
 		// Composite types
 	} else if dt.Type <= DT_Set {
@@ -204,9 +325,18 @@ func (dt *DataType) String() string {
 			return dt.Type.String() + "<?>"
 		}
 		return dt.Type.String() + "<" + dt.SubType.(*DataType).String() + ">"
+		// Map type
+	} else if dt.Type == DT_Map {
+		return "map<" + dt.SubType.(*MapType).Key.String() + ", " + dt.SubType.(*MapType).Value.String() + ">"
 		// Option type
-	} else {
+	} else if dt.Type == DT_Option {
 		return dt.SubType.(*DataType).String() + "?"
+		// Function type
+	} else if dt.Type == DT_Function {
+		return dt.SubType.(*FunctionType).String()
+		// Type parameter
+	} else {
+		return dt.SubType.(string)
 	}
 }
 
@@ -215,9 +345,9 @@ func (dt *DataType) Signature() string {
 	if dt.Type <= DT_None {
 		return dt.Type.String()
 	} else if dt.Type == DT_Enum {
-		return "enum" + dt.SubType.(string)
-	} else if dt.Type == DT_Object {
-		return dt.SubType.(string)
+		return "enum:" + dt.SubType.(string)
+	} else if dt.Type == DT_Object || dt.Type == DT_Map || dt.Type == DT_Function || dt.Type == DT_Generic {
+		return dt.String()
 	} else {
 		return dt.Type.String() + "<" + dt.SubType.(*DataType).String() + ">"
 	}
@@ -229,9 +359,315 @@ func (dt *DataType) FunctionCompareSignature() string {
 		return dt.Type.String()
 	} else if dt.Type == DT_Enum {
 		return "enum"
-	} else if dt.Type == DT_Object {
-		return dt.SubType.(string)
+	} else if dt.Type == DT_Object || dt.Type == DT_Map || dt.Type == DT_Function || dt.Type == DT_Generic {
+		return dt.String()
 	} else {
 		return dt.Type.String() + "<" + dt.SubType.(*DataType).String() + ">"
 	}
 }
+
+func (ft *FunctionType) Equals(other *FunctionType) bool {
+	if len(ft.Parameters) != len(other.Parameters) {
+		return false
+	}
+
+	for i, parameter := range ft.Parameters {
+		if !parameter.Equals(other.Parameters[i]) {
+			return false
+		}
+	}
+
+	if ft.ReturnType == nil || other.ReturnType == nil {
+		return ft.ReturnType == other.ReturnType
+	}
+
+	return ft.ReturnType.Equals(other.ReturnType)
+}
+
+func (ft *FunctionType) Copy() *FunctionType {
+	parameters := make([]*DataType, len(ft.Parameters))
+	for i, parameter := range ft.Parameters {
+		parameters[i] = parameter.Copy()
+	}
+
+	if ft.ReturnType == nil {
+		return &FunctionType{parameters, nil}
+	}
+
+	return &FunctionType{parameters, ft.ReturnType.Copy()}
+}
+
+func (ft *FunctionType) String() string {
+	signature := "fun("
+
+	for i, parameter := range ft.Parameters {
+		if i != 0 {
+			signature += ", "
+		}
+		signature += parameter.String()
+	}
+	signature += ")"
+
+	if ft.ReturnType != nil {
+		signature += " -> " + ft.ReturnType.String()
+	}
+
+	return signature
+}
+
+// Returns name of struct of an object type.
+func (dt *DataType) StructName() string {
+	if isObjectType(dt.SubType) {
+		return dt.SubType.(*ObjectType).Name
+	}
+
+	return dt.SubType.(string)
+}
+
+// Checks if type contains any type parameters.
+func (dt *DataType) IsGeneric() bool {
+	switch dt.Type {
+	case DT_Generic:
+		return true
+
+	case DT_List, DT_Set, DT_Option:
+		return dt.SubType != nil && dt.SubType.(*DataType).IsGeneric()
+
+	case DT_Map:
+		return dt.SubType.(*MapType).Key.IsGeneric() || dt.SubType.(*MapType).Value.IsGeneric()
+
+	case DT_Object:
+		if isObjectType(dt.SubType) {
+			for _, argument := range dt.SubType.(*ObjectType).TypeArguments {
+				if argument.IsGeneric() {
+					return true
+				}
+			}
+		}
+
+	case DT_Function:
+		for _, parameter := range dt.SubType.(*FunctionType).Parameters {
+			if parameter.IsGeneric() {
+				return true
+			}
+		}
+
+		return dt.SubType.(*FunctionType).ReturnType != nil && dt.SubType.(*FunctionType).ReturnType.IsGeneric()
+	}
+
+	return false
+}
+
+// Creates a copy of the type with type parameters replaced by their type arguments. Type parameters without an argument are kept.
+func (dt *DataType) Substitute(typeArguments map[string]*DataType) *DataType {
+	switch dt.Type {
+	case DT_Generic:
+		argument, exists := typeArguments[dt.SubType.(string)]
+		if exists {
+			return argument.Copy()
+		}
+
+	case DT_List, DT_Set, DT_Option:
+		if dt.SubType != nil {
+			return &DataType{dt.Type, dt.SubType.(*DataType).Substitute(typeArguments)}
+		}
+
+	case DT_Map:
+		return &DataType{DT_Map, &MapType{dt.SubType.(*MapType).Key.Substitute(typeArguments), dt.SubType.(*MapType).Value.Substitute(typeArguments)}}
+
+	case DT_Object:
+		if isObjectType(dt.SubType) {
+			objectType := dt.SubType.(*ObjectType)
+			arguments := make([]*DataType, len(objectType.TypeArguments))
+
+			for i, argument := range objectType.TypeArguments {
+				arguments[i] = argument.Substitute(typeArguments)
+			}
+
+			return &DataType{DT_Object, &ObjectType{objectType.Name, arguments}}
+		}
+
+	case DT_Function:
+		functionType := dt.SubType.(*FunctionType)
+		parameters := make([]*DataType, len(functionType.Parameters))
+
+		for i, parameter := range functionType.Parameters {
+			parameters[i] = parameter.Substitute(typeArguments)
+		}
+
+		if functionType.ReturnType == nil {
+			return &DataType{DT_Function, &FunctionType{parameters, nil}}
+		}
+
+		return &DataType{DT_Function, &FunctionType{parameters, functionType.ReturnType.Substitute(typeArguments)}}
+	}
+
+	return dt.Copy()
+}
+
+// Matches type containing type parameters to other type and collects arguments of the type parameters.
+// Returns false if the other type can't be assigned to this type.
+func (dt *DataType) InferTypeArguments(other *DataType, typeArguments map[string]*DataType) bool {
+	// Types without type parameters are compared directly
+	if !dt.IsGeneric() {
+		return dt.CanBeAssigned(other)
+	}
+
+	switch dt.Type {
+	case DT_Generic:
+		// Type parameter already has an argument
+		argument, exists := typeArguments[dt.SubType.(string)]
+		if exists {
+			return argument.CanBeAssigned(other)
+		}
+
+		// Nothing can be inferred from unknown types and nones
+		if other.Type == DT_Unknown || other.Type == DT_None {
+			return other.Type == DT_None
+		}
+
+		typeArguments[dt.SubType.(string)] = other
+		return true
+
+	case DT_List, DT_Set:
+		if dt.SubType != nil && other.Type == dt.Type && other.SubType != nil {
+			return dt.SubType.(*DataType).InferTypeArguments(other.SubType.(*DataType), typeArguments)
+		}
+
+	case DT_Option:
+		if other.Type == DT_None {
+			return true
+		}
+
+		if other.Type == DT_Option {
+			return dt.SubType.(*DataType).InferTypeArguments(other.SubType.(*DataType), typeArguments)
+		}
+
+		return dt.SubType.(*DataType).InferTypeArguments(other, typeArguments)
+
+	case DT_Map:
+		if other.Type == DT_Map {
+			mapType, otherMapType := dt.SubType.(*MapType), other.SubType.(*MapType)
+			return mapType.Key.InferTypeArguments(otherMapType.Key, typeArguments) && mapType.Value.InferTypeArguments(otherMapType.Value, typeArguments)
+		}
+
+	case DT_Object:
+		if isObjectType(dt.SubType) && other.Type == DT_Object && isObjectType(other.SubType) {
+			objectType, otherObjectType := dt.SubType.(*ObjectType), other.SubType.(*ObjectType)
+
+			if objectType.Name != otherObjectType.Name || len(objectType.TypeArguments) != len(otherObjectType.TypeArguments) {
+				return false
+			}
+
+			for i, argument := range objectType.TypeArguments {
+				if !argument.InferTypeArguments(otherObjectType.TypeArguments[i], typeArguments) {
+					return false
+				}
+			}
+
+			return true
+		}
+
+	case DT_Function:
+		if other.Type == DT_Function {
+			functionType, otherFunctionType := dt.SubType.(*FunctionType), other.SubType.(*FunctionType)
+
+			if len(functionType.Parameters) != len(otherFunctionType.Parameters) {
+				return false
+			}
+
+			for i, parameter := range functionType.Parameters {
+				if !parameter.InferTypeArguments(otherFunctionType.Parameters[i], typeArguments) {
+					return false
+				}
+			}
+
+			if functionType.ReturnType == nil || otherFunctionType.ReturnType == nil {
+				return functionType.ReturnType == otherFunctionType.ReturnType
+			}
+
+			return functionType.ReturnType.InferTypeArguments(otherFunctionType.ReturnType, typeArguments)
+		}
+	}
+
+	return false
+}
+
+// Collects names of type parameters contained in the type in order of their appearance.
+func (dt *DataType) CollectTypeParameters(names *[]string) {
+	switch dt.Type {
+	case DT_Generic:
+		for _, name := range *names {
+			if name == dt.SubType.(string) {
+				return
+			}
+		}
+		*names = append(*names, dt.SubType.(string))
+
+	case DT_List, DT_Set, DT_Option:
+		if dt.SubType != nil {
+			dt.SubType.(*DataType).CollectTypeParameters(names)
+		}
+
+	case DT_Map:
+		dt.SubType.(*MapType).Key.CollectTypeParameters(names)
+		dt.SubType.(*MapType).Value.CollectTypeParameters(names)
+
+	case DT_Object:
+		if isObjectType(dt.SubType) {
+			for _, argument := range dt.SubType.(*ObjectType).TypeArguments {
+				argument.CollectTypeParameters(names)
+			}
+		}
+
+	case DT_Function:
+		for _, parameter := range dt.SubType.(*FunctionType).Parameters {
+			parameter.CollectTypeParameters(names)
+		}
+
+		if dt.SubType.(*FunctionType).ReturnType != nil {
+			dt.SubType.(*FunctionType).ReturnType.CollectTypeParameters(names)
+		}
+	}
+}
+
+func isObjectType(subType any) bool {
+	_, isObjectType := subType.(*ObjectType)
+	return isObjectType
+}
+
+func (ot *ObjectType) Equals(other *ObjectType) bool {
+	if ot.Name != other.Name || len(ot.TypeArguments) != len(other.TypeArguments) {
+		return false
+	}
+
+	for i, argument := range ot.TypeArguments {
+		if !argument.Equals(other.TypeArguments[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (ot *ObjectType) Copy() *ObjectType {
+	arguments := make([]*DataType, len(ot.TypeArguments))
+	for i, argument := range ot.TypeArguments {
+		arguments[i] = argument.Copy()
+	}
+
+	return &ObjectType{ot.Name, arguments}
+}
+
+func (ot *ObjectType) String() string {
+	signature := ot.Name + "<"
+
+	for i, argument := range ot.TypeArguments {
+		if i != 0 {
+			signature += ", "
+		}
+		signature += argument.String()
+	}
+
+	return signature + ">"
+}
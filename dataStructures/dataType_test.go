@@ -18,6 +18,8 @@ func TestIsCompositeType(t *testing.T) {
 		{DT_List, &DataType{DT_Bool, nil}}:    true,
 		{DT_Set, &DataType{DT_String, nil}}:   true,
 		{DT_Option, &DataType{DT_Float, nil}}: true,
+		{DT_Map, &MapType{&DataType{DT_String, nil}, &DataType{DT_Int, nil}}}: true,
+		{DT_Function, &FunctionType{[]*DataType{{DT_Int, nil}}, nil}}:         false,
 	}
 
 	for dataType, isComposite := range dataTypes {
@@ -34,9 +36,13 @@ func TestEquals(t *testing.T) {
 	enumtype := &DataType{DT_Enum, "Day"}
 	objectType := &DataType{DT_Object, "Person"}
 	boolListType := &DataType{DT_List, &DataType{DT_Bool, nil}}
+	strIntMapType := &DataType{DT_Map, &MapType{&DataType{DT_String, nil}, &DataType{DT_Int, nil}}}
+	strBoolMapType := &DataType{DT_Map, &MapType{&DataType{DT_String, nil}, &DataType{DT_Bool, nil}}}
+	intToBoolFunctionType := &DataType{DT_Function, &FunctionType{[]*DataType{intType}, &DataType{DT_Bool, nil}}}
+	intFunctionType := &DataType{DT_Function, &FunctionType{[]*DataType{intType}, nil}}
 
 	// Equals
-	allTypes := []*DataType{intType, noneType, strOptionType, enumtype, objectType, boolListType}
+	allTypes := []*DataType{intType, noneType, strOptionType, enumtype, objectType, boolListType, strIntMapType, intToBoolFunctionType, intFunctionType}
 	for _, dataType := range allTypes {
 		if !dataType.Equals(dataType) {
 			t.Errorf("%s == %s: false, want true", dataType, dataType)
@@ -44,16 +50,22 @@ func TestEquals(t *testing.T) {
 	}
 
 	// Not equals
-	combinations := map[*DataType]*DataType{
-		intType:       noneType,
-		boolListType:  intType,
-		enumtype:      intType,
-		enumtype:      strOptionType,
-		enumtype:      objectType,
-		strOptionType: boolListType,
+	combinations := [][2]*DataType{
+		{intType, noneType},
+		{boolListType, intType},
+		{enumtype, intType},
+		{enumtype, strOptionType},
+		{enumtype, objectType},
+		{strOptionType, boolListType},
+		{strIntMapType, strBoolMapType},
+		{boolListType, strIntMapType},
+		{intToBoolFunctionType, intFunctionType},
+		{intFunctionType, intType},
 	}
 
-	for type1, type2 := range combinations {
+	for _, pair := range combinations {
+		type1, type2 := pair[0], pair[1]
+
 		if type1.Equals(type2) {
 			t.Errorf("%s == %s: true, want false", type1, type2)
 		}
@@ -71,49 +83,67 @@ func TestAssign(t *testing.T) {
 	objectType1 := &DataType{DT_Object, "Person"}
 	objectType2 := &DataType{DT_Object, "Animal"}
 	intListType := &DataType{DT_List, &DataType{DT_Int, nil}}
+	strIntMapType := &DataType{DT_Map, &MapType{&DataType{DT_String, nil}, &DataType{DT_Int, nil}}}
+	strAnyMapType := &DataType{DT_Map, &MapType{&DataType{DT_String, nil}, anyType}}
+	intToAnyFunctionType := &DataType{DT_Function, &FunctionType{[]*DataType{intType}, anyType}}
+	intToBoolFunctionType := &DataType{DT_Function, &FunctionType{[]*DataType{intType}, boolType}}
+	boolToBoolFunctionType := &DataType{DT_Function, &FunctionType{[]*DataType{boolType}, boolType}}
+	intFunctionType := &DataType{DT_Function, &FunctionType{[]*DataType{intType}, nil}}
 
 	// Assignable
-	assignableCombinations := map[*DataType]*DataType{
-		anyType:       anyType,
-		anyType:       intType,
-		anyType:       noneType,
-		anyType:       objectType2,
-		anyType:       intListType,
-		intType:       intType,
-		noneType:      noneType,
-		intOptionType: intOptionType,
-		intOptionType: intType,
-		intOptionType: noneType,
-		enumtype:      enumtype,
-		objectType1:   objectType1,
-		intListType:   intListType,
-		intListType:   intListType,
-	}
-
-	for assignedToType, assignedType := range assignableCombinations {
+	assignableCombinations := [][2]*DataType{
+		{anyType, anyType},
+		{anyType, intType},
+		{anyType, noneType},
+		{anyType, objectType2},
+		{anyType, intListType},
+		{intType, intType},
+		{noneType, noneType},
+		{intOptionType, intOptionType},
+		{intOptionType, intType},
+		{intOptionType, noneType},
+		{enumtype, enumtype},
+		{objectType1, objectType1},
+		{intListType, intListType},
+		{strIntMapType, strIntMapType},
+		{strAnyMapType, strIntMapType},
+		{intToAnyFunctionType, intToBoolFunctionType},
+		{intFunctionType, intFunctionType},
+	}
+
+	for _, pair := range assignableCombinations {
+		assignedToType, assignedType := pair[0], pair[1]
+
 		if !assignedToType.CanBeAssigned(assignedType) {
 			t.Errorf("(%s).CanBeAssigned(%s): false, want true", assignedToType, assignedType)
 		}
 	}
 
 	// Not assignable
-	notAssignableCombinations := map[*DataType]*DataType{
-		unknownType:   anyType,
-		anyType:       unknownType,
-		intType:       unknownType,
-		unknownType:   intType,
-		intType:       anyType,
-		intOptionType: anyType,
-		noneType:      anyType,
-		intType:       boolType,
-		intOptionType: boolType,
-		intListType:   intType,
-		intListType:   intOptionType,
-		objectType1:   objectType2,
-		noneType:      intOptionType,
-	}
-
-	for assignedToType, assignedType := range notAssignableCombinations {
+	notAssignableCombinations := [][2]*DataType{
+		{unknownType, anyType},
+		{anyType, unknownType},
+		{intType, unknownType},
+		{unknownType, intType},
+		{intType, anyType},
+		{intOptionType, anyType},
+		{noneType, anyType},
+		{intType, boolType},
+		{intOptionType, boolType},
+		{intListType, intType},
+		{intListType, intOptionType},
+		{objectType1, objectType2},
+		{noneType, intOptionType},
+		{strIntMapType, strAnyMapType},
+		{strIntMapType, intListType},
+		{intToBoolFunctionType, intToAnyFunctionType},
+		{boolToBoolFunctionType, intToBoolFunctionType},
+		{intFunctionType, intToBoolFunctionType},
+	}
+
+	for _, pair := range notAssignableCombinations {
+		assignedToType, assignedType := pair[0], pair[1]
+
 		if assignedToType.CanBeAssigned(assignedType) {
 			t.Errorf("(%s).CanBeAssigned(%s): true, want false", assignedToType, assignedType)
 		}
@@ -360,9 +390,14 @@ func TestDataTypeString(t *testing.T) {
 		{DT_List, &DataType{DT_Bool, nil}}:    "list<bool>",
 		{DT_Set, &DataType{DT_Int, nil}}:      "set<int>",
 		{DT_Option, &DataType{DT_Float, nil}}: "float?",
-		{DT_Set, &DataType{DT_List, &DataType{DT_Bool, nil}}}:    "set<list<bool>>",
-		{DT_Set, &DataType{DT_List, &DataType{DT_Unknown, nil}}}: "set<list<?>>",
-		{DT_List, &DataType{DT_Option, &DataType{DT_Int, nil}}}:  "list<int?>",
+		{DT_Set, &DataType{DT_List, &DataType{DT_Bool, nil}}}:                                            "set<list<bool>>",
+		{DT_Set, &DataType{DT_List, &DataType{DT_Unknown, nil}}}:                                         "set<list<?>>",
+		{DT_List, &DataType{DT_Option, &DataType{DT_Int, nil}}}:                                          "list<int?>",
+		{DT_Map, &MapType{&DataType{DT_String, nil}, &DataType{DT_List, &DataType{DT_Int, nil}}}}:        "map<string, list<int>>",
+		{DT_Function, &FunctionType{[]*DataType{{DT_Int, nil}, {DT_Int, nil}}, &DataType{DT_Bool, nil}}}: "fun(int, int) -> bool",
+		{DT_Function, &FunctionType{[]*DataType{}, nil}}:                                                 "fun()",
+		{DT_Generic, "T"}: "T",
+		{DT_Object, &ObjectType{"Pair", []*DataType{{DT_Int, nil}, {DT_Generic, "T"}}}}: "Pair<int, T>",
 	}
 
 	for dataType, name := range dataTypes {
@@ -397,3 +432,71 @@ func TestSignature(t *testing.T) {
 		}
 	}
 }
+
+func TestSubstitute(t *testing.T) {
+	typeArguments := map[string]*DataType{
+		"T": {DT_Int, nil},
+		"U": {DT_List, &DataType{DT_String, nil}},
+	}
+
+	dataTypes := map[*DataType]string{
+		{DT_Generic, "T"}:                       "int",
+		{DT_Generic, "V"}:                       "V",
+		{DT_Bool, nil}:                          "bool",
+		{DT_Option, &DataType{DT_Generic, "T"}}: "int?",
+		{DT_Map, &MapType{&DataType{DT_Generic, "T"}, &DataType{DT_Generic, "U"}}}:               "map<int, list<string>>",
+		{DT_Object, &ObjectType{"Pair", []*DataType{{DT_Generic, "U"}, {DT_Generic, "T"}}}}:      "Pair<list<string>, int>",
+		{DT_Function, &FunctionType{[]*DataType{{DT_Generic, "T"}}, &DataType{DT_Generic, "U"}}}: "fun(int) -> list<string>",
+	}
+
+	for dataType, name := range dataTypes {
+		if dataType.Substitute(typeArguments).String() != name {
+			t.Errorf("(%s).Substitute(): %s, want %s", dataType, dataType.Substitute(typeArguments), name)
+		}
+	}
+}
+
+func TestInferTypeArguments(t *testing.T) {
+	intType := &DataType{DT_Int, nil}
+	strType := &DataType{DT_String, nil}
+	genericT := &DataType{DT_Generic, "T"}
+
+	// Successful inference
+	typeArguments := map[string]*DataType{}
+	pairType := &DataType{DT_Object, &ObjectType{"Pair", []*DataType{genericT, {DT_Generic, "U"}}}}
+
+	if !pairType.InferTypeArguments(&DataType{DT_Object, &ObjectType{"Pair", []*DataType{intType, strType}}}, typeArguments) {
+		t.Errorf("(%s).InferTypeArguments(Pair<int, string>): false, want true", pairType)
+	}
+
+	if !typeArguments["T"].Equals(intType) || !typeArguments["U"].Equals(strType) {
+		t.Errorf("(%s).InferTypeArguments(Pair<int, string>): T = %s, U = %s, want T = int, U = string", pairType, typeArguments["T"], typeArguments["U"])
+	}
+
+	// Options can be assigned values of their sub-type
+	typeArguments = map[string]*DataType{}
+	optionType := &DataType{DT_Option, genericT}
+
+	if !optionType.InferTypeArguments(intType, typeArguments) || !typeArguments["T"].Equals(intType) {
+		t.Errorf("(%s).InferTypeArguments(int): T = %s, want T = int", optionType, typeArguments["T"])
+	}
+
+	// Conflicting type arguments
+	typeArguments = map[string]*DataType{"T": intType}
+
+	if genericT.InferTypeArguments(strType, typeArguments) {
+		t.Errorf("(%s).InferTypeArguments(string) with T = int: true, want false", genericT)
+	}
+
+	// Incompatible types
+	listType := &DataType{DT_List, genericT}
+
+	if listType.InferTypeArguments(intType, map[string]*DataType{}) {
+		t.Errorf("(%s).InferTypeArguments(int): true, want false", listType)
+	}
+
+	// Types without type parameters
+	if !intType.InferTypeArguments(intType, map[string]*DataType{}) || intType.InferTypeArguments(strType, map[string]*DataType{}) {
+		t.Errorf("(int).InferTypeArguments() doesn't match (int).CanBeAssigned()")
+	}
+}
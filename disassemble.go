@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+
+	VM "github.com/DanielNos/neco/virtualMachine"
+
+	"github.com/fatih/color"
+)
+
+// Prints header, constants and code of a NeCo binary.
+func disassemble(configuration *Configuration) {
+	virtualMachine := VM.NewVirtualMachine(configuration.TargetPath, nil)
+
+	reader := VM.NewInstructionReader(configuration.TargetPath, virtualMachine)
+	reader.Read()
+
+	color.HiYellow("NeCo binary %s\n", configuration.TargetPath)
+	color.Set(color.FgHiWhite)
+	fmt.Printf("Version %d.%d.%d\n\n", reader.Version[0], reader.Version[1], reader.Version[2])
+
+	printConstants(reader.ConstantCounts[0], reader.ConstantCounts[1], reader.ConstantCounts[2], virtualMachine.Constants)
+
+	// Functions are named by the scope they push
+	functionNames := make([]string, len(reader.Functions))
+
+	for i, start := range reader.Functions {
+		for j := start; j < functionEnd(reader.Functions, i, len(virtualMachine.FunctionsInstructions)); j++ {
+			if virtualMachine.FunctionsInstructions[j].InstructionType == VM.IT_PushScope {
+				functionNames[i] = virtualMachine.Constants[virtualMachine.FunctionsInstructions[j].InstructionValue[0]].(string)
+				break
+			}
+		}
+	}
+
+	disassembler := disassembler{virtualMachine.Constants, functionNames, map[int]int{}, 0}
+
+	fmt.Println()
+	color.HiYellow("Globals %d instructions\n", len(virtualMachine.GlobalsInstructions))
+	color.Set(color.FgHiWhite)
+
+	disassembler.printCode(virtualMachine.GlobalsInstructions, reader.GlobalsOffsets, 0, len(virtualMachine.GlobalsInstructions))
+
+	// Lines are tracked separately for globals and functions, the same way the virtual machine does it
+	disassembler.lines = map[int]int{}
+	disassembler.file = 0
+
+	fmt.Println()
+	color.HiYellow("Functions %d instructions\n", len(virtualMachine.FunctionsInstructions))
+	color.Set(color.FgHiWhite)
+
+	// Instructions before the first function set file and line of it
+	start := len(virtualMachine.FunctionsInstructions)
+	if len(reader.Functions) != 0 {
+		start = reader.Functions[0]
+	}
+
+	disassembler.printCode(virtualMachine.FunctionsInstructions, reader.FunctionsOffsets, 0, start)
+
+	for i, start := range reader.Functions {
+		end := functionEnd(reader.Functions, i, len(virtualMachine.FunctionsInstructions))
+
+		fmt.Println()
+		color.HiYellow("Function %d %s() instructions %d-%d\n", i, functionNames[i], start, end-1)
+		color.Set(color.FgHiWhite)
+
+		disassembler.printCode(virtualMachine.FunctionsInstructions, reader.FunctionsOffsets, start, end)
+	}
+}
+
+// Returns index of the instruction after the last instruction of function.
+func functionEnd(functions []int, function, instructionCount int) int {
+	if function+1 < len(functions) {
+		return functions[function+1]
+	}
+
+	return instructionCount
+}
+
+type disassembler struct {
+	constants     []any
+	functionNames []string
+
+	lines map[int]int // Current lines by index of file constant
+	file  int
+}
+
+// Prints instructions from start to end with their byte offsets, indexes, source lines and resolved arguments.
+func (d *disassembler) printCode(instructions []VM.ExpandedInstruction, offsets []int, start, end int) {
+	for i := start; i < end; i++ {
+		instruction := instructions[i]
+
+		switch instruction.InstructionType {
+		// Marker is followed by line offset with the first line of the file
+		case VM.IT_FileMarker:
+			d.file = instruction.InstructionValue[0]
+			d.lines[d.file] = 0
+
+		case VM.IT_LineOffset:
+			d.lines[d.file] += instruction.InstructionValue[0]
+		}
+
+		// Print offset, index and source position
+		color.Set(color.FgWhite)
+		fmt.Printf("0x%06x %5d  ", offsets[i], i)
+
+		// Instructions before the first line don't have a position
+		position := ""
+		if d.lines[d.file] != 0 {
+			position = fmt.Sprintf("%s.neco:%d", d.constants[d.file], d.lines[d.file])
+		}
+
+		color.Set(color.FgHiCyan)
+		fmt.Printf("%-16s", position)
+
+		// Print instruction name and argument
+		color.Set(color.FgHiWhite)
+		fmt.Printf("%-18s", VM.InstructionTypeToString[instruction.InstructionType])
+
+		if len(instruction.InstructionValue) != 0 {
+			fmt.Printf("%-6d", instruction.InstructionValue[0])
+		}
+
+		color.Set(color.FgHiGreen)
+		fmt.Print(d.describeArgument(instructions, i))
+		color.Set(color.FgHiWhite)
+
+		fmt.Println()
+	}
+}
+
+// Describes what argument of instruction refers to.
+func (d *disassembler) describeArgument(instructions []VM.ExpandedInstruction, index int) string {
+	instruction := instructions[index]
+
+	switch {
+	// Jumps are relative to the next instruction
+	case VM.IsJumpForward(instruction.InstructionType):
+		return fmt.Sprintf("-> %d", index+instruction.InstructionValue[0]+1)
+
+	case instruction.InstructionType == VM.IT_JumpBack || instruction.InstructionType == VM.IT_JumpBackEx:
+		return fmt.Sprintf("-> %d", index-instruction.InstructionValue[0]+1)
+
+	// Catch block starts after the jump following the handler
+	case instruction.InstructionType == VM.IT_PushHandler:
+		return fmt.Sprintf("catch -> %d", index+2)
+
+	case instruction.InstructionType == VM.IT_Call || instruction.InstructionType == VM.IT_CreateClosure:
+		if instruction.InstructionValue[0] < len(d.functionNames) {
+			return d.functionNames[instruction.InstructionValue[0]] + "()"
+		}
+
+	case instruction.InstructionType == VM.IT_CallBuiltInFunc:
+		return VM.BuiltInFuncToString[byte(instruction.InstructionValue[0])] + "()"
+
+	case instruction.InstructionType == VM.IT_FileMarker || instruction.InstructionType == VM.IT_PushScope ||
+		instruction.InstructionType == VM.IT_LoadConst || instruction.InstructionType == VM.IT_LoadConstToList:
+		constant := d.constants[instruction.InstructionValue[0]]
+
+		if reflect.TypeOf(constant).Kind() == reflect.String {
+			return fmt.Sprintf("\"%v\"", constant)
+		}
+		return fmt.Sprintf("%v", constant)
+	}
+
+	return ""
+}
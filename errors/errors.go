@@ -15,4 +15,7 @@ const (
 	STACK_OVERFLOW
 	UNDECLARED_VARIABLE
 	INDEX_OUT_OF_RANGE
+
+	UNFORMATTED
+	LINTS
 )
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DanielNos/neco/errors"
+	"github.com/DanielNos/neco/formatter"
+	"github.com/DanielNos/neco/logger"
+	"github.com/DanielNos/neco/moduleLoader"
+)
+
+// Formats files and directories of configuration. Formatted code is printed, checked or written depending on flags. Returns exit code.
+func formatFiles(configuration *Configuration) int {
+	exitCode := 0
+	unformatted := 0
+
+	for _, path := range collectFormatPaths(configuration.FormatPaths) {
+		source, err := os.ReadFile(path)
+
+		if err != nil {
+			logger.Error("Failed to read file " + path + ".")
+			exitCode = errors.READ_PROGRAM
+			continue
+		}
+
+		// Only syntactically valid code can be formatted
+		loader := moduleLoader.NewModuleLoader(configuration.IncludePaths)
+		loader.SetSource(path, string(source))
+		loader.Load(path)
+
+		if errorCount := loader.LexicalErrorCount + loader.SyntaxErrorCount; errorCount != 0 {
+			logger.Error(fmt.Sprintf("Can't format %s, because it has %d error/s.", path, errorCount))
+			exitCode = errors.SYNTAX
+			continue
+		}
+
+		formatted, err := formatter.Format(path, string(source))
+
+		if err != nil {
+			logger.Error("Failed to format " + path + ". " + strings.ToUpper(err.Error()[:1]) + err.Error()[1:] + ".")
+			exitCode = errors.SYNTAX
+			continue
+		}
+
+		switch {
+		case configuration.CheckFormat:
+			if formatted != string(source) {
+				logger.Warning(path + " isn't formatted.")
+				unformatted++
+			}
+
+		case configuration.WriteFormat:
+			if formatted == string(source) {
+				continue
+			}
+
+			if err := os.WriteFile(path, []byte(formatted), 0644); err != nil {
+				logger.Error("Failed to write file " + path + ".")
+				exitCode = errors.READ_PROGRAM
+				continue
+			}
+			logger.Info("Formatted " + path + ".")
+
+		default:
+			fmt.Print(formatted)
+		}
+	}
+
+	if unformatted != 0 {
+		logger.Error(fmt.Sprintf("%d file/s aren't formatted.", unformatted))
+
+		if exitCode == 0 {
+			exitCode = errors.UNFORMATTED
+		}
+	}
+
+	return exitCode
+}
+
+// Collects paths of files. Directories are searched for .neco files.
+func collectFormatPaths(targets []string) []string {
+	paths := []string{}
+
+	for _, target := range targets {
+		info, err := os.Stat(target)
+
+		// Try again with .neco file extension
+		if err != nil && !strings.HasSuffix(target, ".neco") {
+			target += ".neco"
+			info, err = os.Stat(target)
+		}
+
+		if err != nil {
+			logger.Fatal(errors.READ_PROGRAM, "Failed to open "+target+".")
+		}
+
+		if !info.IsDir() {
+			paths = append(paths, target)
+			continue
+		}
+
+		filepath.WalkDir(target, func(path string, entry fs.DirEntry, err error) error {
+			if err == nil && !entry.IsDir() && strings.HasSuffix(path, ".neco") {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+	}
+
+	return paths
+}
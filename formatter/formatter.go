@@ -0,0 +1,655 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/DanielNos/neco/lexer"
+)
+
+const INDENTATION = "    "
+
+// Roles of tokens, which are spaced differently depending on their context
+type role byte
+
+const (
+	R_None          role = iota
+	R_Prefix             // Unary operator before it's operand
+	R_Postfix            // Unwrap or none check after it's operand
+	R_Call               // Parenthesis or bracket after called or indexed expression
+	R_GenericOpen        // < of type parameters
+	R_GenericClose       // > of type parameters
+	R_BlockOpen          // { of block
+	R_ScopeOpen          // { of bare scope
+	R_BlockClose         // } of block
+	R_LiteralOpen        // { of struct, class or map literal
+	R_LiteralClose       // } of struct, class or map literal
+	R_SliceColon         // : between indexes of slice
+	R_KeyColon           // : after key of map literal or property name
+	R_TernaryColon       // : between branches of ternary operator
+	R_PathSeparator      // / between segments of imported module path
+)
+
+type delimiter struct {
+	tokenType   lexer.TokenType
+	role        role
+	indentation int  // Indentation of lines inside the delimiter
+	ternaries   int  // Number of ternary operators inside the delimiter waiting for their colon
+	isInline    bool // Block is inside of an expression, like a lambda passed as an argument, so it keeps line breaks of the source
+}
+
+// Formats tokens of a module, including comments, in canonical style. Opening braces of blocks are moved to the lines of their headers and contents of blocks
+// to their own lines. Other line breaks of the source are kept, but blank lines are collapsed.
+type Formatter struct {
+	tokens []*lexer.Token
+	lines  [][]rune
+	output strings.Builder
+
+	tokenIndex int
+	lineBreaks int // Number of line breaks before the current token
+	delimiters []*delimiter
+
+	lineIndentation int
+	expectedBlocks  int  // Number of keywords, which are followed by a block
+	declaresType    bool // Struct, class or enum is being declared, so it's brace opens a block
+	importsModule   bool
+
+	previous     *lexer.Token // Last written token
+	previousRole role
+	operand      *lexer.Token // Last written token, which isn't a comment
+	operandRole  role
+}
+
+// Formats source of a module. Source has to be syntactically valid.
+// Formatted source is lexed again and compared with the original, so formatting can't change the meaning of the code.
+func Format(filePath, source string) (string, error) {
+	if !strings.HasSuffix(source, "\n") {
+		source += "\n"
+	}
+
+	tokens, errorCount := lex(filePath, source)
+	if errorCount != 0 {
+		return "", fmt.Errorf("source has %d lexical error/s", errorCount)
+	}
+
+	formatter := NewFormatter(tokens, source)
+	formatted := formatter.Format()
+
+	formattedTokens, errorCount := lex(filePath, formatted)
+	if errorCount != 0 || !equalTokens(tokens, formattedTokens) {
+		return "", fmt.Errorf("formatted code doesn't match the original code")
+	}
+
+	return formatted, nil
+}
+
+func NewFormatter(tokens []*lexer.Token, source string) Formatter {
+	lines := strings.Split(source, "\n")
+	runeLines := make([][]rune, len(lines))
+
+	for i, line := range lines {
+		runeLines[i] = []rune(strings.TrimSuffix(line, "\r"))
+	}
+
+	return Formatter{
+		tokens,
+		runeLines,
+		strings.Builder{},
+		0,
+		0,
+		[]*delimiter{{lexer.TT_StartOfFile, R_None, 0, 0, false}},
+		0,
+		0,
+		false,
+		false,
+		nil,
+		R_None,
+		nil,
+		R_None,
+	}
+}
+
+func (f *Formatter) Format() string {
+	for f.tokenIndex = 0; f.tokenIndex < len(f.tokens); f.tokenIndex++ {
+		token := f.tokens[f.tokenIndex]
+
+		switch token.TokenType {
+		case lexer.TT_StartOfFile, lexer.TT_EndOfFile:
+			continue
+
+		case lexer.TT_EndOfCommand:
+			// Block can start on the next line
+			if token.Value != "" || !f.isBeforeBrace() {
+				f.expectedBlocks = 0
+				f.declaresType = false
+			}
+			f.importsModule = false
+
+			if token.Value == "" {
+				f.lineBreaks++
+				continue
+			}
+		}
+
+		text := f.text(token)
+
+		// Strings are written together with their interpolated expressions, which are lexed as multiple tokens
+		if strings.HasPrefix(text, "\"") {
+			token = &lexer.Token{Position: token.Position, TokenType: lexer.TT_LT_String, Value: text}
+		}
+
+		tokenRole := f.classify(token)
+		f.placeBraces(token, tokenRole)
+
+		// Close delimiter
+		var closed *delimiter
+		if tokenRole == R_GenericClose || token.TokenType.IsClosingDelimiter() {
+			closed = f.close(token.TokenType)
+		}
+
+		// Start new line
+		if f.lineBreaks != 0 || f.output.Len() == 0 {
+			if f.output.Len() != 0 {
+				f.output.WriteString("\n")
+
+				// Blank lines are collapsed to one and removed at the start and the end of delimiters
+				isAfterOpening := f.previous.TokenType.IsOpeningDelimiter() || f.previousRole == R_GenericOpen
+				if f.lineBreaks > 1 && !isAfterOpening && closed == nil {
+					f.output.WriteString("\n")
+				}
+			}
+
+			f.lineIndentation = f.top().indentation
+			if closed != nil {
+				f.lineIndentation = closed.indentation - 1
+			}
+
+			f.output.WriteString(strings.Repeat(INDENTATION, f.lineIndentation))
+			f.lineBreaks = 0
+		} else if f.needsSpace(token, tokenRole) {
+			f.output.WriteString(" ")
+		}
+
+		f.output.WriteString(text)
+		f.update(token, tokenRole)
+	}
+
+	f.output.WriteString("\n")
+
+	return f.output.String()
+}
+
+// Moves line breaks around braces of blocks. Opening brace is written on the line of the block header and content of the block on it's own lines.
+// Closing brace is followed by else, elif or catch on the same line. Line breaks after comments can't be removed.
+func (f *Formatter) placeBraces(token *lexer.Token, tokenRole role) {
+	if f.previous == nil || f.previous.TokenType == lexer.TT_Comment {
+		return
+	}
+
+	isAfterOpening := f.previousRole == R_BlockOpen || f.previousRole == R_ScopeOpen
+	isInline := f.top().isInline && f.top().tokenType == lexer.TT_DL_BraceOpen
+
+	switch {
+	// Empty block
+	case tokenRole == R_BlockClose && isAfterOpening:
+		f.lineBreaks = 0
+
+	case tokenRole == R_BlockOpen:
+		f.lineBreaks = 0
+
+	// Comment after opening brace stays on it's line
+	case !isInline && (tokenRole == R_BlockClose || isAfterOpening && token.TokenType != lexer.TT_Comment):
+		f.lineBreaks = max(f.lineBreaks, 1)
+
+	case f.previousRole == R_BlockClose:
+		switch token.TokenType {
+		case lexer.TT_KW_else, lexer.TT_KW_elif, lexer.TT_KW_catch:
+			f.lineBreaks = 0
+		}
+	}
+}
+
+// Collects text of token from the source.
+func (f *Formatter) text(token *lexer.Token) string {
+	if token.TokenType == lexer.TT_EndOfCommand {
+		return ";"
+	}
+
+	position := token.Position
+	line := f.lines[position.StartLine-1]
+	start := int(position.StartChar) - 1
+
+	// String literal
+	if start < len(line) && line[start] == '"' {
+		end := stringEnd(line, start)
+
+		// Skip tokens of interpolated expressions
+		for f.tokenIndex+1 < len(f.tokens) {
+			next := f.tokens[f.tokenIndex+1].Position
+
+			if next.StartLine != position.StartLine || int(next.StartChar)-1 > end {
+				break
+			}
+			f.tokenIndex++
+		}
+
+		return string(line[start : end+1])
+	}
+
+	// Token on a single line
+	if position.StartLine == position.EndLine {
+		if int(position.EndChar) <= start || int(position.EndChar) > len(line) {
+			return token.String()
+		}
+
+		text := string(line[start:position.EndChar])
+
+		if token.TokenType == lexer.TT_Comment {
+			text = strings.TrimRightFunc(text, unicode.IsSpace)
+		}
+		return text
+	}
+
+	// Multi line comment
+	text := string(line[start:]) + "\n"
+
+	for i := position.StartLine; i < position.EndLine-1; i++ {
+		text += string(f.lines[i]) + "\n"
+	}
+
+	return text + string(f.lines[position.EndLine-1][:position.EndChar])
+}
+
+// Finds index of closing quote of string starting at start. Strings can contain interpolated expressions with strings.
+func stringEnd(line []rune, start int) int {
+	i := start + 1
+
+	for i < len(line) {
+		switch line[i] {
+		case '\\':
+			i++
+
+		case '"':
+			return i
+
+		case '{':
+			depth := 1
+			i++
+
+			for i < len(line) && depth != 0 {
+				switch line[i] {
+				case '{':
+					depth++
+				case '}':
+					depth--
+				case '"':
+					i = stringEnd(line, i)
+				}
+
+				if depth != 0 {
+					i++
+				}
+			}
+		}
+
+		i++
+	}
+
+	return len(line) - 1
+}
+
+// Determines role of token from it's previous tokens.
+func (f *Formatter) classify(token *lexer.Token) role {
+	top := f.top()
+
+	switch token.TokenType {
+	case lexer.TT_OP_Subtract:
+		if !f.isAfterOperand() {
+			return R_Prefix
+		}
+
+	case lexer.TT_OP_Not:
+		if f.isAfterOperand() {
+			return R_Postfix
+		}
+		return R_Prefix
+
+	case lexer.TT_OP_QuestionMark:
+		return R_Postfix
+
+	case lexer.TT_OP_Divide:
+		if f.importsModule {
+			return R_PathSeparator
+		}
+
+	case lexer.TT_OP_Lower:
+		if f.operand == nil {
+			break
+		}
+
+		// Generic composite type
+		if f.operand.TokenType.IsCompositeType() {
+			return R_GenericOpen
+		}
+
+		// Type parameters of generic type or function are written right after it's identifier
+		next := f.peekNext()
+		if f.operand.TokenType == lexer.TT_Identifier && !f.isAfterSpace(token) && (next.TokenType == lexer.TT_Identifier || next.TokenType.IsVariableType() || next.TokenType == lexer.TT_KW_fun) {
+			return R_GenericOpen
+		}
+
+	case lexer.TT_OP_Greater:
+		if top.role == R_GenericOpen {
+			return R_GenericClose
+		}
+
+	case lexer.TT_DL_ParenthesisOpen:
+		if f.operand == nil {
+			break
+		}
+
+		switch f.operand.TokenType {
+		case lexer.TT_Identifier, lexer.TT_DL_ParenthesisClose, lexer.TT_DL_BracketClose, lexer.TT_KW_bool, lexer.TT_KW_int, lexer.TT_KW_flt, lexer.TT_KW_str, lexer.TT_KW_fun:
+			return R_Call
+		}
+
+		if f.operandRole == R_GenericClose {
+			return R_Call
+		}
+
+	case lexer.TT_DL_BracketOpen:
+		if f.isAfterOperand() {
+			return R_Call
+		}
+
+	case lexer.TT_DL_BraceOpen:
+		if f.declaresType {
+			return R_BlockOpen
+		}
+
+		// Literal of struct or class is written right after it's type
+		if f.operand != nil && (f.operand.TokenType == lexer.TT_Identifier || f.operandRole == R_GenericClose) && !f.isAfterSpace(token) {
+			return R_LiteralOpen
+		}
+
+		if f.expectedBlocks != 0 {
+			return R_BlockOpen
+		}
+
+		// Bare scope starts a statement inside of a block
+		isStatementStart := f.lineBreaks != 0 || f.previous == nil || f.previous.TokenType == lexer.TT_EndOfCommand || f.previousRole == R_BlockOpen || f.previousRole == R_ScopeOpen
+		isInBlock := top.role == R_BlockOpen || top.role == R_ScopeOpen || top.tokenType == lexer.TT_StartOfFile
+
+		if isStatementStart && isInBlock {
+			return R_ScopeOpen
+		}
+		return R_LiteralOpen
+
+	case lexer.TT_DL_BraceClose:
+		for i := len(f.delimiters) - 1; i > 0; i-- {
+			if f.delimiters[i].tokenType == lexer.TT_DL_BraceOpen {
+				if f.delimiters[i].role == R_BlockOpen || f.delimiters[i].role == R_ScopeOpen {
+					return R_BlockClose
+				}
+				return R_LiteralClose
+			}
+		}
+		return R_BlockClose
+
+	case lexer.TT_DL_Colon:
+		if top.tokenType == lexer.TT_DL_BracketOpen {
+			return R_SliceColon
+		}
+
+		if top.ternaries != 0 {
+			top.ternaries--
+			return R_TernaryColon
+		}
+		return R_KeyColon
+	}
+
+	return R_None
+}
+
+// Checks if token should be separated from the previous token on the same line by a space.
+func (f *Formatter) needsSpace(token *lexer.Token, tokenRole role) bool {
+	if token.TokenType == lexer.TT_Comment || f.previous.TokenType == lexer.TT_Comment {
+		return true
+	}
+
+	// Tokens after previous token
+	switch f.previousRole {
+	case R_Prefix, R_GenericOpen, R_LiteralOpen, R_SliceColon, R_PathSeparator:
+		return false
+
+	case R_BlockOpen, R_ScopeOpen:
+		return tokenRole != R_BlockClose
+	}
+
+	switch f.previous.TokenType {
+	case lexer.TT_DL_ParenthesisOpen, lexer.TT_DL_BracketOpen, lexer.TT_OP_Dot, lexer.TT_OP_Range, lexer.TT_OP_RangeInclusive:
+		return false
+	}
+
+	// Tokens before token
+	switch tokenRole {
+	case R_Postfix, R_Call, R_GenericOpen, R_GenericClose, R_LiteralClose, R_SliceColon, R_KeyColon, R_PathSeparator:
+		return false
+
+	case R_LiteralOpen:
+		return f.previous.TokenType != lexer.TT_Identifier && f.previousRole != R_GenericClose
+	}
+
+	switch token.TokenType {
+	case lexer.TT_DL_ParenthesisClose, lexer.TT_DL_BracketClose, lexer.TT_DL_Comma, lexer.TT_EndOfCommand, lexer.TT_OP_Dot, lexer.TT_OP_Range, lexer.TT_OP_RangeInclusive:
+		return false
+	}
+
+	return true
+}
+
+// Updates state of formatter after token was written.
+func (f *Formatter) update(token *lexer.Token, tokenRole role) {
+	f.previous, f.previousRole = token, tokenRole
+
+	if token.TokenType == lexer.TT_Comment {
+		return
+	}
+
+	f.operand, f.operandRole = token, tokenRole
+
+	switch token.TokenType {
+	case lexer.TT_KW_if, lexer.TT_KW_elif, lexer.TT_KW_else, lexer.TT_KW_loop, lexer.TT_KW_while, lexer.TT_KW_for, lexer.TT_KW_forEach,
+		lexer.TT_KW_try, lexer.TT_KW_catch, lexer.TT_KW_match, lexer.TT_KW_CaseIs, lexer.TT_KW_fun:
+		f.expectedBlocks++
+
+	case lexer.TT_KW_struct, lexer.TT_KW_class, lexer.TT_KW_enum:
+		f.declaresType = true
+
+	case lexer.TT_KW_import:
+		f.importsModule = true
+
+	case lexer.TT_OP_Ternary:
+		f.top().ternaries++
+
+	case lexer.TT_DL_ParenthesisOpen, lexer.TT_DL_BracketOpen, lexer.TT_DL_BraceOpen:
+		top := f.top()
+		isInline := top.isInline || top.role != R_BlockOpen && top.role != R_ScopeOpen && top.tokenType != lexer.TT_StartOfFile
+		f.delimiters = append(f.delimiters, &delimiter{token.TokenType, tokenRole, f.lineIndentation + 1, 0, isInline})
+
+		if tokenRole == R_BlockOpen {
+			f.declaresType = false
+
+			if f.expectedBlocks != 0 {
+				f.expectedBlocks--
+			}
+		}
+
+	case lexer.TT_OP_Lower:
+		if tokenRole == R_GenericOpen {
+			f.delimiters = append(f.delimiters, &delimiter{token.TokenType, tokenRole, f.top().indentation, 0, f.top().isInline})
+		}
+	}
+
+	if token.TokenType.IsAssignKeyword() {
+		f.expectedBlocks = 0
+	}
+}
+
+// Removes delimiter closed by closing token type and delimiters of type parameters left open inside it.
+func (f *Formatter) close(closingType lexer.TokenType) *delimiter {
+	if len(f.delimiters) == 1 {
+		return f.delimiters[0]
+	}
+
+	closed := f.top()
+	f.delimiters = f.delimiters[:len(f.delimiters)-1]
+
+	// Comparisons can look like type parameters
+	if closed.role == R_GenericOpen && closingType != lexer.TT_OP_Greater {
+		return f.close(closingType)
+	}
+
+	return closed
+}
+
+func (f *Formatter) top() *delimiter {
+	return f.delimiters[len(f.delimiters)-1]
+}
+
+// Checks if the next token, which isn't a comment or a line break, is an opening brace.
+func (f *Formatter) isBeforeBrace() bool {
+	for i := f.tokenIndex + 1; i < len(f.tokens); i++ {
+		switch {
+		case f.tokens[i].TokenType == lexer.TT_Comment:
+		case f.tokens[i].TokenType == lexer.TT_EndOfCommand && f.tokens[i].Value == "":
+		default:
+			return f.tokens[i].TokenType == lexer.TT_DL_BraceOpen
+		}
+	}
+
+	return false
+}
+
+func (f *Formatter) peekNext() *lexer.Token {
+	for i := f.tokenIndex + 1; i < len(f.tokens); i++ {
+		if f.tokens[i].TokenType != lexer.TT_Comment {
+			return f.tokens[i]
+		}
+	}
+
+	return f.tokens[len(f.tokens)-1]
+}
+
+// Checks if the previous token ends an operand, so the following operator is binary or postfix.
+func (f *Formatter) isAfterOperand() bool {
+	if f.operand == nil {
+		return false
+	}
+
+	switch f.operandRole {
+	case R_Postfix, R_GenericClose, R_LiteralClose:
+		return true
+	}
+
+	switch f.operand.TokenType {
+	case lexer.TT_Identifier, lexer.TT_DL_ParenthesisClose, lexer.TT_DL_BracketClose:
+		return true
+	}
+
+	return f.operand.TokenType.IsLiteral()
+}
+
+// Checks if token is separated from the previous character by whitespace in the source.
+func (f *Formatter) isAfterSpace(token *lexer.Token) bool {
+	if token.Position.StartChar <= 1 {
+		return true
+	}
+
+	return unicode.IsSpace(f.lines[token.Position.StartLine-1][token.Position.StartChar-2])
+}
+
+func lex(filePath, source string) ([]*lexer.Token, uint) {
+	lex := lexer.NewLexerFromFile(filePath, io.NopCloser(strings.NewReader(source)))
+	lex.KeepComments = true
+
+	return lex.Lex(), lex.ErrorCount
+}
+
+// Compares tokens of original and formatted source. Blank lines, line breaks moved by formatting and trailing whitespace of comments are ignored.
+func equalTokens(original, formatted []*lexer.Token) bool {
+	originalTokens, formattedTokens := significantTokens(original), significantTokens(formatted)
+
+	if len(originalTokens) != len(formattedTokens) {
+		return false
+	}
+
+	for i := range originalTokens {
+		if originalTokens[i] != formattedTokens[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func significantTokens(tokens []*lexer.Token) []string {
+	significant := []string{}
+
+	for i, token := range tokens {
+		switch {
+		case token.TokenType == lexer.TT_StartOfFile || token.TokenType == lexer.TT_EndOfFile:
+			continue
+
+		// Consecutive line breaks are one line break, line breaks around braces are moved by formatting
+		case token.TokenType == lexer.TT_EndOfCommand && token.Value == "":
+			if len(significant) != 0 && significant[len(significant)-1] != "\n" && !isAroundBrace(tokens, i) {
+				significant = append(significant, "\n")
+			}
+
+		case token.TokenType == lexer.TT_Comment:
+			significant = append(significant, strings.TrimRightFunc(token.Value, unicode.IsSpace))
+
+		default:
+			significant = append(significant, token.TokenType.String()+" "+token.Value)
+		}
+	}
+
+	// Line breaks at the end of file
+	for len(significant) != 0 && significant[len(significant)-1] == "\n" {
+		significant = significant[:len(significant)-1]
+	}
+
+	return significant
+}
+
+// Checks if line break at index is after a brace or before a brace, else, elif or catch. Other line breaks are skipped.
+func isAroundBrace(tokens []*lexer.Token, index int) bool {
+	isLineBreak := func(token *lexer.Token) bool {
+		return token.TokenType == lexer.TT_EndOfCommand && token.Value == ""
+	}
+
+	previous := index - 1
+	for previous > 0 && isLineBreak(tokens[previous]) {
+		previous--
+	}
+
+	next := index + 1
+	for next < len(tokens)-1 && isLineBreak(tokens[next]) {
+		next++
+	}
+
+	switch tokens[previous].TokenType {
+	case lexer.TT_DL_BraceOpen, lexer.TT_DL_BraceClose:
+		return true
+	}
+
+	switch tokens[next].TokenType {
+	case lexer.TT_DL_BraceOpen, lexer.TT_DL_BraceClose, lexer.TT_KW_else, lexer.TT_KW_elif, lexer.TT_KW_catch:
+		return true
+	}
+
+	return false
+}
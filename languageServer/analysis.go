@@ -0,0 +1,105 @@
+package languageServer
+
+import (
+	data "github.com/DanielNos/neco/dataStructures"
+	"github.com/DanielNos/neco/logger"
+	"github.com/DanielNos/neco/moduleLoader"
+	"github.com/DanielNos/neco/parser"
+)
+
+type analysis struct {
+	module      string                          // Name of the document's module, which is used by code positions in it
+	modules     map[string]*moduleLoader.Module // Loaded modules by their name
+	index       *parser.SymbolIndex             // Nil if document couldn't be parsed
+	diagnostics []Diagnostic                    // Diagnostics of the analyzed document
+}
+
+// Analyzes document and modules imported by it. Errors and warnings are collected instead of being printed.
+func (ls *LanguageServer) analyze(analyzed *document) *analysis {
+	diagnostics := logger.CollectDiagnostics()
+	defer logger.StopCollecting()
+
+	loader := moduleLoader.NewModuleLoader(ls.includePaths)
+
+	// Open documents are used instead of their files, so unsaved changes are analyzed
+	for _, opened := range ls.documents {
+		loader.SetSource(opened.path, opened.text)
+	}
+
+	index := ls.parse(loader, analyzed.path)
+
+	// Collect modules, module of the document is found by it's path, because names of modules in different directories can be the same
+	modules := map[string]*moduleLoader.Module{}
+	documentModule := ""
+
+	for _, module := range loader.Modules {
+		modules[module.Name] = module
+
+		if !module.Embedded && samePath(module.Path, analyzed.path) {
+			documentModule = module.Name
+		}
+	}
+
+	// Collect diagnostics of document
+	documentDiagnostics := []Diagnostic{}
+
+	for _, diagnostic := range *diagnostics {
+		if *diagnostic.Position.File != documentModule {
+			continue
+		}
+
+		severity := DS_Warning
+		if diagnostic.IsError {
+			severity = DS_Error
+		}
+
+		documentDiagnostics = append(documentDiagnostics, Diagnostic{ls.encodeRange(codePosToRange(diagnostic.Position), analyzed.path), severity, SOURCE, diagnostic.Message})
+	}
+
+	return &analysis{documentModule, modules, index, documentDiagnostics}
+}
+
+// Loads and parses module at path. Returns nil if it has lexical or syntax errors, or if analysis has aborted.
+func (ls *LanguageServer) parse(loader *moduleLoader.ModuleLoader, path string) (index *parser.SymbolIndex) {
+	// Analysis aborts on too many errors and parser can fail on invalid code after reporting it's errors
+	defer func() {
+		if recover() != nil {
+			index = nil
+		}
+	}()
+
+	loader.Load(path)
+
+	// Parser requires syntactically valid code
+	if loader.LexicalErrorCount+loader.SyntaxErrorCount != 0 {
+		return nil
+	}
+
+	p := parser.NewParser(loader.TokenLists(), 0, false)
+	p.Index = parser.NewSymbolIndex()
+	p.Parse()
+
+	return p.Index
+}
+
+// Converts code position to a range. Code positions start at 1 and include their last character, ranges start at 0 and don't.
+func codePosToRange(codePos *data.CodePos) Range {
+	start := Position{decrement(codePos.StartLine), decrement(codePos.StartChar)}
+	end := Position{decrement(codePos.EndLine), codePos.EndChar}
+
+	// Positions of end of file end before they start
+	if end.Line == start.Line && end.Character < start.Character {
+		end.Character = start.Character
+	}
+
+	return Range{start, end}
+}
+
+// Decrements position, which is 0 for positions before the first line or character.
+func decrement(position uint) uint {
+	if position == 0 {
+		return 0
+	}
+
+	return position - 1
+}
@@ -0,0 +1,199 @@
+package languageServer
+
+import (
+	"io"
+	"strings"
+
+	data "github.com/DanielNos/neco/dataStructures"
+	"github.com/DanielNos/neco/lexer"
+	"github.com/DanielNos/neco/logger"
+)
+
+var KIND_TO_COMPLETION_ITEM_KIND = map[string]int{
+	"variable":       CIK_Variable,
+	"function":       CIK_Function,
+	"struct":         CIK_Struct,
+	"enum":           CIK_Enum,
+	"type parameter": CIK_TypeParameter,
+	"module":         CIK_Module,
+}
+
+// Describes symbol at position with it's type.
+func (ls *LanguageServer) hover(hovered *document, line, char uint) *Hover {
+	declaration, position := hovered.analysis.index.Find(hovered.analysis.module, line, char)
+
+	if declaration == nil {
+		return nil
+	}
+
+	return &Hover{markupContent{"markdown", "```neco\n" + declaration.Description + "\n```"}, ls.encodeRange(codePosToRange(position), hovered.path)}
+}
+
+// Finds location of declaration of symbol at position.
+func (ls *LanguageServer) definition(document *document, line, char uint) *Location {
+	declaration, _ := document.analysis.index.Find(document.analysis.module, line, char)
+
+	if declaration == nil || declaration.Position == nil {
+		return nil
+	}
+
+	return ls.location(document, declaration.Position)
+}
+
+// Finds locations of all uses of symbol at position.
+func (ls *LanguageServer) references(document *document, line, char uint, includeDeclaration bool) []Location {
+	locations := []Location{}
+	declaration, _ := document.analysis.index.Find(document.analysis.module, line, char)
+
+	if declaration == nil {
+		return locations
+	}
+
+	positions := document.analysis.index.References(declaration)
+
+	if includeDeclaration && declaration.Position != nil {
+		positions = append([]*data.CodePos{declaration.Position}, positions...)
+	}
+
+	for _, position := range positions {
+		if location := ls.location(document, position); location != nil {
+			locations = append(locations, *location)
+		}
+	}
+
+	return locations
+}
+
+// Creates location of code position. Modules embedded in the compiler don't have a location.
+func (ls *LanguageServer) location(document *document, codePos *data.CodePos) *Location {
+	module, exists := document.analysis.modules[*codePos.File]
+
+	if !exists || module.Embedded {
+		return nil
+	}
+
+	return &Location{pathToUri(module.Path), ls.encodeRange(codePosToRange(codePos), module.Path)}
+}
+
+// Lists symbols and built-ins visible at position.
+func (ls *LanguageServer) completion(document *document, line, char uint) []CompletionItem {
+	items := []CompletionItem{}
+
+	for _, declaration := range document.analysis.index.Visible(document.analysis.module, line, char) {
+		// Methods are named after their struct, so they can't be completed without the type of it's object
+		if strings.Contains(declaration.Identifier, ".") {
+			continue
+		}
+
+		items = append(items, CompletionItem{declaration.Identifier, KIND_TO_COMPLETION_ITEM_KIND[declaration.Kind], declaration.Description})
+	}
+
+	return items
+}
+
+// Lists signatures of called function, which has unclosed argument list at position.
+func (ls *LanguageServer) signatureHelp(document *document, line, char uint) *SignatureHelp {
+	identifier, argumentIndex := calledFunction(document.text, line, char)
+
+	if identifier == "" {
+		return nil
+	}
+
+	signatures := document.analysis.index.Signatures(identifier)
+
+	if len(signatures) == 0 {
+		return nil
+	}
+
+	help := &SignatureHelp{[]SignatureInformation{}, 0, argumentIndex}
+	activeFound := false
+
+	for i, signature := range signatures {
+		information := SignatureInformation{signature.Label, []ParameterInformation{}}
+
+		for _, parameter := range signature.Parameters {
+			information.Parameters = append(information.Parameters, ParameterInformation{parameter})
+		}
+
+		// First overload with enough parameters is active
+		if !activeFound && argumentIndex < len(signature.Parameters) {
+			help.ActiveSignature = i
+			activeFound = true
+		}
+
+		help.Signatures = append(help.Signatures, information)
+	}
+
+	return help
+}
+
+// Finds identifier of function, which has unclosed argument list at position, and index of the argument at position.
+// Document is lexed again, because it's argument list isn't closed, so it probably has syntax errors.
+func calledFunction(text string, line, char uint) (string, int) {
+	logger.CollectDiagnostics()
+	defer logger.StopCollecting()
+
+	tokens := lexTokens(text)
+	end := 0
+
+	// Find first token after position
+	for end < len(tokens) && (tokens[end].Position.StartLine < line || tokens[end].Position.StartLine == line && tokens[end].Position.StartChar < char) {
+		end++
+	}
+
+	// Find unclosed opening parenthesis
+	depth, argumentIndex := 0, 0
+
+	for i := end - 1; i > 0; i-- {
+		switch {
+		case tokens[i].TokenType.IsClosingDelimiter():
+			depth++
+
+		case tokens[i].TokenType.IsOpeningDelimiter() && depth > 0:
+			depth--
+
+		case tokens[i].TokenType == lexer.TT_DL_Comma && depth == 0:
+			argumentIndex++
+
+		case tokens[i].TokenType == lexer.TT_DL_ParenthesisOpen:
+			return functionIdentifier(tokens[:i]), argumentIndex
+
+		// Braces and brackets aren't argument lists
+		case tokens[i].TokenType.IsOpeningDelimiter():
+			return "", 0
+		}
+	}
+
+	return "", 0
+}
+
+// Collects identifier of function from tokens before it's argument list. Functions of imported modules are qualified with module name.
+func functionIdentifier(tokens []*lexer.Token) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	last := tokens[len(tokens)-1]
+	identifier := last.Value
+
+	switch last.TokenType {
+	case lexer.TT_KW_int, lexer.TT_KW_flt, lexer.TT_KW_str:
+		return last.TokenType.String()
+
+	case lexer.TT_Identifier:
+		if len(tokens) > 2 && tokens[len(tokens)-2].TokenType == lexer.TT_OP_Dot && tokens[len(tokens)-3].TokenType == lexer.TT_Identifier {
+			identifier = tokens[len(tokens)-3].Value + "." + identifier
+		}
+		return identifier
+	}
+
+	return ""
+}
+
+func lexTokens(text string) []*lexer.Token {
+	defer func() { recover() }()
+
+	// Lexer requires last line to be terminated
+	lex := lexer.NewLexerFromFile("document", io.NopCloser(strings.NewReader(text+"\n")))
+	return lex.Lex()
+}
@@ -0,0 +1,300 @@
+package languageServer
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const SOURCE = "neco"
+
+type document struct {
+	path string
+	text string
+
+	analysis *analysis
+}
+
+// Language server communicating using Language Server Protocol. Documents are analyzed when they're opened or changed.
+type LanguageServer struct {
+	includePaths []string
+	documents    map[string]*document // Open documents by their URI
+
+	writer   io.Writer
+	shutdown bool
+	utf16    bool // Characters of positions are counted in UTF-16 code units instead of characters
+}
+
+func NewLanguageServer(includePaths []string) *LanguageServer {
+	return &LanguageServer{
+		includePaths: includePaths,
+		documents:    map[string]*document{},
+
+		writer:   nil,
+		shutdown: false,
+		utf16:    true,
+	}
+}
+
+// Serves requests read from input until exit notification or end of input. Returns exit code of the server.
+func (ls *LanguageServer) Run(input io.Reader, output io.Writer) int {
+	reader := bufio.NewReader(input)
+	ls.writer = output
+
+	for {
+		content, err := readMessage(reader)
+
+		// Client has closed the input without exiting the server
+		if err != nil {
+			return 1
+		}
+
+		var request message
+		if err := json.Unmarshal(content, &request); err != nil {
+			ls.respondError(nil, PARSE_ERROR, "Failed to parse message. "+err.Error())
+			continue
+		}
+
+		if request.Method == "exit" {
+			if ls.shutdown {
+				return 0
+			}
+			return 1
+		}
+
+		ls.handle(&request)
+	}
+}
+
+func (ls *LanguageServer) handle(request *message) {
+	var result any
+	var err error
+
+	switch request.Method {
+	case "initialize":
+		var params initializeParams
+		if len(request.Params) != 0 {
+			if err = json.Unmarshal(request.Params, &params); err != nil {
+				break
+			}
+		}
+
+		// UTF-16 is the default encoding of the protocol, clients can support counting of characters
+		ls.utf16 = !slices.Contains(params.Capabilities.General.PositionEncodings, "utf-32")
+		positionEncoding := "utf-16"
+		if !ls.utf16 {
+			positionEncoding = "utf-32"
+		}
+
+		result = map[string]any{
+			"capabilities": map[string]any{
+				"positionEncoding":   positionEncoding,
+				"textDocumentSync":   1, // Documents are synchronized by sending their full text
+				"hoverProvider":      true,
+				"definitionProvider": true,
+				"referencesProvider": true,
+				"completionProvider": map[string]any{
+					"triggerCharacters": []string{"."},
+				},
+				"signatureHelpProvider": map[string]any{
+					"triggerCharacters": []string{"(", ","},
+				},
+			},
+			"serverInfo": map[string]any{
+				"name": "neco",
+			},
+		}
+
+	case "shutdown":
+		ls.shutdown = true
+
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err = json.Unmarshal(request.Params, &params); err == nil {
+			ls.openDocument(params.TextDocument.URI, params.TextDocument.Text)
+		}
+
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err = json.Unmarshal(request.Params, &params); err == nil && len(params.ContentChanges) != 0 {
+			ls.openDocument(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err = json.Unmarshal(request.Params, &params); err == nil {
+			delete(ls.documents, params.TextDocument.URI)
+			ls.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{params.TextDocument.URI, []Diagnostic{}})
+		}
+
+	case "textDocument/hover", "textDocument/definition", "textDocument/references", "textDocument/completion", "textDocument/signatureHelp":
+		var params positionParams
+		if err = json.Unmarshal(request.Params, &params); err == nil {
+			result = ls.answer(request.Method, &params)
+		}
+
+	default:
+		// Unknown notifications are ignored
+		if request.ID != nil {
+			ls.respondError(request.ID, METHOD_NOT_FOUND, "Method "+request.Method+" isn't supported.")
+		}
+		return
+	}
+
+	// Notifications don't have a response
+	if request.ID == nil {
+		return
+	}
+
+	if err != nil {
+		ls.respondError(request.ID, INVALID_PARAMS, "Invalid parameters of "+request.Method+". "+err.Error())
+		return
+	}
+
+	writeMessage(ls.writer, response{"2.0", request.ID, result})
+}
+
+// Answers request about position in a document.
+func (ls *LanguageServer) answer(method string, params *positionParams) any {
+	document, exists := ls.documents[params.TextDocument.URI]
+
+	if !exists || document.analysis.index == nil {
+		return nil
+	}
+
+	// Code positions start at 1
+	line, char := params.Position.Line+1, ls.decodeCharacter(params.Position, document.text)+1
+
+	switch method {
+	case "textDocument/hover":
+		return ls.hover(document, line, char)
+
+	case "textDocument/definition":
+		return ls.definition(document, line, char)
+
+	case "textDocument/references":
+		return ls.references(document, line, char, params.Context.IncludeDeclaration)
+
+	case "textDocument/completion":
+		return ls.completion(document, line, char)
+
+	case "textDocument/signatureHelp":
+		return ls.signatureHelp(document, line, char)
+	}
+
+	return nil
+}
+
+// Stores text of document, analyzes it and publishes it's diagnostics.
+func (ls *LanguageServer) openDocument(uri, text string) {
+	path := uriToPath(uri)
+
+	opened, exists := ls.documents[uri]
+	if !exists {
+		opened = &document{path, text, nil}
+		ls.documents[uri] = opened
+	}
+
+	opened.text = text
+	previous := opened.analysis
+	opened.analysis = ls.analyze(opened)
+
+	// Symbols of document with errors, which prevented parsing, are kept from the previous analysis
+	if opened.analysis.index == nil && previous != nil {
+		opened.analysis.module = previous.module
+		opened.analysis.index = previous.index
+		opened.analysis.modules = previous.modules
+	}
+
+	ls.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{uri, opened.analysis.diagnostics})
+}
+
+// Converts range of characters to the position encoding of the client. Text of file at path is read only if it's needed.
+func (ls *LanguageServer) encodeRange(characterRange Range, path string) Range {
+	if !ls.utf16 {
+		return characterRange
+	}
+
+	lines := strings.Split(ls.sourceText(path), "\n")
+
+	characterRange.Start.Character = runesToUtf16(lineAt(lines, characterRange.Start.Line), characterRange.Start.Character)
+	characterRange.End.Character = runesToUtf16(lineAt(lines, characterRange.End.Line), characterRange.End.Character)
+
+	return characterRange
+}
+
+// Converts character of position in the position encoding of the client to character index.
+func (ls *LanguageServer) decodeCharacter(position Position, text string) uint {
+	if !ls.utf16 {
+		return position.Character
+	}
+
+	return utf16ToRunes(lineAt(strings.Split(text, "\n"), position.Line), position.Character)
+}
+
+// Returns text of open document at path, or content of it's file if it isn't open.
+func (ls *LanguageServer) sourceText(path string) string {
+	for _, opened := range ls.documents {
+		if samePath(opened.path, path) {
+			return opened.text
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	return string(content)
+}
+
+func lineAt(lines []string, line uint) string {
+	if line >= uint(len(lines)) {
+		return ""
+	}
+
+	return lines[line]
+}
+
+func (ls *LanguageServer) respondError(id *json.RawMessage, code int, message string) {
+	writeMessage(ls.writer, errorResponse{"2.0", id, responseError{code, message}})
+}
+
+func (ls *LanguageServer) notify(method string, params any) {
+	writeMessage(ls.writer, notification{"2.0", method, params})
+}
+
+func uriToPath(uri string) string {
+	parsed, err := url.Parse(uri)
+
+	if err != nil || parsed.Scheme != "file" {
+		return uri
+	}
+
+	return filepath.FromSlash(parsed.Path)
+}
+
+func pathToUri(path string) string {
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		absolute = path
+	}
+
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(absolute)}).String()
+}
+
+func samePath(path1, path2 string) bool {
+	absolute1, err1 := filepath.Abs(path1)
+	absolute2, err2 := filepath.Abs(path2)
+
+	if err1 != nil || err2 != nil {
+		return filepath.Clean(path1) == filepath.Clean(path2)
+	}
+
+	return absolute1 == absolute2
+}
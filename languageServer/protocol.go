@@ -0,0 +1,263 @@
+package languageServer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// JSON-RPC error codes
+const (
+	PARSE_ERROR      = -32700
+	METHOD_NOT_FOUND = -32601
+	INVALID_PARAMS   = -32602
+)
+
+// Diagnostic severities
+const (
+	DS_Error   = 1
+	DS_Warning = 2
+)
+
+// Completion item kinds
+const (
+	CIK_Function      = 3
+	CIK_Variable      = 6
+	CIK_Module        = 9
+	CIK_Enum          = 13
+	CIK_Struct        = 22
+	CIK_TypeParameter = 25
+)
+
+type message struct {
+	JsonRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+}
+
+type response struct {
+	JsonRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  any              `json:"result"`
+}
+
+type errorResponse struct {
+	JsonRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Error   responseError    `json:"error"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type notification struct {
+	JsonRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type Position struct {
+	Line      uint `json:"line"`
+	Character uint `json:"character"`
+}
+
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+type initializeParams struct {
+	Capabilities struct {
+		General struct {
+			PositionEncodings []string `json:"positionEncodings"`
+		} `json:"general"`
+	} `json:"capabilities"`
+}
+
+type textDocumentItem struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type didOpenParams struct {
+	TextDocument textDocumentItem `json:"textDocument"`
+}
+
+type didChangeParams struct {
+	TextDocument   textDocumentIdentifier `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type didCloseParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type positionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+	Context      struct {
+		IncludeDeclaration bool `json:"includeDeclaration"`
+	} `json:"context"`
+}
+
+type Diagnostic struct {
+	Range    Range  `json:"range"`
+	Severity int    `json:"severity"`
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+type publishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+type Hover struct {
+	Contents markupContent `json:"contents"`
+	Range    Range         `json:"range"`
+}
+
+type markupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type CompletionItem struct {
+	Label  string `json:"label"`
+	Kind   int    `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+type SignatureHelp struct {
+	Signatures      []SignatureInformation `json:"signatures"`
+	ActiveSignature int                    `json:"activeSignature"`
+	ActiveParameter int                    `json:"activeParameter"`
+}
+
+type SignatureInformation struct {
+	Label      string                 `json:"label"`
+	Parameters []ParameterInformation `json:"parameters"`
+}
+
+type ParameterInformation struct {
+	Label string `json:"label"`
+}
+
+// Converts column counted in characters to column counted in UTF-16 code units.
+func runesToUtf16(line string, column uint) uint {
+	units := uint(0)
+
+	for _, character := range line {
+		if column == 0 {
+			return units
+		}
+
+		units += utf16Length(character)
+		column--
+	}
+
+	// Column after the end of the line
+	return units + column
+}
+
+// Converts column counted in UTF-16 code units to column counted in characters.
+func utf16ToRunes(line string, column uint) uint {
+	characters := uint(0)
+
+	for _, character := range line {
+		units := utf16Length(character)
+
+		if column < units {
+			return characters
+		}
+
+		characters++
+		column -= units
+	}
+
+	// Column after the end of the line
+	return characters + column
+}
+
+// Characters outside of the basic multilingual plane are encoded as surrogate pairs.
+func utf16Length(character rune) uint {
+	if utf16.IsSurrogate(character) || character < 0x10000 {
+		return 1
+	}
+
+	return 2
+}
+
+// Reads message framed by a Content-Length header.
+func readMessage(reader *bufio.Reader) ([]byte, error) {
+	contentLength := -1
+
+	// Read headers
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		line = strings.TrimSpace(line)
+
+		// Headers end with an empty line
+		if line == "" {
+			break
+		}
+
+		name, value, _ := strings.Cut(line, ":")
+
+		if strings.EqualFold(name, "Content-Length") {
+			contentLength, err = strconv.Atoi(strings.TrimSpace(value))
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length %s", value)
+			}
+		}
+	}
+
+	if contentLength < 0 {
+		return nil, fmt.Errorf("message is missing Content-Length header")
+	}
+
+	// Read content
+	content := make([]byte, contentLength)
+	_, err := io.ReadFull(reader, content)
+
+	return content, err
+}
+
+// Writes message framed by a Content-Length header.
+func writeMessage(writer io.Writer, value any) error {
+	// Signatures contain arrows, which shouldn't be escaped
+	var content bytes.Buffer
+	encoder := json.NewEncoder(&content)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(writer, "Content-Length: %d\r\n\r\n%s", content.Len(), content.Bytes())
+	return err
+}
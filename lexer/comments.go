@@ -1,43 +1,76 @@
 package lexer
 
-func (l *Lexer) skipComment() {
-	for l.currRune != '\n' && l.currRune != '\r' {
+// Lexes single line comment. Lexer is at the second slash of it.
+func (l *Lexer) lexComment() {
+	startChar := l.charIndex - 1
+	l.token.WriteRune('/')
+
+	for l.currRune != '\n' && l.currRune != '\r' && l.currRune != EOF {
+		l.token.WriteRune(l.currRune)
 		l.advance()
 	}
+
+	l.keepComment(l.lineIndex, startChar)
+}
+
+// Lexes multi line comment. Lexer is at the asterisk of it.
+func (l *Lexer) lexMultiLineComment() {
+	startLine, startChar := l.lineIndex, l.charIndex-1
+	l.token.WriteString("/*")
+	l.advance()
+
+	l.collectMultiLineComment()
+	l.keepComment(startLine, startChar)
+}
+
+// Creates comment token if comments are kept, otherwise the comment is dropped.
+func (l *Lexer) keepComment(startLine, startChar uint) {
+	if l.KeepComments {
+		l.newToken(startLine, startChar, TT_Comment)
+	} else {
+		l.token.Reset()
+	}
 }
 
-func (l *Lexer) skipMultiLineComment() {
+func (l *Lexer) collectMultiLineComment() {
 	for l.currRune != EOF {
 		switch l.currRune {
 
 		// End of comment
 		case '*':
+			l.token.WriteRune(l.currRune)
 			l.advance()
 			if l.currRune == '/' {
+				l.token.WriteRune(l.currRune)
 				l.advance()
 				return
 			}
 
 		// Start of new multiline comment
 		case '/':
+			l.token.WriteRune(l.currRune)
 			l.advance()
 			if l.currRune == '*' {
+				l.token.WriteRune(l.currRune)
 				l.advance()
-				l.skipMultiLineComment()
+				l.collectMultiLineComment()
 			}
 
 		// New line
 		case '\n':
+			l.token.WriteRune(l.currRune)
+			l.advance()
 			l.lineIndex++
 			l.charIndex = 1
-			l.advance()
 
 		// Windows new line
 		case '\r':
+			l.token.WriteRune(l.currRune)
 			l.advance()
 			if l.currRune != '\n' {
 				l.newError(l.lineIndex, l.charIndex-1, true, "Invalid Windows line ending.")
 			} else {
+				l.token.WriteRune(l.currRune)
 				l.advance()
 			}
 
@@ -45,6 +78,7 @@ func (l *Lexer) skipMultiLineComment() {
 			l.charIndex = 1
 
 		default:
+			l.token.WriteRune(l.currRune)
 			l.advance()
 		}
 	}
@@ -9,6 +9,7 @@ var KEYWORDS = map[string]TokenType{
 	"str":   TT_KW_str,
 	"list":  TT_KW_list,
 	"set":   TT_KW_set,
+	"map":   TT_KW_map,
 
 	"loop":     TT_KW_loop,
 	"while":    TT_KW_while,
@@ -17,12 +18,14 @@ var KEYWORDS = map[string]TokenType{
 	"continue": TT_KW_continue,
 	"break":    TT_KW_break,
 	"in":       TT_OP_In,
+	"step":     TT_OP_Step,
 
 	"pub":    TT_KW_pub,
 	"fun":    TT_KW_fun,
 	"return": TT_KW_return,
 
 	"struct": TT_KW_struct,
+	"class":  TT_KW_class,
 	"enum":   TT_KW_enum,
 
 	"if":   TT_KW_if,
@@ -34,6 +37,10 @@ var KEYWORDS = map[string]TokenType{
 	"match":   TT_KW_match,
 	"default": TT_KW_default,
 
+	"try":   TT_KW_try,
+	"catch": TT_KW_catch,
+	"throw": TT_KW_throw,
+
 	"import": TT_KW_import,
 }
 
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"unicode"
@@ -43,7 +44,7 @@ func isTokenBreaker(char rune) bool {
 
 type Lexer struct {
 	filePath string
-	file     *os.File
+	file     io.ReadCloser
 	reader   *bufio.Reader
 	fileOpen bool
 
@@ -56,7 +57,8 @@ type Lexer struct {
 	token  bytes.Buffer
 	tokens []*Token
 
-	ErrorCount uint
+	ErrorCount   uint
+	KeepComments bool // Comments are lexed as comment tokens instead of being skipped
 }
 
 func NewLexer(filePath string) Lexer {
@@ -72,9 +74,19 @@ func NewLexer(filePath string) Lexer {
 		bytes.Buffer{},
 		make([]*Token, 0, 100),
 		0,
+		false,
 	}
 }
 
+// Creates lexer reading source from already opened file. File is closed after it's read.
+func NewLexerFromFile(filePath string, file io.ReadCloser) Lexer {
+	lexer := NewLexer(filePath)
+	lexer.file = file
+	lexer.fileOpen = true
+
+	return lexer
+}
+
 func (l *Lexer) openFile() {
 	file, err := os.Open(l.filePath)
 
@@ -104,7 +116,9 @@ func (l *Lexer) setModuleName() {
 
 func (l *Lexer) Lex() []*Token {
 	// Create reader
-	l.openFile()
+	if !l.fileOpen {
+		l.openFile()
+	}
 	l.setModuleName()
 
 	// Insert StartOfFile token
@@ -147,6 +161,31 @@ func (l *Lexer) newError(line, char uint, useTokenLength bool, message string) {
 	}
 }
 
+// Returns the rest of the line after current rune without reading it. Returns false if the line is longer than buffer of the reader and was cut.
+func (l *Lexer) peekLine() ([]rune, bool) {
+	if l.nextRune == '\n' || l.nextRune == '\r' || l.nextRune == EOF {
+		return []rune{}, true
+	}
+
+	line := []rune{l.nextRune}
+
+	if !l.fileOpen {
+		return line, true
+	}
+
+	for size := 64; ; size *= 2 {
+		buffered, err := l.reader.Peek(size)
+
+		if end := bytes.IndexAny(buffered, "\r\n"); end != -1 {
+			return append(line, []rune(string(buffered[:end]))...), true
+		}
+
+		if err != nil {
+			return append(line, []rune(string(buffered))...), err != bufio.ErrBufferFull
+		}
+	}
+}
+
 func (l *Lexer) advance() {
 	// Don't advance when file is closed
 	if !l.fileOpen {
@@ -293,11 +332,9 @@ func (l *Lexer) lexRune() {
 				l.advance()
 				l.newTokenFrom(l.lineIndex, l.charIndex-2, TT_KW_DivideAssign, "")
 			} else if l.currRune == '/' { // //
-				l.advance()
-				l.skipComment()
+				l.lexComment()
 			} else if l.currRune == '*' { // /*
-				l.advance()
-				l.skipMultiLineComment()
+				l.lexMultiLineComment()
 			} else { // /
 				l.newTokenFrom(l.lineIndex, l.charIndex-1, TT_OP_Divide, "")
 			}
@@ -333,7 +370,17 @@ func (l *Lexer) lexRune() {
 			l.newTokenFrom(l.lineIndex, l.charIndex-1, TT_OP_Or, "")
 		case '.':
 			l.advance()
-			l.newTokenFrom(l.lineIndex, l.charIndex-1, TT_OP_Dot, "")
+			if l.currRune == '.' {
+				l.advance()
+				if l.currRune == '=' { // ..=
+					l.advance()
+					l.newTokenFrom(l.lineIndex, l.charIndex-3, TT_OP_RangeInclusive, "")
+				} else { // ..
+					l.newTokenFrom(l.lineIndex, l.charIndex-2, TT_OP_Range, "")
+				}
+			} else { // .
+				l.newTokenFrom(l.lineIndex, l.charIndex-1, TT_OP_Dot, "")
+			}
 		case '?':
 			l.advance()
 			if l.currRune == '!' {
@@ -46,11 +46,11 @@ func (l *Lexer) lexNumber() {
 			l.advance()
 			break
 			// Float
-		} else if l.currRune == '.' {
+		} else if l.currRune == '.' && l.nextRune != '.' {
 			l.lexFloat(startLine, startChar)
 			return
 			// End of number
-		} else if isTokenBreaker(l.currRune) {
+		} else if isTokenBreaker(l.currRune) || l.currRune == '.' {
 			break
 			// Invalid character
 		} else {
@@ -74,8 +74,8 @@ func (l *Lexer) lexNumber() {
 		l.advance()
 	}
 
-	// Create token
-	if isTokenBreaker(l.currRune) {
+	// Create token (number can be followed by a range operator)
+	if isTokenBreaker(l.currRune) || l.currRune == '.' && l.nextRune == '.' {
 		l.newToken(startLine, startChar, TT_LT_Int)
 		return
 		// Float
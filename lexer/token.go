@@ -12,6 +12,7 @@ const (
 	TT_EndOfCommand TokenType = iota
 	TT_StartOfFile
 	TT_EndOfFile
+	TT_Comment
 
 	TT_Identifier
 
@@ -47,6 +48,9 @@ const (
 	TT_OP_Greater
 	TT_OP_GreaterEqual
 	TT_OP_In
+	TT_OP_Range
+	TT_OP_RangeInclusive
+	TT_OP_Step
 
 	TT_OP_Ternary
 
@@ -65,6 +69,7 @@ const (
 	TT_KW_str
 	TT_KW_list
 	TT_KW_set
+	TT_KW_map
 
 	TT_KW_Assign
 	TT_KW_AddAssign
@@ -100,6 +105,10 @@ const (
 	TT_KW_default
 	TT_KW_CaseIs
 
+	TT_KW_try
+	TT_KW_catch
+	TT_KW_throw
+
 	TT_KW_import
 )
 
@@ -107,6 +116,7 @@ var TokenTypeToString = map[TokenType]string{
 	TT_EndOfCommand: "EndOfCommand",
 	TT_StartOfFile:  "StartOfFile",
 	TT_EndOfFile:    "EndOfFile",
+	TT_Comment:      "Comment",
 
 	TT_Identifier: "Identifier",
 
@@ -155,6 +165,8 @@ var TokenTypeToString = map[TokenType]string{
 	TT_KW_flt:    "flt",
 	TT_KW_str:    "str",
 	TT_KW_list:   "list",
+	TT_KW_set:    "set",
+	TT_KW_map:    "map",
 
 	TT_KW_Assign:         "=",
 	TT_KW_AddAssign:      "+=",
@@ -165,6 +177,10 @@ var TokenTypeToString = map[TokenType]string{
 	TT_KW_ModuloAssign:   "%=",
 	TT_OP_In:             "in",
 
+	TT_OP_Range:          "..",
+	TT_OP_RangeInclusive: "..=",
+	TT_OP_Step:           "step",
+
 	TT_OP_Ternary: "??",
 
 	TT_KW_loop:     "loop",
@@ -193,6 +209,10 @@ var TokenTypeToString = map[TokenType]string{
 	TT_KW_default: "default",
 	TT_KW_CaseIs:  "=>",
 
+	TT_KW_try:   "try",
+	TT_KW_catch: "catch",
+	TT_KW_throw: "throw",
+
 	TT_KW_import: "import",
 }
 
@@ -201,7 +221,7 @@ func (tt TokenType) String() string {
 }
 
 func (tt TokenType) IsVariableType() bool {
-	return tt >= TT_KW_var && tt <= TT_KW_set
+	return tt >= TT_KW_var && tt <= TT_KW_map
 }
 
 func (tt TokenType) IsLiteral() bool {
@@ -229,7 +249,7 @@ func (tt TokenType) IsDelimiter() bool {
 }
 
 func (tt TokenType) IsCompositeType() bool {
-	return tt >= TT_KW_list && tt <= TT_KW_set
+	return tt >= TT_KW_list && tt <= TT_KW_map
 }
 
 func (tt TokenType) CanBeExpression() bool {
@@ -265,7 +285,7 @@ func (t *Token) String() string {
 		return "SOF"
 	case TT_EndOfFile:
 		return "EOF"
-	case TT_Identifier:
+	case TT_Identifier, TT_Comment:
 		return t.Value
 	case TT_LT_Bool:
 		if t.Value == "0" {
@@ -1,6 +1,7 @@
 package lexer
 
 import (
+	"strings"
 	"unicode"
 )
 
@@ -45,8 +46,13 @@ func (l *Lexer) lexLetter() {
 func (l *Lexer) lexString() {
 	startLine := l.lineIndex
 	startChar := l.charIndex
+	partStartChar := startChar + 1
 	l.advance()
 
+	// Interpolated strings are lexed as concatenation of their parts in parentheses
+	isInterpolated := false
+	hasParts := false
+
 	// Collect string
 	for l.currRune != '"' {
 		// New line in string
@@ -68,6 +74,42 @@ func (l *Lexer) lexString() {
 			l.charIndex = 1
 
 			l.newToken(startLine, startChar, TT_LT_String)
+			return
+		} else if l.currRune == EOF {
+			l.newError(startLine, startChar, false, "String is missing a closing quote.")
+			l.newToken(startLine, startChar, TT_LT_String)
+			return
+		}
+
+		// Interpolated expression
+		if l.currRune == '{' {
+			if !isInterpolated {
+				l.newTokenFrom(startLine, startChar, TT_DL_ParenthesisOpen, "")
+				isInterpolated = true
+			}
+
+			// Collected string before expression
+			if l.token.Len() != 0 {
+				if hasParts {
+					l.newTokenFrom(l.lineIndex, partStartChar, TT_OP_Add, "")
+				}
+
+				l.newToken(l.lineIndex, partStartChar, TT_LT_String)
+				hasParts = true
+			}
+
+			if hasParts {
+				l.newTokenFrom(l.lineIndex, l.charIndex, TT_OP_Add, "")
+			}
+
+			// Unterminated expression ends the string
+			if !l.lexInterpolatedExpression() {
+				l.newTokenFrom(l.lineIndex, l.charIndex, TT_DL_ParenthesisClose, "")
+				return
+			}
+
+			hasParts = true
+			partStartChar = l.charIndex
 			continue
 		}
 
@@ -93,6 +135,10 @@ func (l *Lexer) lexString() {
 					l.token.WriteRune('\v')
 				case '"':
 					l.token.WriteRune('"')
+				case '{':
+					l.token.WriteRune('{')
+				case '}':
+					l.token.WriteRune('}')
 				default:
 					l.newError(l.lineIndex, l.charIndex, false, "Invalid escape sequence.")
 				}
@@ -112,5 +158,122 @@ func (l *Lexer) lexString() {
 	}
 	l.advance()
 
-	l.newToken(startLine, startChar, TT_LT_String)
+	// Simple string
+	if !isInterpolated {
+		l.newToken(startLine, startChar, TT_LT_String)
+		return
+	}
+
+	// Collected string after last expression
+	if l.token.Len() != 0 {
+		l.newTokenFrom(l.lineIndex, l.charIndex-1, TT_OP_Add, "")
+		l.newToken(l.lineIndex, partStartChar, TT_LT_String)
+	}
+
+	l.newTokenFrom(l.lineIndex, l.charIndex-1, TT_DL_ParenthesisClose, "")
+}
+
+func (l *Lexer) lexInterpolatedExpression() bool {
+	braceLine := l.lineIndex
+	braceChar := l.charIndex
+
+	// Check expression before lexing it, so an unterminated expression doesn't consume the rest of the line
+	line, isWhole := l.peekLine()
+	end := expressionEnd(line, 0)
+
+	// Unterminated expression, string is expected to end at the next quote or at the end of the line
+	if end == -1 && isWhole {
+		l.newError(braceLine, braceChar, false, "Interpolated expression is missing a closing brace.")
+		l.newTokenFrom(braceLine, braceChar, TT_LT_String, "")
+
+		for l.currRune != '"' && l.currRune != '\n' && l.currRune != '\r' && l.currRune != EOF {
+			l.advance()
+		}
+
+		return l.currRune == '"'
+	}
+
+	// Empty expression is replaced by an empty string
+	if end != -1 && strings.TrimSpace(string(line[:end])) == "" {
+		l.newError(braceLine, braceChar, false, "Interpolated expression is empty. Use \\{ to insert a brace.")
+		l.newTokenFrom(braceLine, braceChar, TT_LT_String, "")
+
+		for l.currRune != '}' {
+			l.advance()
+		}
+		l.advance() // }
+
+		return true
+	}
+
+	l.advance() // {
+
+	// Expression is converted to string by str()
+	l.newTokenFrom(braceLine, braceChar, TT_KW_str, "")
+	l.newTokenFrom(braceLine, braceChar, TT_DL_ParenthesisOpen, "")
+
+	// Lex tokens of expression until closing brace
+	depth := 0
+	for l.currRune != '}' || depth != 0 {
+		// Unterminated expression
+		if l.currRune == EOF || l.currRune == '\n' || l.currRune == '\r' || l.lineIndex != braceLine {
+			l.newError(braceLine, braceChar, false, "Interpolated expression is missing a closing brace.")
+			l.newTokenFrom(l.lineIndex, l.charIndex, TT_DL_ParenthesisClose, "")
+			return false
+		}
+
+		if l.currRune == '{' {
+			depth++
+		} else if l.currRune == '}' {
+			depth--
+		}
+
+		l.lexRune()
+	}
+
+	l.newTokenFrom(l.lineIndex, l.charIndex, TT_DL_ParenthesisClose, "")
+	l.advance() // }
+
+	return true
+}
+
+// Finds index of brace closing interpolated expression, which starts at index start of line. Returns -1 if the expression isn't closed on the line.
+func expressionEnd(line []rune, start int) int {
+	depth := 0
+
+	for i := start; i < len(line); i++ {
+		switch line[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case '"':
+			if i = stringEnd(line, i+1); i == -1 {
+				return -1
+			}
+		}
+	}
+
+	return -1
+}
+
+// Finds index of quote closing string, which starts at index start of line. Returns -1 if the string isn't closed on the line.
+func stringEnd(line []rune, start int) int {
+	for i := start; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		case '{':
+			if i = expressionEnd(line, i+1); i == -1 {
+				return -1
+			}
+		}
+	}
+
+	return -1
 }
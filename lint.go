@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DanielNos/neco/errors"
+	"github.com/DanielNos/neco/lexer"
+	"github.com/DanielNos/neco/linter"
+	"github.com/DanielNos/neco/logger"
+	"github.com/DanielNos/neco/moduleLoader"
+	"github.com/DanielNos/neco/parser"
+)
+
+// Lints target module and prints found lints. Returns exit code.
+func lint(configuration *Configuration) int {
+	startTime := time.Now()
+
+	// Tokenize and analyze syntax of target and imported modules
+	loader := moduleLoader.NewModuleLoader(configuration.IncludePaths)
+	module := loader.Load(configuration.TargetPath)
+
+	if errorCount := loader.LexicalErrorCount + loader.SyntaxErrorCount; errorCount != 0 {
+		logger.Fatal(errors.SYNTAX, fmt.Sprintf("😿 Linting failed with %d error/s.", errorCount))
+	}
+
+	// Parse and collect warnings and symbols
+	warnings := []parser.Warning{}
+
+	p := parser.NewParser(loader.TokenLists(), 0, false)
+	p.Index = parser.NewSymbolIndex()
+	p.Warnings = &warnings
+	p.Parse()
+
+	if p.ErrorCount != 0 {
+		logger.Fatal(errors.SEMANTIC, fmt.Sprintf("😿 Linting failed with %d error/s.", p.ErrorCount))
+	}
+
+	// Lex target again with comments, which can suppress lints
+	commentLexer := lexer.NewLexer(module.Path)
+	commentLexer.KeepComments = true
+
+	lints := configuration.Linter.Lint(module.Name, p.Index, warnings, commentLexer.Lex())
+
+	for _, lint := range lints {
+		if lint.Position == nil {
+			logger.Warning(lint.Message + " [" + lint.Rule + "]")
+		} else {
+			logger.WarningCodePos(lint.Position, lint.Message+" ["+lint.Rule+"]")
+		}
+	}
+
+	if len(lints) != 0 {
+		logger.Error(fmt.Sprintf("😿 Found %d lint/s in %s.", len(lints), time.Since(startTime)))
+		return errors.LINTS
+	}
+
+	logger.Success(fmt.Sprintf("😺 Linting completed in %s.", time.Since(startTime)))
+	return 0
+}
+
+func printLintRules() {
+	fmt.Println("Rule                 Description")
+	fmt.Println()
+
+	for _, rule := range linter.RULES {
+		fmt.Printf("%-20s %s\n", rule.ID, rule.Description)
+	}
+}
@@ -0,0 +1,276 @@
+package linter
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	data "github.com/DanielNos/neco/dataStructures"
+	"github.com/DanielNos/neco/lexer"
+	"github.com/DanielNos/neco/parser"
+)
+
+// Comment suppressing lints of it's line, or of the next line if it's on it's own line
+const SUPPRESSION = "lint:ignore"
+
+// Rules checked by the linter, other rules are checked by the parser
+const (
+	R_UnusedVariable  = "unused-variable"
+	R_UnusedParameter = "unused-parameter"
+	R_UnusedImport    = "unused-import"
+	R_Naming          = "naming"
+)
+
+type Rule struct {
+	ID          string
+	Description string
+}
+
+var RULES = []Rule{
+	{R_UnusedVariable, "Local variables that are never used."},
+	{R_UnusedParameter, "Parameters that are never used."},
+	{R_UnusedImport, "Imported modules that are never used."},
+	{parser.W_UnusedFunction, "Functions that are never called."},
+	{parser.W_Shadowing, "Variables that shadow variables of outer scopes."},
+	{parser.W_ConstantCondition, "Conditions that consist only of literals."},
+	{parser.W_EmptyBlock, "Blocks without statements or comments."},
+	{parser.W_RedundantDefault, "Operators ?! that always return their default."},
+	{parser.W_UnnecessaryDefault, "Default cases of match statements that can't be reached."},
+	{parser.W_EmptyStruct, "Structs without fields."},
+	{parser.W_MissingEntry, "Programs without an entry() function."},
+	{R_Naming, "Types that aren't in PascalCase, constants that aren't in UPPER_CASE or camelCase and other symbols that aren't in camelCase."},
+}
+
+type Lint struct {
+	Rule     string
+	Position *data.CodePos // Nil for lints about the whole program
+	Message  string
+}
+
+type Linter struct {
+	enabled map[string]bool
+}
+
+// Creates linter with all rules enabled.
+func NewLinter() *Linter {
+	linter := &Linter{map[string]bool{}}
+	linter.SetEnabled("all", true)
+
+	return linter
+}
+
+// Enables or disables rule. Rule all changes every rule. Returns false if the rule doesn't exist.
+func (l *Linter) SetEnabled(rule string, enabled bool) bool {
+	for _, existing := range RULES {
+		if rule == "all" || rule == existing.ID {
+			l.enabled[existing.ID] = enabled
+
+			if rule != "all" {
+				return true
+			}
+		}
+	}
+
+	return rule == "all"
+}
+
+// Collects lints of module from it's parsed symbols and warnings of the parser. Tokens of the module have to include comments, which can suppress lints.
+func (l *Linter) Lint(module string, index *parser.SymbolIndex, warnings []parser.Warning, tokens []*lexer.Token) []Lint {
+	lints := []Lint{}
+
+	for _, warning := range warnings {
+		if warning.Position == nil || *warning.Position.File == module {
+			lints = append(lints, Lint{warning.Rule, warning.Position, warning.Message})
+		}
+	}
+
+	// Modules are used through their symbols
+	usedModules := map[string]bool{}
+
+	for _, use := range index.Uses {
+		if *use.Position.File == module && use.Declaration.Position != nil {
+			usedModules[*use.Declaration.Position.File] = true
+		}
+	}
+
+	for _, declaration := range index.Declarations {
+		if declaration.Position == nil || *declaration.Position.File != module {
+			continue
+		}
+
+		isUsed := len(index.References(declaration)) != 0
+		if declaration.Kind == "module" {
+			isUsed = isUsed || usedModules[declaration.Identifier]
+		}
+
+		lints = append(lints, lintDeclaration(declaration, isUsed)...)
+	}
+
+	suppressed := suppressedRules(tokens)
+	reported := map[string]bool{}
+	filtered := []Lint{}
+
+	for _, lint := range lints {
+		// Parser can check the same code multiple times
+		key := lint.Rule + " " + lint.Message
+		if lint.Position != nil {
+			key += " " + lint.Position.String()
+		}
+
+		if !l.enabled[lint.Rule] || reported[key] || isSuppressed(lint, suppressed) {
+			continue
+		}
+
+		// Blocks with comments are meant to be empty
+		if lint.Rule == parser.W_EmptyBlock && containsComment(lint.Position, tokens) {
+			continue
+		}
+
+		reported[key] = true
+		filtered = append(filtered, lint)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		a, b := filtered[i].Position, filtered[j].Position
+
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		return a.StartLine < b.StartLine || a.StartLine == b.StartLine && a.StartChar < b.StartChar
+	})
+
+	return filtered
+}
+
+func lintDeclaration(declaration *parser.Declaration, isUsed bool) []Lint {
+	lints := []Lint{}
+	identifier := declaration.Identifier
+
+	switch declaration.Kind {
+	case "variable":
+		// Identifiers starting with an underscore are unused on purpose
+		if isUsed || declaration.IsGlobal || identifier == "self" || strings.HasPrefix(identifier, "_") {
+			break
+		}
+
+		if declaration.IsParameter {
+			lints = append(lints, Lint{R_UnusedParameter, declaration.Position, "Parameter " + identifier + " is never used."})
+		} else {
+			lints = append(lints, Lint{R_UnusedVariable, declaration.Position, "Variable " + identifier + " is never used."})
+		}
+
+	case "module":
+		if !isUsed {
+			lints = append(lints, Lint{R_UnusedImport, declaration.Position, "Module " + identifier + " is imported, but never used."})
+		}
+		return lints
+	}
+
+	// Methods are named after their struct
+	name := strings.TrimLeft(identifier[strings.LastIndex(identifier, ".")+1:], "_")
+
+	if name == "" {
+		return lints
+	}
+
+	switch {
+	case declaration.Kind == "struct" || declaration.Kind == "enum" || declaration.Kind == "type parameter":
+		if !isPascalCase(name) {
+			lints = append(lints, Lint{R_Naming, declaration.Position, strings.ToUpper(declaration.Kind[:1]) + declaration.Kind[1:] + " " + identifier + " should be in PascalCase."})
+		}
+
+	case strings.HasPrefix(declaration.Description, "const "):
+		if !isUpperCase(name) && !isCamelCase(name) {
+			lints = append(lints, Lint{R_Naming, declaration.Position, "Constant " + identifier + " should be in UPPER_CASE or camelCase."})
+		}
+
+	case declaration.Kind == "variable" || declaration.Kind == "function":
+		if !isCamelCase(name) {
+			lints = append(lints, Lint{R_Naming, declaration.Position, strings.ToUpper(declaration.Kind[:1]) + declaration.Kind[1:] + " " + identifier + " should be in camelCase."})
+		}
+	}
+
+	return lints
+}
+
+func isPascalCase(name string) bool {
+	return unicode.IsUpper([]rune(name)[0]) && !strings.Contains(name, "_")
+}
+
+func isCamelCase(name string) bool {
+	return unicode.IsLower([]rune(name)[0]) && !strings.Contains(name, "_")
+}
+
+func isUpperCase(name string) bool {
+	return strings.ToUpper(name) == name && unicode.IsLetter([]rune(name)[0])
+}
+
+// Collects rules suppressed on lines by comments. Empty rule list suppresses all rules.
+func suppressedRules(tokens []*lexer.Token) map[uint][]string {
+	suppressed := map[uint][]string{}
+	linesWithCode := map[uint]bool{}
+
+	for _, token := range tokens {
+		switch token.TokenType {
+		case lexer.TT_StartOfFile, lexer.TT_EndOfFile, lexer.TT_EndOfCommand:
+			continue
+
+		case lexer.TT_Comment:
+			text := strings.TrimSpace(strings.TrimSuffix(strings.TrimLeft(token.Value, "/*"), "*/"))
+
+			if !strings.HasPrefix(text, SUPPRESSION) {
+				continue
+			}
+
+			rules := strings.FieldsFunc(text[len(SUPPRESSION):], func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+
+			// Comment on it's own line suppresses the next line
+			line := token.Position.StartLine
+			if !linesWithCode[line] {
+				line = token.Position.EndLine + 1
+			}
+
+			suppressed[line] = append(suppressed[line], rules...)
+			if len(rules) == 0 {
+				suppressed[line] = append(suppressed[line], "all")
+			}
+
+		default:
+			linesWithCode[token.Position.EndLine] = true
+		}
+	}
+
+	return suppressed
+}
+
+func isSuppressed(lint Lint, suppressed map[uint][]string) bool {
+	if lint.Position == nil {
+		return false
+	}
+
+	for _, rule := range suppressed[lint.Position.StartLine] {
+		if rule == "all" || rule == lint.Rule {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsComment(position *data.CodePos, tokens []*lexer.Token) bool {
+	for _, token := range tokens {
+		if token.TokenType != lexer.TT_Comment {
+			continue
+		}
+
+		start := token.Position
+		isAfterStart := start.StartLine > position.StartLine || start.StartLine == position.StartLine && start.StartChar > position.StartChar
+		isBeforeEnd := start.StartLine < position.EndLine || start.StartLine == position.EndLine && start.StartChar < position.EndChar
+
+		if isAfterStart && isBeforeEnd {
+			return true
+		}
+	}
+
+	return false
+}
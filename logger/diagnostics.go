@@ -0,0 +1,36 @@
+package logger
+
+import data "github.com/DanielNos/neco/dataStructures"
+
+// Message with position in code, which is collected instead of being printed.
+type Diagnostic struct {
+	Position *data.CodePos
+	Message  string
+	IsError  bool
+}
+
+// Value of panic raised by Fatal while diagnostics are collected.
+type Abort struct {
+	ErrorCode int
+	Message   string
+}
+
+// If set, warnings and errors with positions are appended to it and nothing is printed. Fatal panics with Abort instead of exiting.
+var Diagnostics *[]Diagnostic
+
+// Starts collecting diagnostics. Returns slice, which collects them.
+func CollectDiagnostics() *[]Diagnostic {
+	diagnostics := []Diagnostic{}
+	Diagnostics = &diagnostics
+
+	return Diagnostics
+}
+
+// Stops collecting diagnostics.
+func StopCollecting() {
+	Diagnostics = nil
+}
+
+func collect(codePos *data.CodePos, message string, isError bool) {
+	*Diagnostics = append(*Diagnostics, Diagnostic{codePos, message, isError})
+}
@@ -57,7 +57,7 @@ func readLine(filePath string, lineIndex uint) (string, error) {
 }
 
 func Success(message string) {
-	if LoggingLevel > LL_Success {
+	if LoggingLevel > LL_Success || Diagnostics != nil {
 		return
 	}
 
@@ -69,7 +69,7 @@ func Success(message string) {
 }
 
 func Info(message string) {
-	if LoggingLevel > LL_Info {
+	if LoggingLevel > LL_Info || Diagnostics != nil {
 		return
 	}
 
@@ -80,7 +80,7 @@ func Info(message string) {
 }
 
 func Warning(message string) {
-	if LoggingLevel > LL_Warning {
+	if LoggingLevel > LL_Warning || Diagnostics != nil {
 		return
 	}
 
@@ -96,6 +96,11 @@ func WarningPos(file *string, line, startChar, endChar uint, message string) {
 		return
 	}
 
+	if Diagnostics != nil {
+		collect(&data.CodePos{File: file, StartLine: line, EndLine: line, StartChar: startChar, EndChar: endChar}, message, false)
+		return
+	}
+
 	// Read line
 	lineString, err := readLine(*file, line)
 
@@ -136,11 +141,16 @@ func WarningPos(file *string, line, startChar, endChar uint, message string) {
 }
 
 func WarningCodePos(codePos *data.CodePos, message string) {
+	if Diagnostics != nil {
+		collect(codePos, message, false)
+		return
+	}
+
 	WarningPos(codePos.File, codePos.StartLine, codePos.StartChar, codePos.EndChar, message)
 }
 
 func Error(message string) {
-	if LoggingLevel > LL_Error {
+	if LoggingLevel > LL_Error || Diagnostics != nil {
 		return
 	}
 
@@ -156,6 +166,11 @@ func ErrorPos(file *string, line, startChar, endChar uint, message string) {
 		return
 	}
 
+	if Diagnostics != nil {
+		collect(&data.CodePos{File: file, StartLine: line, EndLine: line, StartChar: startChar, EndChar: endChar}, message, true)
+		return
+	}
+
 	// Read line
 	lineString, err := readLine(*file, line)
 
@@ -196,6 +211,11 @@ func ErrorPos(file *string, line, startChar, endChar uint, message string) {
 }
 
 func ErrorCodePos(codePos *data.CodePos, message string) {
+	if Diagnostics != nil {
+		collect(codePos, message, true)
+		return
+	}
+
 	ErrorPos(codePos.File, codePos.StartLine, codePos.StartChar, codePos.EndChar, message)
 }
 
@@ -204,6 +224,12 @@ func Error2CodePos(codePos1, codePos2 *data.CodePos, message string) {
 		return
 	}
 
+	if Diagnostics != nil {
+		// Error is reported once, spanning both positions
+		collect(codePos1.Combine(codePos2), message, true)
+		return
+	}
+
 	// Read line
 	lineString, err := readLine(*codePos1.File, codePos1.StartLine)
 
@@ -260,6 +286,10 @@ func Error2CodePos(codePos1, codePos2 *data.CodePos, message string) {
 }
 
 func Fatal(error_code int, message string) {
+	if Diagnostics != nil {
+		panic(Abort{error_code, message})
+	}
+
 	if LoggingLevel > LL_Fatal {
 		os.Exit(error_code)
 	}
@@ -2,16 +2,17 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/fatih/color"
 
 	codeGen "github.com/DanielNos/neco/codeGenerator"
 	"github.com/DanielNos/neco/errors"
-	"github.com/DanielNos/neco/lexer"
+	"github.com/DanielNos/neco/languageServer"
 	"github.com/DanielNos/neco/logger"
+	"github.com/DanielNos/neco/moduleLoader"
 	"github.com/DanielNos/neco/parser"
-	"github.com/DanielNos/neco/syntaxAnalyzer"
 	VM "github.com/DanielNos/neco/virtualMachine"
 )
 
@@ -31,11 +32,30 @@ func printHelp() {
 	fmt.Println("                 -l  --log-level [LEVEL] Sets logging level. Possible values are 0 to 5 or level names.")
 	fmt.Println("                 -o  --out               Sets output file path.")
 	fmt.Println("                 -c  --constants         Prints constants stored in binary.")
-	fmt.Println("\nrun [target]")
+	fmt.Println("                 -I  --include [PATH]    Adds directory searched for imported modules.")
+	fmt.Println("                 --                      Passes following arguments to the program.")
+	fmt.Println("\nrun [target] [arguments]")
+	fmt.Println("\ndisasm [target]")
+	fmt.Println("\nrepl")
+	fmt.Println("\nlsp")
+	fmt.Println("                 -I  --include [PATH] Adds directory searched for imported modules.")
+	fmt.Println("\nfmt [targets]")
+	fmt.Println("                 --check              Lists files, which aren't formatted, and fails if there are any.")
+	fmt.Println("                 -w  --write          Overwrites files with their formatted code.")
+	fmt.Println("                 -I  --include [PATH] Adds directory searched for imported modules.")
+	fmt.Println("\nlint [target]")
+	fmt.Println("                 -e  --enable [RULES]    Enables comma separated rules, all enables every rule.")
+	fmt.Println("                 -d  --disable [RULES]   Disables comma separated rules, all disables every rule.")
+	fmt.Println("                 -s  --silent            Doesn't produce info messages when possible.")
+	fmt.Println("                 -n  --no-log            Doesn't produce any log messages.")
+	fmt.Println("                 -l  --log-level [LEVEL] Sets logging level. Possible values are 0 to 5 or level names.")
+	fmt.Println("                 -I  --include [PATH]    Adds directory searched for imported modules.")
+	fmt.Println("                 --rules                 Lists rules of linter.")
 	fmt.Println("\nanalyze [target]")
 	fmt.Println("                 -to --tokens        Prints lexed tokens.")
 	fmt.Println("                 -tr --tree          Draws abstract syntax tree.")
 	fmt.Println("                 -d  --dontOptimize  Compiler won't optimize byte code.")
+	fmt.Println("                 -I  --include [PATH] Adds directory searched for imported modules.")
 }
 
 func analyze(configuration *Configuration) (*parser.Node, *parser.Parser) {
@@ -44,24 +64,21 @@ func analyze(configuration *Configuration) (*parser.Node, *parser.Parser) {
 		action = "Compilation"
 	}
 
-	// Tokenize
-	lexer := lexer.NewLexer(configuration.TargetPath)
-	tokens := lexer.Lex()
+	// Tokenize and analyze syntax of target and imported modules
+	moduleLoader := moduleLoader.NewModuleLoader(configuration.IncludePaths)
+	moduleLoader.Load(configuration.TargetPath)
+	tokens := moduleLoader.Tokens()
 
 	exitCode := 0
-	if lexer.ErrorCount != 0 {
-		logger.Error(fmt.Sprintf("Lexical analysis failed with %d error/s.", lexer.ErrorCount))
+	if moduleLoader.LexicalErrorCount != 0 {
+		logger.Error(fmt.Sprintf("Lexical analysis failed with %d error/s.", moduleLoader.LexicalErrorCount))
 		exitCode = errors.LEXICAL
 	} else {
 		logger.Success("Passed lexical analysis.")
 	}
 
-	// Analyze syntax
-	syntaxAnalyzer := syntaxAnalyzer.NewSyntaxAnalyzer(tokens, lexer.ErrorCount)
-	tokens = syntaxAnalyzer.Analyze()
-
-	if syntaxAnalyzer.ErrorCount != 0 {
-		logger.Error(fmt.Sprintf("Syntax analysis failed with %d error/s.", syntaxAnalyzer.ErrorCount))
+	if moduleLoader.SyntaxErrorCount != 0 {
+		logger.Error(fmt.Sprintf("Syntax analysis failed with %d error/s.", moduleLoader.SyntaxErrorCount))
 
 		// Print tokens
 		if configuration.PrintTokens {
@@ -71,16 +88,16 @@ func analyze(configuration *Configuration) (*parser.Node, *parser.Parser) {
 
 		// Exit with correct return code
 		if exitCode == 0 {
-			logger.Fatal(errors.SYNTAX, fmt.Sprintf("😿 %s failed with %d error/s.", action, lexer.ErrorCount+syntaxAnalyzer.ErrorCount))
+			logger.Fatal(errors.SYNTAX, fmt.Sprintf("😿 %s failed with %d error/s.", action, moduleLoader.LexicalErrorCount+moduleLoader.SyntaxErrorCount))
 		} else {
-			logger.Fatal(exitCode, fmt.Sprintf("😿 %s failed with %d error/s.", action, lexer.ErrorCount+syntaxAnalyzer.ErrorCount))
+			logger.Fatal(exitCode, fmt.Sprintf("😿 %s failed with %d error/s.", action, moduleLoader.LexicalErrorCount+moduleLoader.SyntaxErrorCount))
 		}
 	} else {
 		logger.Success("Passed syntax analysis.")
 	}
 
 	// Construct AST
-	p := parser.NewParser(tokens, syntaxAnalyzer.ErrorCount, configuration.Optimize)
+	p := parser.NewParser(moduleLoader.TokenLists(), moduleLoader.LexicalErrorCount+moduleLoader.SyntaxErrorCount, configuration.Optimize)
 	tree := p.Parse()
 
 	// Print info
@@ -108,7 +125,7 @@ func analyze(configuration *Configuration) (*parser.Node, *parser.Parser) {
 	}
 
 	if exitCode != 0 {
-		logger.Fatal(exitCode, fmt.Sprintf("😿 %s failed with %d error/s.", action, lexer.ErrorCount+syntaxAnalyzer.ErrorCount+p.ErrorCount))
+		logger.Fatal(exitCode, fmt.Sprintf("😿 %s failed with %d error/s.", action, moduleLoader.LexicalErrorCount+moduleLoader.SyntaxErrorCount+p.ErrorCount))
 	}
 
 	return tree, &p
@@ -156,7 +173,7 @@ func buildAndRun(configuration *Configuration) {
 	logger.Info("🐱 Compiling " + configuration.TargetPath)
 	compile(configuration)
 
-	virtualMachine := VM.NewVirtualMachine(configuration.OutputPath)
+	virtualMachine := VM.NewVirtualMachine(configuration.OutputPath, configuration.ProgramArguments)
 	virtualMachine.Execute()
 }
 
@@ -169,7 +186,7 @@ func main() {
 		compile(configuration)
 
 	case A_Run:
-		virtualMachine := VM.NewVirtualMachine(configuration.TargetPath)
+		virtualMachine := VM.NewVirtualMachine(configuration.TargetPath, configuration.ProgramArguments)
 		virtualMachine.Execute()
 
 	case A_Analyze:
@@ -182,5 +199,21 @@ func main() {
 
 	case A_BuildAndRun:
 		buildAndRun(configuration)
+
+	case A_Repl:
+		NewRepl().Run()
+
+	case A_Lsp:
+		os.Exit(languageServer.NewLanguageServer(configuration.IncludePaths).Run(os.Stdin, os.Stdout))
+
+	case A_Fmt:
+		os.Exit(formatFiles(configuration))
+
+	case A_Lint:
+		logger.Info("🐱 Linting " + configuration.TargetPath)
+		os.Exit(lint(configuration))
+
+	case A_Disasm:
+		disassemble(configuration)
 	}
 }
@@ -0,0 +1,246 @@
+package moduleLoader
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	data "github.com/DanielNos/neco/dataStructures"
+	"github.com/DanielNos/neco/errors"
+	"github.com/DanielNos/neco/lexer"
+	"github.com/DanielNos/neco/logger"
+	"github.com/DanielNos/neco/std"
+	"github.com/DanielNos/neco/syntaxAnalyzer"
+)
+
+type Module struct {
+	Name     string
+	Path     string
+	Embedded bool // Module is part of standard library embedded in compiler
+	Tokens   []*lexer.Token
+}
+
+type ModuleLoader struct {
+	resolver *Resolver
+
+	modules     map[string]*Module // Modules by their absolute path
+	moduleNames map[string]*Module // Modules by their name
+	loading     []*Module          // Chain of modules which are being loaded
+	sources     map[string]string  // Source codes, which are used instead of files, by absolute path
+
+	Modules []*Module // Loaded modules ordered so that every module is after modules it imports
+
+	LexicalErrorCount uint
+	SyntaxErrorCount  uint // Includes errors of unresolved imports and import cycles
+}
+
+func NewModuleLoader(includePaths []string) *ModuleLoader {
+	return &ModuleLoader{
+		resolver: NewResolver(includePaths),
+
+		modules:     map[string]*Module{},
+		moduleNames: map[string]*Module{},
+		loading:     []*Module{},
+		sources:     map[string]string{},
+
+		Modules: []*Module{},
+
+		LexicalErrorCount: 0,
+		SyntaxErrorCount:  0,
+	}
+}
+
+func (ml *ModuleLoader) newError(position *data.CodePos, message string) {
+	if ml.LexicalErrorCount+ml.SyntaxErrorCount == 0 {
+		fmt.Fprint(os.Stderr, "\n")
+	}
+
+	ml.SyntaxErrorCount++
+	logger.ErrorCodePos(position, message)
+}
+
+// Sets source code of file at path. It's used instead of file's content, which doesn't have to exist.
+func (ml *ModuleLoader) SetSource(path, source string) {
+	// Lexer requires last line to be terminated
+	if !strings.HasSuffix(source, "\n") {
+		source += "\n"
+	}
+
+	ml.sources[absolutePath(path)] = source
+}
+
+// Loads module at path and all modules imported by it. Returns the loaded module.
+func (ml *ModuleLoader) Load(path string) *Module {
+	if filePath, exists := findSourceFile(path); exists {
+		path = filePath
+	}
+
+	ml.resolver.projectRoot = filepath.Dir(path)
+
+	return ml.load(&Module{moduleName(path), path, false, nil}, true)
+}
+
+// Collects tokens of all loaded modules.
+func (ml *ModuleLoader) Tokens() []*lexer.Token {
+	tokens := []*lexer.Token{}
+
+	for _, module := range ml.Modules {
+		tokens = append(tokens, module.Tokens...)
+	}
+
+	return tokens
+}
+
+// Collects token lists of all loaded modules.
+func (ml *ModuleLoader) TokenLists() [][]*lexer.Token {
+	tokenLists := make([][]*lexer.Token, len(ml.Modules))
+
+	for i, module := range ml.Modules {
+		tokenLists[i] = module.Tokens
+	}
+
+	return tokenLists
+}
+
+func (ml *ModuleLoader) load(module *Module, isEntry bool) *Module {
+	ml.modules[module.key()] = module
+	ml.moduleNames[module.Name] = module
+
+	ml.loading = append(ml.loading, module)
+	defer func() { ml.loading = ml.loading[:len(ml.loading)-1] }()
+
+	// Tokenize
+	lexer := ml.newLexer(module)
+	tokens := lexer.Lex()
+	ml.LexicalErrorCount += lexer.ErrorCount
+
+	// Load imported modules
+	tokens, importTokens := syntaxAnalyzer.CollectImports(tokens)
+	importedModules := []*Module{}
+
+	for _, importToken := range importTokens {
+		if imported := ml.loadImport(importToken, filepath.Dir(module.Path)); imported != nil {
+			importedModules = append(importedModules, imported)
+		}
+	}
+
+	syntaxAnalyzer := syntaxAnalyzer.NewSyntaxAnalyzer(tokens, ml.LexicalErrorCount+ml.SyntaxErrorCount)
+
+	for _, imported := range importedModules {
+		syntaxAnalyzer.ImportModule(imported.Name, imported.Tokens)
+	}
+
+	// Types of imported modules are prefixed with module name
+	if !isEntry {
+		syntaxAnalyzer.QualifyTypes(module.Name)
+	}
+
+	// Analyze syntax
+	module.Tokens = syntaxAnalyzer.Analyze()
+	ml.SyntaxErrorCount += syntaxAnalyzer.ErrorCount
+
+	ml.Modules = append(ml.Modules, module)
+
+	return module
+}
+
+func (ml *ModuleLoader) newLexer(module *Module) lexer.Lexer {
+	if !module.Embedded {
+		if source, exists := ml.sources[module.key()]; exists {
+			return lexer.NewLexerFromFile(module.Path, io.NopCloser(strings.NewReader(source)))
+		}
+
+		return lexer.NewLexer(module.Path)
+	}
+
+	file, err := std.Open(module.Path)
+
+	if err != nil {
+		logger.Fatal(errors.LEXICAL, "Failed to open standard library module "+module.Path+". "+err.Error())
+	}
+
+	return lexer.NewLexerFromFile(module.Path, file)
+}
+
+func (ml *ModuleLoader) loadImport(importToken *lexer.Token, importingDirectory string) *Module {
+	path, embedded := importToken.Value, true
+
+	// Find source file
+	if !std.IsModule(path) {
+		var searchedDirectories []string
+		path, searchedDirectories = ml.resolver.Resolve(importToken.Value, importingDirectory)
+		embedded = false
+
+		if path == "" {
+			if strings.HasPrefix(importToken.Value, std.PREFIX) {
+				ml.newError(importToken.Position, "Module "+importToken.Value+" isn't part of standard library. Standard library modules: "+strings.Join(std.Modules(), ", ")+".")
+			} else {
+				ml.newError(importToken.Position, "Module "+importToken.Value+" wasn't found. Searched directories: "+strings.Join(searchedDirectories, ", ")+".")
+			}
+			return nil
+		}
+	}
+
+	imported := &Module{moduleName(path), path, embedded, nil}
+	module, loaded := ml.modules[imported.key()]
+
+	// Module is being loaded, so it imports itself through the imported modules
+	if loaded && module.Tokens == nil {
+		chain := []string{}
+		for _, loading := range ml.loading[ml.indexOfLoading(module):] {
+			chain = append(chain, loading.Name)
+		}
+
+		ml.newError(importToken.Position, "Import cycle "+strings.Join(append(chain, module.Name), " -> ")+". Modules can't import each other.")
+		return nil
+	}
+
+	// Modules are loaded only once
+	if loaded {
+		return module
+	}
+
+	// Modules are referenced by their name, so it has to be unique
+	if other, exists := ml.moduleNames[imported.Name]; exists {
+		ml.newError(importToken.Position, "Module "+importToken.Value+" has the same name as module "+other.Path+".")
+		return nil
+	}
+
+	return ml.load(imported, false)
+}
+
+// Creates key identifying module. Embedded modules are identified by their import path, others by absolute path of their file.
+func (module *Module) key() string {
+	if module.Embedded {
+		return module.Path
+	}
+
+	return absolutePath(module.Path)
+}
+
+func (ml *ModuleLoader) indexOfLoading(module *Module) int {
+	for i, loading := range ml.loading {
+		if loading == module {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// Creates module name from it's path. Module name is the file name without extension.
+func moduleName(path string) string {
+	return strings.Split(filepath.Base(path), ".")[0]
+}
+
+func absolutePath(path string) string {
+	absolute, err := filepath.Abs(path)
+
+	if err != nil {
+		return path
+	}
+
+	return absolute
+}
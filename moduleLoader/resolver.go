@@ -0,0 +1,66 @@
+package moduleLoader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const SOURCE_EXTENSION = ".neco"
+const PATH_VARIABLE = "NECO_PATH"
+
+type Resolver struct {
+	projectRoot string   // Directory of the entry module
+	searchPaths []string // Directories searched after directory of importing module and project root
+}
+
+// Creates resolver, which searches include paths and then project roots from NECO_PATH environment variable.
+func NewResolver(includePaths []string) *Resolver {
+	searchPaths := append([]string{}, includePaths...)
+
+	for _, root := range filepath.SplitList(os.Getenv(PATH_VARIABLE)) {
+		if root != "" {
+			searchPaths = append(searchPaths, root)
+		}
+	}
+
+	return &Resolver{"", searchPaths}
+}
+
+// Finds source file of imported module. Directory of importing module is searched first, then project root and search paths in order.
+// Returns path of the file and list of searched directories.
+func (r *Resolver) Resolve(importPath, importingDirectory string) (string, []string) {
+	directories := []string{}
+	searched := map[string]bool{}
+
+	for _, directory := range append([]string{importingDirectory, r.projectRoot}, r.searchPaths...) {
+		if !searched[absolutePath(directory)] {
+			searched[absolutePath(directory)] = true
+			directories = append(directories, directory)
+		}
+	}
+
+	for _, directory := range directories {
+		if filePath, exists := findSourceFile(filepath.Join(directory, filepath.FromSlash(importPath))); exists {
+			return filePath, directories
+		}
+	}
+
+	return "", directories
+}
+
+// Checks if source file exists at path, with or without source file extension.
+func findSourceFile(path string) (string, bool) {
+	if !strings.HasSuffix(path, SOURCE_EXTENSION) {
+		if isFile(path + SOURCE_EXTENSION) {
+			return path + SOURCE_EXTENSION, true
+		}
+	}
+
+	return path, isFile(path)
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -92,7 +92,7 @@ func visualize(node *Node, indent string, isLast bool) {
 	case NT_Variable:
 		fmt.Println(node.Value.(*VariableNode).Identifier)
 
-	case NT_FunctionDeclaration:
+	case NT_FunctionDeclaration, NT_Lambda:
 		functionDeclareNode := node.Value.(*FunctionDeclareNode)
 
 		fmt.Printf("fun %s(", functionDeclareNode.Identifier)
@@ -166,7 +166,10 @@ func visualize(node *Node, indent string, isLast bool) {
 		fmt.Printf("%s├─ Init\n", indent)
 
 		visualizeList(forNode.InitStatement, indent, false)
-		visualize(forNode.Body, indent, true)
+		visualize(forNode.Body, indent, false)
+
+		fmt.Printf("%s└─ Step\n", indent)
+		visualizeList(forNode.StepStatement, indent, true)
 
 	case NT_ForEachLoop:
 		fmt.Println("forEach")
@@ -179,6 +182,9 @@ func visualize(node *Node, indent string, isLast bool) {
 	case NT_Break:
 		fmt.Println("break")
 
+	case NT_Continue:
+		fmt.Println("continue")
+
 	case NT_List, NT_Set:
 		listNode := node.Value.(*ListNode)
 		fmt.Printf("%s", listNode.DataType)
@@ -192,6 +198,22 @@ func visualize(node *Node, indent string, isLast bool) {
 
 		visualizeList(listNode.Nodes, indent, true)
 
+	case NT_Map:
+		mapNode := node.Value.(*MapNode)
+		fmt.Printf("%s", mapNode.DataType)
+
+		if len(mapNode.Keys) == 0 {
+			fmt.Println(" (empty)")
+			return
+		} else {
+			fmt.Println()
+		}
+
+		for i := range mapNode.Keys {
+			visualize(mapNode.Keys[i], indent, false)
+			visualize(mapNode.Values[i], indent, i == len(mapNode.Keys)-1)
+		}
+
 	case NT_ListValue:
 		fmt.Println("ListValue")
 		listValue := node.Value.(*TypedBinaryNode)
@@ -199,6 +221,40 @@ func visualize(node *Node, indent string, isLast bool) {
 		visualize(listValue.Left, indent, false)
 		visualize(listValue.Right, indent, true)
 
+	case NT_Slice:
+		fmt.Println("Slice")
+		sliceNode := node.Value.(*SliceNode)
+
+		visualize(sliceNode.Expression, indent, false)
+
+		if sliceNode.Start == nil {
+			fmt.Printf("%s├─ (start)\n", indent)
+		} else {
+			visualize(sliceNode.Start, indent, false)
+		}
+
+		if sliceNode.End == nil {
+			fmt.Printf("%s└─ (end)\n", indent)
+		} else {
+			visualize(sliceNode.End, indent, true)
+		}
+
+	case NT_Range:
+		rangeNode := node.Value.(*RangeNode)
+
+		if rangeNode.Inclusive {
+			fmt.Println("Range (inclusive)")
+		} else {
+			fmt.Println("Range")
+		}
+
+		visualize(rangeNode.Start, indent, false)
+		visualize(rangeNode.End, indent, rangeNode.Step == nil)
+
+		if rangeNode.Step != nil {
+			visualize(rangeNode.Step, indent, true)
+		}
+
 	case NT_ListAssign:
 		listAssign := node.Value.(*ListAssignNode)
 		fmt.Println("Assign")
@@ -210,6 +266,17 @@ func visualize(node *Node, indent string, isLast bool) {
 	case NT_Enum:
 		fmt.Printf("%d (%s)\n", node.Value.(*EnumNode).Value, node.Value.(*EnumNode).Identifier)
 
+	case NT_Variant:
+		variantNode := node.Value.(*VariantNode)
+
+		fmt.Printf("%s.%s (%d)\n", variantNode.Enum, variantNode.Identifier, variantNode.Tag)
+		visualizeList(variantNode.Payload, indent, true)
+
+	case NT_VariantPayload:
+		variantPayloadNode := node.Value.(*VariantPayloadNode)
+		fmt.Println("Payload " + fmt.Sprintf("%d", variantPayloadNode.PayloadIndex))
+		visualize(variantPayloadNode.Variant, indent, true)
+
 	case NT_Object:
 		objectNode := node.Value.(*ObjectNode)
 
@@ -246,6 +313,21 @@ func visualize(node *Node, indent string, isLast bool) {
 
 		visualize(caseNode.Statement, indent, true)
 
+	case NT_Try:
+		tryNode := node.Value.(*TryNode)
+
+		fmt.Println("Try")
+		visualize(tryNode.Body, indent, false)
+		fmt.Println(indent + "└─ catch " + tryNode.ErrorIdentifier)
+		visualize(tryNode.CatchBody, indent+"   ", true)
+
+	case NT_Throw:
+		fmt.Println("Throw")
+		visualize(node.Value.(*Node), indent, true)
+
+	case NT_FunctionReference:
+		fmt.Printf("fun %s\n", node.Value.(*FunctionReferenceNode).Identifier)
+
 	case NT_Unwrap:
 		fmt.Println("Unwrap")
 		visualize(node.Value.(*Node), indent, true)
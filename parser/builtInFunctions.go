@@ -1,9 +1,29 @@
 package parser
 
-import data "github.com/DanielNos/neco/dataStructures"
+import (
+	"math"
+
+	data "github.com/DanielNos/neco/dataStructures"
+	"github.com/DanielNos/neco/lexer"
+)
 
 var NO_PARAMS = []Parameter{}
 
+// Built-in functions, which modify list passed as their first argument
+var MUTATING_BUILT_IN_FUNCTIONS = map[string]struct{}{
+	"insert":  {},
+	"pop":     {},
+	"reverse": {},
+	"sort":    {},
+	"clear":   {},
+}
+
+var BUILT_IN_CONSTANTS = map[string]float64{
+	"PI":  math.Pi,
+	"E":   math.E,
+	"INF": math.Inf(1),
+}
+
 func (p *Parser) insertBuiltInFunctions() {
 	// Prints
 	p.insertFunction("print", &FunctionSymbol{-1,
@@ -76,10 +96,160 @@ func (p *Parser) insertBuiltInFunctions() {
 		&data.DataType{data.DT_Float, nil}, true},
 	)
 
+	// Powers, roots and logarithms
+	p.insertFunction("sqrt", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "float", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+	p.insertFunction("pow", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Int, nil}, "base", nil}, {&data.DataType{data.DT_Int, nil}, "exponent", nil}},
+		&data.DataType{data.DT_Int, nil}, true},
+	)
+	p.insertFunction("pow", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "base", nil}, {&data.DataType{data.DT_Float, nil}, "exponent", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+	p.insertFunction("exp", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "float", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+	p.insertFunction("log", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "float", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+	p.insertFunction("log2", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "float", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+	p.insertFunction("log10", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "float", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+
+	// Trigonometric functions
+	p.insertFunction("sin", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "angle", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+	p.insertFunction("cos", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "angle", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+	p.insertFunction("tan", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "angle", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+	p.insertFunction("asin", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "float", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+	p.insertFunction("acos", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "float", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+	p.insertFunction("atan", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "float", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+	p.insertFunction("atan2", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "y", nil}, {&data.DataType{data.DT_Float, nil}, "x", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+
+	// Minimum, maximum and clamping
+	p.insertFunction("min", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Int, nil}, "a", nil}, {&data.DataType{data.DT_Int, nil}, "b", nil}},
+		&data.DataType{data.DT_Int, nil}, true},
+	)
+	p.insertFunction("min", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "a", nil}, {&data.DataType{data.DT_Float, nil}, "b", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+	p.insertFunction("max", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Int, nil}, "a", nil}, {&data.DataType{data.DT_Int, nil}, "b", nil}},
+		&data.DataType{data.DT_Int, nil}, true},
+	)
+	p.insertFunction("max", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "a", nil}, {&data.DataType{data.DT_Float, nil}, "b", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+	p.insertFunction("clamp", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Int, nil}, "value", nil}, {&data.DataType{data.DT_Int, nil}, "minimum", nil}, {&data.DataType{data.DT_Int, nil}, "maximum", nil}},
+		&data.DataType{data.DT_Int, nil}, true},
+	)
+	p.insertFunction("clamp", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "value", nil}, {&data.DataType{data.DT_Float, nil}, "minimum", nil}, {&data.DataType{data.DT_Float, nil}, "maximum", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+	p.insertFunction("sign", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Int, nil}, "integer", nil}},
+		&data.DataType{data.DT_Int, nil}, true},
+	)
+	p.insertFunction("sign", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "float", nil}},
+		&data.DataType{data.DT_Float, nil}, true},
+	)
+
+	// Special float values
+	p.insertFunction("isNaN", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "float", nil}},
+		&data.DataType{data.DT_Bool, nil}, true},
+	)
+	p.insertFunction("isInf", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Float, nil}, "float", nil}},
+		&data.DataType{data.DT_Bool, nil}, true},
+	)
+
 	// Reading text from terminal
 	p.insertFunction("readLine", &FunctionSymbol{-1, NO_PARAMS, &data.DataType{data.DT_String, nil}, true})
 	p.insertFunction("readChar", &FunctionSymbol{-1, NO_PARAMS, &data.DataType{data.DT_String, nil}, true})
 
+	// Reading files and directories, none is returned on failure
+	p.insertFunction("readFile", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "path", nil}},
+		&data.DataType{data.DT_Option, &data.DataType{data.DT_String, nil}}, true},
+	)
+	p.insertFunction("readLines", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "path", nil}},
+		&data.DataType{data.DT_Option, &data.DataType{data.DT_List, &data.DataType{data.DT_String, nil}}}, true},
+	)
+	p.insertFunction("listDir", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "path", nil}},
+		&data.DataType{data.DT_Option, &data.DataType{data.DT_List, &data.DataType{data.DT_String, nil}}}, true},
+	)
+	p.insertFunction("fileExists", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "path", nil}},
+		&data.DataType{data.DT_Bool, nil}, true},
+	)
+
+	// Modifying files and directories, error is returned on failure
+	p.insertFunction("writeFile", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "path", nil}, {&data.DataType{data.DT_String, nil}, "text", nil}},
+		&data.DataType{data.DT_Option, &data.DataType{data.DT_Object, "Error"}}, true},
+	)
+	p.insertFunction("appendFile", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "path", nil}, {&data.DataType{data.DT_String, nil}, "text", nil}},
+		&data.DataType{data.DT_Option, &data.DataType{data.DT_Object, "Error"}}, true},
+	)
+	p.insertFunction("makeDir", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "path", nil}},
+		&data.DataType{data.DT_Option, &data.DataType{data.DT_Object, "Error"}}, true},
+	)
+	p.insertFunction("removeFile", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "path", nil}},
+		&data.DataType{data.DT_Option, &data.DataType{data.DT_Object, "Error"}}, true},
+	)
+
+	// Environment variables
+	p.insertFunction("getEnv", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "name", nil}},
+		&data.DataType{data.DT_Option, &data.DataType{data.DT_String, nil}}, true},
+	)
+	p.insertFunction("setEnv", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "name", nil}, {&data.DataType{data.DT_String, nil}, "value", nil}},
+		&data.DataType{data.DT_Option, &data.DataType{data.DT_Object, "Error"}}, true},
+	)
+
 	// Length of strings
 	p.insertFunction("length", &FunctionSymbol{-1,
 		[]Parameter{{&data.DataType{data.DT_String, nil}, "string", nil}},
@@ -92,6 +262,64 @@ func (p *Parser) insertBuiltInFunctions() {
 		&data.DataType{data.DT_Int, nil}, true},
 	)
 
+	// Modifying lists
+	p.insertFunction("insert", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_List, &data.DataType{data.DT_Generic, "T"}}, "list", nil}, {&data.DataType{data.DT_Int, nil}, "index", nil}, {&data.DataType{data.DT_Generic, "T"}, "value", nil}},
+		nil, true},
+	)
+	p.insertFunction("pop", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_List, &data.DataType{data.DT_Generic, "T"}}, "list", nil}},
+		&data.DataType{data.DT_Generic, "T"}, true},
+	)
+	p.insertFunction("reverse", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_List, &data.DataType{data.DT_Generic, "T"}}, "list", nil}},
+		nil, true},
+	)
+	p.insertFunction("sort", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_List, &data.DataType{data.DT_Generic, "T"}}, "list", nil}},
+		nil, true},
+	)
+	p.insertFunction("clear", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_List, &data.DataType{data.DT_Generic, "T"}}, "list", nil}},
+		nil, true},
+	)
+
+	// Searching and copying lists
+	p.insertFunction("indexOf", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_List, &data.DataType{data.DT_Generic, "T"}}, "list", nil}, {&data.DataType{data.DT_Generic, "T"}, "value", nil}},
+		&data.DataType{data.DT_Int, nil}, true},
+	)
+	p.insertFunction("sorted", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_List, &data.DataType{data.DT_Generic, "T"}}, "list", nil}},
+		&data.DataType{data.DT_List, &data.DataType{data.DT_Generic, "T"}}, true},
+	)
+	p.insertFunction("copy", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_List, &data.DataType{data.DT_Generic, "T"}}, "list", nil}},
+		&data.DataType{data.DT_List, &data.DataType{data.DT_Generic, "T"}}, true},
+	)
+
+	// List of numbers from 0 to n
+	p.insertFunction("range", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Int, nil}, "n", nil}},
+		&data.DataType{data.DT_List, &data.DataType{data.DT_Int, nil}}, true},
+	)
+
+	// Size of maps
+	p.insertFunction("size", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Map, &data.MapType{&data.DataType{data.DT_Any, nil}, &data.DataType{data.DT_Any, nil}}}, "map", nil}},
+		&data.DataType{data.DT_Int, nil}, true},
+	)
+
+	// Keys and values of maps
+	p.insertFunction("keys", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Map, &data.MapType{&data.DataType{data.DT_Any, nil}, &data.DataType{data.DT_Any, nil}}}, "map", nil}},
+		&data.DataType{data.DT_List, &data.DataType{data.DT_Any, nil}}, true},
+	)
+	p.insertFunction("values", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Map, &data.MapType{&data.DataType{data.DT_Any, nil}, &data.DataType{data.DT_Any, nil}}}, "map", nil}},
+		&data.DataType{data.DT_List, &data.DataType{data.DT_Any, nil}}, true},
+	)
+
 	// String to upper/lower
 	p.insertFunction("toLower", &FunctionSymbol{-1,
 		[]Parameter{{&data.DataType{data.DT_String, nil}, "string", nil}},
@@ -102,6 +330,70 @@ func (p *Parser) insertBuiltInFunctions() {
 		&data.DataType{data.DT_String, nil}, true},
 	)
 
+	// String searching and manipulation
+	p.insertFunction("split", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "string", nil}, {&data.DataType{data.DT_String, nil}, "separator", nil}},
+		&data.DataType{data.DT_List, &data.DataType{data.DT_String, nil}}, true},
+	)
+	p.insertFunction("join", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_List, &data.DataType{data.DT_String, nil}}, "strings", nil}, {&data.DataType{data.DT_String, nil}, "separator", nil}},
+		&data.DataType{data.DT_String, nil}, true},
+	)
+	p.insertFunction("replace", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "string", nil}, {&data.DataType{data.DT_String, nil}, "old", nil}, {&data.DataType{data.DT_String, nil}, "new", nil}},
+		&data.DataType{data.DT_String, nil}, true},
+	)
+	p.insertFunction("contains", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "string", nil}, {&data.DataType{data.DT_String, nil}, "part", nil}},
+		&data.DataType{data.DT_Bool, nil}, true},
+	)
+	p.insertFunction("startsWith", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "string", nil}, {&data.DataType{data.DT_String, nil}, "prefix", nil}},
+		&data.DataType{data.DT_Bool, nil}, true},
+	)
+	p.insertFunction("endsWith", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "string", nil}, {&data.DataType{data.DT_String, nil}, "suffix", nil}},
+		&data.DataType{data.DT_Bool, nil}, true},
+	)
+	p.insertFunction("indexOf", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "string", nil}, {&data.DataType{data.DT_String, nil}, "part", nil}},
+		&data.DataType{data.DT_Int, nil}, true},
+	)
+
+	// Removing white space
+	p.insertFunction("trim", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "string", nil}},
+		&data.DataType{data.DT_String, nil}, true},
+	)
+	p.insertFunction("trimLeft", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "string", nil}},
+		&data.DataType{data.DT_String, nil}, true},
+	)
+	p.insertFunction("trimRight", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "string", nil}},
+		&data.DataType{data.DT_String, nil}, true},
+	)
+
+	// Repeating and padding strings
+	p.insertFunction("repeat", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "string", nil}, {&data.DataType{data.DT_Int, nil}, "count", nil}},
+		&data.DataType{data.DT_String, nil}, true},
+	)
+	p.insertFunction("padLeft", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "string", nil}, {&data.DataType{data.DT_Int, nil}, "width", nil}, {&data.DataType{data.DT_String, nil}, "padding", nil}},
+		&data.DataType{data.DT_String, nil}, true},
+	)
+	p.insertFunction("padRight", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "string", nil}, {&data.DataType{data.DT_Int, nil}, "width", nil}, {&data.DataType{data.DT_String, nil}, "padding", nil}},
+		&data.DataType{data.DT_String, nil}, true},
+	)
+
+	// Formatting
+	p.insertFunction("format", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "format", nil}, {&data.DataType{data.DT_List, &data.DataType{data.DT_Any, nil}}, "values", nil}},
+		&data.DataType{data.DT_String, nil}, true},
+	)
+
 	// Random ints/floats
 	p.insertFunction("randomInt", &FunctionSymbol{-1, NO_PARAMS, &data.DataType{data.DT_Int, nil}, true})
 	p.insertFunction("randomFlt", &FunctionSymbol{-1, NO_PARAMS, &data.DataType{data.DT_Float, nil}, true})
@@ -110,6 +402,33 @@ func (p *Parser) insertBuiltInFunctions() {
 		&data.DataType{data.DT_Int, false}, true},
 	)
 
+	// JSON, schema of the value is inserted by parser as the last argument
+	p.insertFunction("toJson", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Any, nil}, "value", nil}},
+		&data.DataType{data.DT_String, nil}, true},
+	)
+	p.insertFunction("fromJson", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "text", nil}},
+		&data.DataType{data.DT_Option, &data.DataType{data.DT_Unknown, nil}}, true},
+	)
+
+	// Time
+	p.insertFunction("now", &FunctionSymbol{-1, NO_PARAMS, &data.DataType{data.DT_Int, nil}, true})
+	p.insertFunction("nowFlt", &FunctionSymbol{-1, NO_PARAMS, &data.DataType{data.DT_Float, nil}, true})
+	p.insertFunction("monotonic", &FunctionSymbol{-1, NO_PARAMS, &data.DataType{data.DT_Float, nil}, true})
+	p.insertFunction("sleep", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Int, nil}, "milliseconds", nil}},
+		nil, true},
+	)
+	p.insertFunction("formatTime", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_Int, nil}, "timestamp", nil}, {&data.DataType{data.DT_String, nil}, "layout", nil}},
+		&data.DataType{data.DT_String, nil}, true},
+	)
+	p.insertFunction("parseTime", &FunctionSymbol{-1,
+		[]Parameter{{&data.DataType{data.DT_String, nil}, "text", nil}, {&data.DataType{data.DT_String, nil}, "layout", nil}},
+		&data.DataType{data.DT_Option, &data.DataType{data.DT_Int, nil}}, true},
+	)
+
 	// Exit
 	p.insertFunction("exit", &FunctionSymbol{-1,
 		[]Parameter{{&data.DataType{data.DT_Int, nil}, "exitCode", nil}},
@@ -135,3 +454,45 @@ func (p *Parser) insertBuiltInFunctions() {
 		nil, true},
 	)
 }
+
+// Checks arguments of built-in functions, which can't be checked by matching them to parameter types.
+func (p *Parser) checkBuiltInArguments(identifier *lexer.Token, arguments []*Node) {
+	// Modified list is stored back to it's variable
+	if _, isMutating := MUTATING_BUILT_IN_FUNCTIONS[identifier.Value]; isMutating {
+		if arguments[0].NodeType != NT_Variable {
+			p.newError(GetExpressionPosition(arguments[0]), "List modified by "+identifier.Value+" has to be stored in a variable.")
+		} else if symbol := p.findSymbol(arguments[0].Value.(*VariableNode).Identifier); symbol != nil && symbol.symbolType == ST_Variable && symbol.value.(*VariableSymbol).isConstant {
+			p.newError(GetExpressionPosition(arguments[0]), "Variable "+arguments[0].Value.(*VariableNode).Identifier+" is constant.")
+		}
+	}
+
+	// Only lists of ordered elements can be sorted
+	if identifier.Value == "sort" || identifier.Value == "sorted" {
+		listType := GetExpressionType(arguments[0])
+		elementType := listType.SubType.(*data.DataType).Type
+
+		if elementType != data.DT_Int && elementType != data.DT_Float && elementType != data.DT_String {
+			p.newError(GetExpressionPosition(arguments[0]), "Can't sort "+listType.String()+". Only lists of int, flt and str can be sorted.")
+		}
+	}
+}
+
+// Checks if function bucket contains a built-in function.
+func (p *Parser) isBuiltInFunction(functionBucketSymbol *Symbol) bool {
+	for _, function := range functionBucketSymbol.value.(symbolTable) {
+		if function.value.(*FunctionSymbol).number == -1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Parser) insertBuiltInStructs() {
+	// Error caught by catch blocks
+	p.insertSymbol("Error", &Symbol{ST_Struct, map[string]PropertySymbol{
+		"message":   {0, &data.DataType{data.DT_String, nil}},
+		"traceback": {1, &data.DataType{data.DT_String, nil}},
+	}})
+	p.StringConstants["Error"] = -1
+}
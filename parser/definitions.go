@@ -1,25 +1,21 @@
 package parser
 
 import (
+	"slices"
+	"strings"
+
 	data "github.com/DanielNos/neco/dataStructures"
 	"github.com/DanielNos/neco/lexer"
-	"github.com/DanielNos/neco/logger"
 )
 
 func (p *Parser) collectGlobals() {
-	// Collect enums
+	// Collect imported modules, struct and class names
 	for p.tokenIndex < len(p.tokens)-1 {
-		if p.peek().TokenType == lexer.TT_KW_enum {
-			p.parseEnum()
-		} else {
+		if p.peek().TokenType == lexer.TT_KW_import {
 			p.consume()
-		}
-	}
-	p.tokenIndex = 1
-
-	// Collect struct names
-	for p.tokenIndex < len(p.tokens)-1 {
-		if p.peek().TokenType == lexer.TT_KW_struct {
+			p.insertImport(p.consume())
+		} else if p.peek().TokenType == lexer.TT_KW_struct || p.peek().TokenType == lexer.TT_KW_class {
+			public := p.isPublic(p.tokenIndex)
 			p.consume()
 
 			symbol := p.getGlobalSymbol(p.peek().Value)
@@ -28,16 +24,40 @@ func (p *Parser) collectGlobals() {
 				p.newError(p.peek().Position, "Symbol is already declared as a "+symbol.symbolType.String()+".")
 			}
 
-			p.insertSymbol(p.consume().Value, &Symbol{ST_Struct, nil})
+			identifierToken := p.consume()
+			identifier := identifierToken.Value
+			symbol = &Symbol{ST_Struct, nil}
+			p.insertSymbol(identifier, symbol)
+			p.indexDeclaration(symbol, identifier, identifierToken.Position)
+
+			if public {
+				p.markPublic(symbol)
+			}
+
+			// Collect type parameters of generic struct
+			typeParameters := p.parseTypeParameters()
+			if len(typeParameters) != 0 {
+				p.structTypeParameters[identifier] = typeParameters
+			}
+		} else {
+			p.consume()
+		}
+	}
+	p.tokenIndex = 1
+
+	// Collect enums (after struct names, so they can be used in variant payloads)
+	for p.tokenIndex < len(p.tokens)-1 {
+		if p.peek().TokenType == lexer.TT_KW_enum {
+			p.parseEnum()
 		} else {
 			p.consume()
 		}
 	}
 	p.tokenIndex = 1
 
-	// Collect structs
+	// Collect structs and classes
 	for p.tokenIndex < len(p.tokens)-1 {
-		if p.peek().TokenType == lexer.TT_KW_struct {
+		if p.peek().TokenType == lexer.TT_KW_struct || p.peek().TokenType == lexer.TT_KW_class {
 			p.parseStruct()
 		} else {
 			p.consume()
@@ -45,11 +65,14 @@ func (p *Parser) collectGlobals() {
 	}
 	p.tokenIndex = 1
 
-	// Collect function headers
+	// Collect function and method headers
 	for p.tokenIndex < len(p.tokens)-1 {
-		if p.peek().TokenType == lexer.TT_KW_fun {
+		if p.peek().TokenType == lexer.TT_KW_class {
+			p.consume()
+			p.parseMethodHeaders(p.consume().Value)
+		} else if p.peek().TokenType == lexer.TT_KW_fun && p.peekNext().TokenType == lexer.TT_Identifier {
 			p.consume()
-			p.parseFunctionHeader()
+			p.parseFunctionHeader(nil)
 		} else {
 			p.consume()
 		}
@@ -67,7 +90,15 @@ func (p *Parser) collectGlobals() {
 			scopeDepth--
 			// Collect globals only in root scope
 		} else if scopeDepth == 0 {
-			if p.peek().TokenType.IsVariableType() {
+			// Skip function headers, so their return types aren't collected as globals
+			if p.peek().TokenType == lexer.TT_KW_fun && p.peekNext().TokenType == lexer.TT_Identifier {
+				for p.peek().TokenType != lexer.TT_DL_BraceOpen && p.tokenIndex < len(p.tokens)-1 {
+					p.consume()
+				}
+				continue
+			}
+
+			if p.peek().TokenType.IsVariableType() || p.isFunctionType() {
 				p.appendScope(p.parseVariableDeclaration(false))
 				continue
 			} else if p.peek().TokenType == lexer.TT_KW_const {
@@ -81,6 +112,54 @@ func (p *Parser) collectGlobals() {
 	p.tokenIndex = 1
 }
 
+func (p *Parser) insertImport(pathToken *lexer.Token) {
+	// Modules are referenced by the last segment of their path
+	pathParts := strings.Split(pathToken.Value, "/")
+	moduleName := pathParts[len(pathParts)-1]
+
+	module, exists := p.modules[moduleName]
+
+	if !exists {
+		p.newError(pathToken.Position, "Module "+pathToken.Value+" can't be imported.")
+		return
+	}
+
+	// Module identifier can't collide with other global symbols
+	if symbol := p.getGlobalSymbol(moduleName); symbol != nil {
+		if symbol.symbolType != ST_Module {
+			p.newError(pathToken.Position, "Symbol "+moduleName+" is already declared as a "+symbol.symbolType.String()+".")
+		}
+		return
+	}
+
+	symbol := &Symbol{ST_Module, module}
+	p.insertSymbol(moduleName, symbol)
+	p.indexDeclaration(symbol, moduleName, pathToken.Position)
+}
+
+func (p *Parser) parseClass() {
+	p.consume() // class
+	className := p.consume().Value
+
+	// Type parameters of class are visible in it's methods
+	p.stack_symbolTableStack.Push(symbolTable{})
+	p.insertTypeParameters(p.parseTypeParameters())
+
+	p.consume() // {
+
+	// Parse methods and skip over properties, because they were registered in collectGlobals()
+	for p.peek().TokenType != lexer.TT_DL_BraceClose {
+		if p.peek().TokenType == lexer.TT_KW_fun && p.peekNext().TokenType == lexer.TT_Identifier {
+			p.appendScope(p.parseFunctionDeclaration(className))
+		} else {
+			p.consume()
+		}
+	}
+
+	p.consume() // }
+	p.stack_symbolTableStack.Pop()
+}
+
 func (p *Parser) parseStruct() {
 	p.consume()
 
@@ -88,6 +167,10 @@ func (p *Parser) parseStruct() {
 	identifier := p.consume()
 	symbol := p.getGlobalSymbol(identifier.Value)
 
+	// Type parameters are visible only inside of the struct
+	p.stack_symbolTableStack.Push(symbolTable{})
+	p.insertTypeParameters(p.parseTypeParameters())
+
 	p.consume() // {
 	p.consumeEOCs()
 
@@ -96,6 +179,13 @@ func (p *Parser) parseStruct() {
 	propertyIndex := 0
 
 	for p.peek().TokenType != lexer.TT_DL_BraceClose {
+		// Skip methods, their headers are collected with function headers
+		if p.peek().TokenType == lexer.TT_KW_fun && p.peekNext().TokenType == lexer.TT_Identifier {
+			p.skipFunction()
+			p.consumeEOCs()
+			continue
+		}
+
 		// Collect property
 		dataType := p.parseType()
 		properties[p.consume().Value] = PropertySymbol{propertyIndex, dataType}
@@ -109,25 +199,28 @@ func (p *Parser) parseStruct() {
 			propertyIndex++
 		}
 
-		p.consume()
+		p.consumeEOCs()
 	}
 
 	p.consume() // }
+	p.stack_symbolTableStack.Pop()
 
 	if len(properties) == 0 {
-		if p.ErrorCount+p.totalErrorCount == 0 {
+		if p.ErrorCount+p.totalErrorCount == 0 && p.Warnings == nil {
 			println()
 		}
-		logger.WarningCodePos(identifier.Position, "Struct "+identifier.Value+" has no fields.")
+		p.newWarning(W_EmptyStruct, identifier.Position, "Struct "+identifier.Value+" has no fields.")
 	}
 
 	symbol.value = properties
 }
 
 func (p *Parser) parseEnum() {
+	public := p.isPublic(p.tokenIndex)
 	p.consume()
 	// Collect identifier
-	identifier := p.consume().Value
+	identifierToken := p.consume()
+	identifier := identifierToken.Value
 
 	if p.peek().TokenType == lexer.TT_EndOfCommand {
 		p.consume()
@@ -147,14 +240,21 @@ func (p *Parser) parseEnum() {
 
 	// Collect enum constants
 	constants := map[string]int64{}
+	variants := map[string]*VariantSymbol{}
 	constantIndex := int64(0)
+	hasPayload := false
 
 	for p.peek().TokenType != lexer.TT_DL_BraceClose {
 		// Collect identifier
 		constantIdentifier := p.consume()
-
-		// Change index
-		if p.peek().TokenType == lexer.TT_KW_Assign {
+		payload := []Parameter{}
+
+		// Collect payload
+		if p.peek().TokenType == lexer.TT_DL_ParenthesisOpen {
+			payload = p.parseVariantPayload()
+			hasPayload = true
+			// Change index
+		} else if p.peek().TokenType == lexer.TT_KW_Assign {
 			p.consume()
 
 			expression := p.parseExpressionRoot()
@@ -174,39 +274,145 @@ func (p *Parser) parseEnum() {
 
 		// Store constant
 		constants[constantIdentifier.Value] = int64(constantIndex)
+		variants[constantIdentifier.Value] = &VariantSymbol{constantIndex, payload}
 		constantIndex++
 
-		// Consume EOCs
+		// Consume commas and EOCs
+		if p.peek().TokenType == lexer.TT_DL_Comma {
+			p.consume()
+		}
 		p.consumeEOCs()
 	}
 
 	p.consume() // }
 
-	p.insertSymbol(identifier, &Symbol{ST_Enum, constants})
+	// Enums with payloads are stored as tagged unions
+	symbol = &Symbol{ST_Enum, constants}
+	if hasPayload {
+		symbol.value = variants
+	}
+	p.insertSymbol(identifier, symbol)
+	p.indexDeclaration(symbol, identifier, identifierToken.Position)
+
+	if public {
+		p.markPublic(symbol)
+	}
+}
+
+func (p *Parser) parseVariantPayload() []Parameter {
+	p.consume() // (
+	payload := []Parameter{}
+
+	for p.peek().TokenType != lexer.TT_DL_ParenthesisClose {
+		dataType := p.parseType()
+		identifierToken := p.consume()
+
+		// Check for duplicate payload identifiers
+		for _, parameter := range payload {
+			if parameter.Identifier == identifierToken.Value {
+				p.newError(identifierToken.Position, "Duplicate payload identifier "+identifierToken.Value+".")
+			}
+		}
+
+		payload = append(payload, Parameter{dataType, identifierToken.Value, nil})
+
+		if p.peek().TokenType == lexer.TT_DL_Comma {
+			p.consume()
+		}
+	}
+
+	p.consume() // )
+
+	return payload
 }
 
-func (p *Parser) parseFunctionHeader() {
+func (p *Parser) parseMethodHeaders(className string) {
+	receiver := p.structType(className)
+
+	// Type parameters of class are visible in it's methods
+	p.stack_symbolTableStack.Push(symbolTable{})
+	p.insertTypeParameters(p.parseTypeParameters())
+
+	p.consume() // {
+	depth := 1
+
+	for depth > 0 && p.tokenIndex < len(p.tokens)-1 {
+		// Collect method header
+		if depth == 1 && p.peek().TokenType == lexer.TT_KW_fun && p.peekNext().TokenType == lexer.TT_Identifier {
+			p.consume()
+			p.parseFunctionHeader(receiver)
+			continue
+		}
+
+		if p.peek().TokenType.IsOpeningDelimiter() {
+			depth++
+		} else if p.peek().TokenType.IsClosingDelimiter() {
+			depth--
+		}
+		p.consume()
+	}
+
+	p.stack_symbolTableStack.Pop()
+}
+
+func (p *Parser) parseFunctionHeader(receiver *data.DataType) {
+	// Methods are visible if their class is
+	public := receiver == nil && p.isPublic(p.tokenIndex-1) || receiver != nil && p.module.public[p.getGlobalSymbol(receiver.StructName())]
+
 	// Find bucket
 	identifierToken := p.consume()
-	symbol := p.findSymbol(identifierToken.Value)
+	identifier := identifierToken.Value
+
+	// Methods are stored as functions with name of their class
+	if receiver != nil {
+		identifier = receiver.StructName() + "." + identifier
+	}
+
+	symbol := p.findSymbol(identifier)
 
 	// Enter scope
 	p.enterScope()
+
+	// Collect type parameters
+	typeParametersPosition := p.peek().Position
+	typeParameters := p.parseTypeParameters()
+	p.insertTypeParameters(typeParameters)
+	typeParametersPosition = typeParametersPosition.Combine(p.peekPrevious().Position)
+
 	p.consume() // (
 
 	// Collect parameters
 	startPosition := p.peek().Position
 	parameters := p.parseParameters()
 
-	// Function entry() can't have parameters
-	if identifierToken.Value == "entry" && len(parameters) != 0 {
-		p.newError(startPosition.Combine(p.peekPrevious().Position), "Function entry() can't have any parameters.")
+	// Object is passed to methods as the first parameter
+	if receiver != nil {
+		parameters = append([]Parameter{{receiver, "self", nil}}, parameters...)
+	}
+
+	// Type parameters have to be used by parameters, so their arguments can be inferred
+	usedTypeParameters := []string{}
+	for _, parameter := range parameters {
+		parameter.DataType.CollectTypeParameters(&usedTypeParameters)
+	}
+
+	for _, typeParameter := range typeParameters {
+		if !slices.Contains(usedTypeParameters, typeParameter) {
+			p.newError(typeParametersPosition, "Type parameter "+typeParameter+" of function "+identifier+" isn't used by any parameter, so it's type argument can't be inferred.")
+		}
+	}
+
+	// Function entry() can only have a parameter for program arguments
+	if identifier == "entry" && len(parameters) != 0 {
+		if len(parameters) != 1 || !parameters[0].DataType.Equals(&data.DataType{data.DT_List, &data.DataType{data.DT_String, nil}}) {
+			p.newError(startPosition.Combine(p.peekPrevious().Position), "Function entry() can only have one parameter of type list<str>.")
+		}
 	}
 
 	// Check for redeclaration
 	if symbol != nil {
 		// Redeclaration of entry()
-		if identifierToken.Value == "entry" {
+		if identifier == "entry" {
 			p.newError(identifierToken.Position, "Function entry() can't be overloaded.")
 		}
 
@@ -214,7 +420,7 @@ func (p *Parser) parseFunctionHeader() {
 		if symbol.symbolType == ST_FunctionBucket {
 			id := createParametersIdentifier(parameters)
 			if symbol.value.(symbolTable)[id] != nil {
-				p.newError(identifierToken.Position, "Redeclaration of symbol "+identifierToken.Value+".")
+				p.newError(identifierToken.Position, "Redeclaration of symbol "+identifier+".")
 			}
 		}
 	}
@@ -227,12 +433,12 @@ func (p *Parser) parseFunctionHeader() {
 
 	if p.peek().TokenType == lexer.TT_KW_returns {
 		returnPosition = p.consume().Position
-		returnType.Type = TokenTypeToDataType[p.consume().TokenType]
+		returnType = p.parseType()
 		returnPosition.EndChar = p.peekPrevious().Position.EndChar
 
-		// Function entry() can't have a return type
-		if identifierToken.Value == "entry" {
-			p.newError(returnPosition, "Function entry() can't have a return type.")
+		// Value returned by entry() is used as exit code
+		if identifier == "entry" && returnType.Type != data.DT_Int {
+			p.newError(returnPosition, "Function entry() can only return int.")
 		}
 	}
 
@@ -241,6 +447,11 @@ func (p *Parser) parseFunctionHeader() {
 	p.stack_symbolTableStack.Pop()
 
 	// Insert function symbol
-	newSymbol := p.insertFunction(identifierToken.Value, &FunctionSymbol{len(p.functions), parameters, returnType, identifierToken.Value == "entry"})
+	newSymbol := p.insertFunction(identifier, &FunctionSymbol{len(p.functions), parameters, returnType, identifier == "entry" && p.module.isEntry})
 	p.functions = append(p.functions, newSymbol.value.(*FunctionSymbol))
+	p.indexDeclaration(newSymbol, identifier, identifierToken.Position)
+
+	if public {
+		p.markPublic(newSymbol)
+	}
 }
@@ -31,6 +31,23 @@ func (p *Parser) deriveType(expression *Node) *data.DataType {
 		return p.deriveOperatorType(expression)
 	}
 
+	// Range
+	if expression.NodeType == NT_Range {
+		rangeNode := expression.Value.(*RangeNode)
+
+		// Start, end and step have to be integers
+		for _, bound := range []*Node{rangeNode.Start, rangeNode.End, rangeNode.Step} {
+			if bound == nil {
+				continue
+			}
+
+			boundType := p.deriveType(bound)
+			if boundType.Type != data.DT_Int && boundType.Type != data.DT_Unknown {
+				p.newError(GetExpressionPosition(bound), "Range start, end and step have to be of type int, but expression is of type "+boundType.String()+".")
+			}
+		}
+	}
+
 	return GetExpressionType(expression)
 }
 
@@ -80,6 +97,12 @@ func (p *Parser) deriveOperatorType(expression *Node) *data.DataType {
 	leftType := p.deriveType(binaryNode.Left)
 	rightType := p.deriveType(binaryNode.Right)
 
+	// Ranges can be used only with operator in
+	if binaryNode.Left.NodeType == NT_Range || binaryNode.Right.NodeType == NT_Range && expression.NodeType != NT_In {
+		p.newError(expression.Position, "Operator "+expression.NodeType.String()+" can't be used on a range.")
+		return &data.DataType{data.DT_Unknown, nil}
+	}
+
 	// Error in one of types
 	if leftType.Type == data.DT_Unknown || rightType.Type == data.DT_Unknown {
 		return &data.DataType{data.DT_Unknown, nil}
@@ -88,8 +111,14 @@ func (p *Parser) deriveOperatorType(expression *Node) *data.DataType {
 	// In operator has to be used on set with correct type
 	if expression.NodeType == NT_In {
 		// Right type isn't a set
-		if rightType.Type != data.DT_Set && rightType.Type != data.DT_List {
-			p.newError(GetExpressionPosition(binaryNode.Right), "Right side of operator \"in\" has to be a set or a list.")
+		if rightType.Type != data.DT_Set && rightType.Type != data.DT_List && rightType.Type != data.DT_Map {
+			p.newError(GetExpressionPosition(binaryNode.Right), "Right side of operator \"in\" has to be a set, a list or a map.")
+			// Left type isn't map's key type
+		} else if rightType.Type == data.DT_Map {
+			if !rightType.SubType.(*data.MapType).Key.CanBeAssigned(leftType) {
+				p.newErrorNoMessage()
+				logger.Error2CodePos(GetExpressionPosition(binaryNode.Left), GetExpressionPosition(binaryNode.Right), "Left expression type ("+leftType.String()+") doesn't match the map key type ("+rightType.SubType.(*data.MapType).Key.String()+").")
+			}
 			// Left type isn't set's sub-type
 		} else if !rightType.SubType.(*data.DataType).CanBeAssigned(leftType) {
 			p.newErrorNoMessage()
@@ -107,6 +136,11 @@ func (p *Parser) deriveOperatorType(expression *Node) *data.DataType {
 			p.newError(GetExpressionPosition(binaryNode.Right), "Expression on the right side of ?! operator can't be possibly none.")
 		}
 
+		// Default is always used
+		if leftType.Type == data.DT_None && p.Warnings != nil {
+			p.newWarning(W_RedundantDefault, GetExpressionPosition(binaryNode.Left), "Left side of operator ?! is always none, so it's always replaced by the default.")
+		}
+
 		// Check if left and right type is compatible
 		if leftType.Type == data.DT_Option {
 			if !leftType.CanBeAssigned(rightType) {
@@ -138,6 +172,12 @@ func (p *Parser) deriveOperatorType(expression *Node) *data.DataType {
 			return binaryNode.DataType
 		}
 
+		// Tagged unions can only be matched
+		if p.taggedUnionVariants(leftType) != nil {
+			p.newError(expression.Position, "Operator "+expression.NodeType.String()+" can't be used on tagged unions. Use a match statement instead.")
+			return &data.DataType{data.DT_Unknown, nil}
+		}
+
 		// Comparison operators return boolean
 		if expression.NodeType.IsComparisonOperator() {
 			binaryNode.DataType = &data.DataType{data.DT_Bool, nil}
@@ -198,15 +238,25 @@ func GetExpressionType(expression *Node) *data.DataType {
 	case NT_List:
 		return expression.Value.(*ListNode).DataType
 	case NT_ListValue:
-		return GetExpressionType(expression.Value.(*TypedBinaryNode).Left).SubType.(*data.DataType)
+		return expression.Value.(*TypedBinaryNode).DataType
+	case NT_Slice:
+		return expression.Value.(*SliceNode).DataType
+	case NT_Range:
+		return &data.DataType{data.DT_List, &data.DataType{data.DT_Int, nil}}
 	case NT_Enum:
 		return &data.DataType{data.DT_Enum, expression.Value.(*EnumNode).Identifier}
+	case NT_Variant:
+		return &data.DataType{data.DT_Enum, expression.Value.(*VariantNode).Enum}
+	case NT_VariantPayload:
+		return expression.Value.(*VariantPayloadNode).DataType
 	case NT_Object:
-		return &data.DataType{data.DT_Object, expression.Value.(*ObjectNode).Identifier}
+		return expression.Value.(*ObjectNode).DataType
 	case NT_ObjectField:
 		return expression.Value.(*ObjectFieldNode).DataType
 	case NT_Set:
 		return expression.Value.(*ListNode).DataType
+	case NT_Map:
+		return expression.Value.(*MapNode).DataType
 	case NT_Unwrap:
 		return GetExpressionType(expression.Value.(*Node)).SubType.(*data.DataType)
 	case NT_IsNone:
@@ -217,6 +267,10 @@ func GetExpressionType(expression *Node) *data.DataType {
 		return GetExpressionType(expression.Value.(*TypedBinaryNode).Right)
 	case NT_TernaryBranches:
 		return expression.Value.(*TypedBinaryNode).DataType
+	case NT_Lambda:
+		return functionType(expression.Value.(*FunctionDeclareNode).Parameters, expression.Value.(*FunctionDeclareNode).ReturnType)
+	case NT_FunctionReference:
+		return expression.Value.(*FunctionReferenceNode).DataType
 	}
 
 	panic("Can't determine expression data type from " + NodeTypeToString[expression.NodeType] + fmt.Sprintf(" (%d)", expression.NodeType) + ".")
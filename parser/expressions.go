@@ -17,6 +17,11 @@ func (p *Parser) parseExpressionRoot() *Node {
 	p.collectConstant(expression)
 	p.deriveType(expression)
 
+	// Ranges aren't values on their own
+	if expression.NodeType == NT_Range {
+		p.newError(expression.Position, "Range can be only iterated over by forEach or used on the right side of operator in.")
+	}
+
 	return expression
 }
 
@@ -62,14 +67,43 @@ func (p *Parser) parseExpression(currentPrecedence int) *Node {
 	} else if p.peek().TokenType == lexer.TT_Identifier {
 		left = p.parseIdentifier(true)
 
+		// Lambda
+	} else if p.peek().TokenType == lexer.TT_KW_fun {
+		left = p.parseLambda()
+
 		// List
 	} else if p.peek().TokenType == lexer.TT_DL_BracketOpen {
 		left = p.parseEnumeration(false)
 
+		// Map
+	} else if p.peek().TokenType == lexer.TT_DL_BraceOpen && p.isMapLiteral() {
+		left = p.parseMapLiteral()
+
 		// Set
 	} else if p.peek().TokenType == lexer.TT_DL_BraceOpen {
 		left = p.parseEnumeration(true)
 
+		// Map with a type specified
+	} else if p.peek().TokenType == lexer.TT_KW_map {
+		specifiedType := p.parseType()
+
+		// Expression after type isn't a map
+		if p.peek().TokenType != lexer.TT_DL_BraceOpen {
+			left = p.parseExpression(currentPrecedence)
+			p.newError(GetExpressionPosition(left), "Expected expression of the type "+specifiedType.String()+".")
+			// Try to set the type of the expression
+		} else {
+			left = p.parseMapLiteral()
+			expressionType := left.Value.(*MapNode).DataType.Copy()
+			expressionType.TryCompleteFrom(specifiedType)
+
+			// Type of expression after type hint is incompatible with it
+			if !specifiedType.CanBeAssigned(expressionType) {
+				p.newError(GetExpressionPosition(left), "Expression after type hint "+specifiedType.String()+" has the wrong type "+left.Value.(*MapNode).DataType.String()+".")
+			}
+			left.Value.(*MapNode).DataType = specifiedType
+		}
+
 		// List/Set with a type specified
 	} else if p.peek().TokenType.IsCompositeType() {
 		specifiedType := p.parseType()
@@ -126,6 +160,12 @@ func (p *Parser) parseExpression(currentPrecedence int) *Node {
 	for p.peek().TokenType.IsBinaryOperator() && operatorPrecedence(p.peek().TokenType) >= currentPrecedence {
 		operator := p.consume()
 
+		// Range
+		if operator.TokenType == lexer.TT_OP_Range || operator.TokenType == lexer.TT_OP_RangeInclusive || operator.TokenType == lexer.TT_OP_Step {
+			left = p.parseRange(left, operator)
+			continue
+		}
+
 		// Parse right side of expression
 		right := p.parseExpression(operatorPrecedence(operator.TokenType))
 		nodeType := TokenTypeToNodeType[operator.TokenType]
@@ -174,6 +214,33 @@ func (p *Parser) createBinaryNode(position *data.CodePos, nodeType NodeType, lef
 	return &Node{position, nodeType, &TypedBinaryNode{left, right, &data.DataType{data.DT_Unknown, nil}}}
 }
 
+func (p *Parser) parseRange(left *Node, operator *lexer.Token) *Node {
+	right := p.parseExpression(operatorPrecedence(operator.TokenType))
+	p.collectConstant(left)
+	p.collectConstant(right)
+
+	position := GetExpressionPosition(left).Combine(GetExpressionPosition(right))
+
+	// Add step to range
+	if operator.TokenType == lexer.TT_OP_Step {
+		if left.NodeType != NT_Range {
+			p.newError(operator.Position, "Keyword step can be only used after a range.")
+			return left
+		}
+
+		if left.Value.(*RangeNode).Step != nil {
+			p.newError(operator.Position, "Range already has a step.")
+		}
+
+		left.Value.(*RangeNode).Step = right
+		left.Position = position
+
+		return left
+	}
+
+	return &Node{position, NT_Range, &RangeNode{left, right, nil, operator.TokenType == lexer.TT_OP_RangeInclusive}}
+}
+
 func operatorPrecedence(operator lexer.TokenType) int {
 	switch operator {
 	case lexer.TT_OP_UnpackOrDefault:
@@ -187,49 +254,101 @@ func operatorPrecedence(operator lexer.TokenType) int {
 		lexer.TT_OP_LowerEqual, lexer.TT_OP_GreaterEqual,
 		lexer.TT_OP_In:
 		return 4
-	case lexer.TT_OP_Add, lexer.TT_OP_Subtract:
+	case lexer.TT_OP_Step:
 		return 5
-	case lexer.TT_OP_Multiply, lexer.TT_OP_Divide:
+	case lexer.TT_OP_Range, lexer.TT_OP_RangeInclusive:
 		return 6
-	case lexer.TT_OP_Power, lexer.TT_OP_Modulo:
+	case lexer.TT_OP_Add, lexer.TT_OP_Subtract:
 		return 7
-	case lexer.TT_OP_Not:
+	case lexer.TT_OP_Multiply, lexer.TT_OP_Divide:
 		return 8
-	case lexer.TT_OP_Dot:
+	case lexer.TT_OP_Power, lexer.TT_OP_Modulo:
 		return 9
+	case lexer.TT_OP_Not:
+		return 10
+	case lexer.TT_OP_Dot:
+		return 11
+	case lexer.TT_DL_Colon:
+		return 0
 	default:
 		panic("Can't get operator precedence of token type " + operator.String() + ".")
 	}
 }
 
 func (p *Parser) parseIdentifier(isInExpression bool) *Node {
+	p.splitShadowedModule()
 	symbol := p.findSymbol(p.peek().Value)
 
+	// Uses of functions are indexed after their overload is picked
+	if symbol != nil && symbol.symbolType != ST_FunctionBucket {
+		p.indexUse(symbol, p.peek())
+	}
+
 	// Undeclared symbol
 	if symbol == nil {
 		identifier := p.consume()
 
+		// Built-in constant
+		if value, isConstant := BUILT_IN_CONSTANTS[identifier.Value]; isConstant && p.peek().TokenType != lexer.TT_DL_ParenthesisOpen {
+			if !isInExpression {
+				p.newError(identifier.Position, "Can't assign to built-in constant "+identifier.Value+".")
+				return &Node{identifier.Position, NT_Variable, &VariableNode{identifier.Value, &data.DataType{data.DT_Unknown, nil}}}
+			}
+
+			constant := &Node{identifier.Position, NT_Literal, &LiteralNode{data.DT_Float, value}}
+			p.collectConstant(constant)
+
+			return constant
+		}
+
+		notDeclared, notDefined := " is not declared in this scope.", " is not defined in this scope."
+		moduleName, builtInFunction, isBuiltInFunction := p.isQualifiedBuiltInFunction(identifier.Value)
+
+		// Symbol of imported module exists, but isn't public
+		if p.isPrivateSymbol(identifier.Value) && !isBuiltInFunction {
+			notDeclared, notDefined = " isn't public.", " isn't public."
+		}
+
+		// Built-in function isn't a symbol of the module it's accessed through
+		if isBuiltInFunction && p.peek().TokenType != lexer.TT_DL_BraceOpen {
+			p.newError(identifier.Position, "Function "+builtInFunction+" is a built-in function, it isn't a member of module "+moduleName+".")
+
+			if p.peek().TokenType == lexer.TT_DL_ParenthesisOpen {
+				return p.parseFunctionCall(nil, identifier, nil)
+			}
+			return &Node{identifier.Position, NT_Variable, &VariableNode{identifier.Value, &data.DataType{data.DT_Unknown, nil}}}
+		}
+
 		// Undeclared function
 		if p.peek().TokenType == lexer.TT_DL_ParenthesisOpen {
-			p.newError(identifier.Position, "Function "+identifier.Value+" is not declared in this scope.")
-			return p.parseFunctionCall(nil, identifier)
+			p.newError(identifier.Position, "Function "+identifier.Value+notDeclared)
+			return p.parseFunctionCall(nil, identifier, nil)
 			// Undeclared struct
 		} else if p.peek().TokenType == lexer.TT_DL_BraceOpen {
-			p.newError(identifier.Position, "Struct "+identifier.Value+" is not defined in this scope.")
+			p.newError(identifier.Position, "Struct "+identifier.Value+notDefined)
 
 			p.consume() // {
 			p.parseAnyProperties()
 			p.consume() // }
 
-			return &Node{identifier.Position, NT_Object, &ObjectNode{identifier.Value, []*Node{}}}
+			return &Node{identifier.Position, NT_Object, &ObjectNode{identifier.Value, []*Node{}, &data.DataType{data.DT_Object, identifier.Value}}}
 			// Undeclared variable
 		} else {
-			p.newError(identifier.Position, "Variable "+identifier.Value+" is not declared in this scope.")
+			p.newError(identifier.Position, "Variable "+identifier.Value+notDeclared)
 			return &Node{identifier.Position, NT_Variable, &VariableNode{identifier.Value, &data.DataType{data.DT_Unknown, nil}}}
 		}
 		// Function call
 	} else if symbol.symbolType == ST_FunctionBucket {
-		return p.parseFunctionCall(symbol, p.consume())
+		// Built-in fromJson has a type argument
+		if p.peek().Value == "fromJson" && p.isBuiltInFunction(symbol) && (p.peekNext().TokenType == lexer.TT_OP_Lower || p.peekNext().TokenType == lexer.TT_DL_ParenthesisOpen) {
+			return p.parseFromJson(symbol)
+		}
+
+		// Function used as a value
+		if p.peekNext().TokenType != lexer.TT_DL_ParenthesisOpen {
+			return p.parseFunctionReference(symbol, p.consume())
+		}
+		return p.parseFunctionCall(symbol, p.consume(), nil)
 		// Variable
 	} else if symbol.symbolType == ST_Variable {
 		if !isInExpression {
@@ -241,10 +360,21 @@ func (p *Parser) parseIdentifier(isInExpression bool) *Node {
 		identifierToken := p.consume()
 		p.consume() // .
 
+		// Tagged union
+		if variants, isTaggedUnion := symbol.value.(map[string]*VariantSymbol); isTaggedUnion {
+			return p.parseVariant(identifierToken, variants)
+		}
+
 		return &Node{identifierToken.Position.Combine(p.peek().Position), NT_Enum, &EnumNode{identifierToken.Value, symbol.value.(map[string]int64)[p.consume().Value]}}
 		// Struct
 	} else if symbol.symbolType == ST_Struct {
 		return p.parseStructLiteral(symbol.value.(map[string]PropertySymbol))
+		// Module
+	} else if symbol.symbolType == ST_Module {
+		identifier := p.consume()
+		p.newError(identifier.Position, "Module "+identifier.Value+" can't be used as a value.")
+
+		return &Node{identifier.Position, NT_Variable, &VariableNode{identifier.Value, &data.DataType{data.DT_Unknown, nil}}}
 	}
 
 	return nil
@@ -259,26 +389,107 @@ func (p *Parser) parseVariable(symbol *Symbol) *Node {
 	}
 
 	identifierToken := p.consume()
+	identifier := p.variableIdentifier(identifierToken.Value, symbol)
+
+	// Call of function stored in variable
+	if p.peek().TokenType == lexer.TT_DL_ParenthesisOpen {
+		variable := &Node{identifierToken.Position, NT_Variable, &VariableNode{identifier, variableSymbol.VariableType}}
+		return p.parseFunctionValueCall(variable, identifierToken)
+	}
 
 	// List element
 	if p.peek().TokenType == lexer.TT_DL_BracketOpen {
 		// Consume index expression
 		for p.peek().TokenType == lexer.TT_DL_BracketOpen {
 			p.consume() // [
-			variable := &Node{identifierToken.Position, NT_Variable, &VariableNode{identifierToken.Value, symbol.value.(*VariableSymbol).VariableType}}
-			listValue := &Node{identifierToken.Position, NT_ListValue, &TypedBinaryNode{variable, p.parseExpressionRoot(), symbol.value.(*VariableSymbol).VariableType.SubType.(*data.DataType)}}
+			variable := &Node{identifierToken.Position, NT_Variable, &VariableNode{identifier, symbol.value.(*VariableSymbol).VariableType}}
+
+			// Slice without start index
+			if p.peek().TokenType == lexer.TT_DL_Colon {
+				return p.parseSlice(variable, nil, identifierToken)
+			}
+
+			indexExpression := p.parseIndexExpression()
+
+			// Slice
+			if p.peek().TokenType == lexer.TT_DL_Colon {
+				return p.parseSlice(variable, indexExpression, identifierToken)
+			}
+
+			var elementType *data.DataType
+
+			// Map value
+			if variableSymbol.VariableType.Type == data.DT_Map {
+				mapType := variableSymbol.VariableType.SubType.(*data.MapType)
+				elementType = mapType.Value
+
+				// Check key type
+				keyType := GetExpressionType(indexExpression)
+				if keyType.Type != data.DT_Unknown && !mapType.Key.CanBeAssigned(keyType) {
+					p.newError(GetExpressionPosition(indexExpression), "Map "+identifierToken.Value+" has keys of type "+mapType.Key.String()+", but was indexed with expression of type "+keyType.String()+".")
+				}
+				// Character of string
+			} else if variableSymbol.VariableType.Type == data.DT_String {
+				elementType = &data.DataType{data.DT_String, nil}
+				// List element
+			} else {
+				elementType = variableSymbol.VariableType.SubType.(*data.DataType)
+			}
+
+			listValue := &Node{identifierToken.Position, NT_ListValue, &TypedBinaryNode{variable, indexExpression, elementType}}
 			p.consume() // ]
 
 			return listValue
 		}
 		// Object field
 	} else if p.peek().TokenType == lexer.TT_OP_Dot {
-		variableNode := &Node{identifierToken.Position, NT_Variable, &VariableNode{identifierToken.Value, variableSymbol.VariableType}}
+		variableNode := &Node{identifierToken.Position, NT_Variable, &VariableNode{identifier, variableSymbol.VariableType}}
 		return p.parseObjectField(variableNode, variableSymbol.VariableType, identifierToken)
 	}
 
 	// Normal variable
-	return &Node{identifierToken.Position, NT_Variable, &VariableNode{identifierToken.Value, symbol.value.(*VariableSymbol).VariableType}}
+	return &Node{identifierToken.Position, NT_Variable, &VariableNode{identifier, symbol.value.(*VariableSymbol).VariableType}}
+}
+
+// Parses index expression, which ends before a colon of a slice.
+func (p *Parser) parseIndexExpression() *Node {
+	expression := p.parseExpression(operatorPrecedence(lexer.TT_OP_UnpackOrDefault))
+	p.collectConstant(expression)
+	p.deriveType(expression)
+
+	return expression
+}
+
+func (p *Parser) parseSlice(sliced *Node, start *Node, identifierToken *lexer.Token) *Node {
+	p.consume() // :
+
+	// Collect end index
+	var end *Node = nil
+	if p.peek().TokenType != lexer.TT_DL_BracketClose {
+		end = p.parseIndexExpression()
+	}
+
+	closingBracket := p.consume() // ]
+	slicedType := GetExpressionType(sliced)
+
+	// Only lists and strings can be sliced
+	if slicedType.Type != data.DT_List && slicedType.Type != data.DT_String && slicedType.Type != data.DT_Unknown {
+		p.newError(identifierToken.Position, "Can't slice "+identifierToken.Value+", because it's not a list or a string.")
+	}
+
+	// Indexes have to be integers
+	for _, index := range []*Node{start, end} {
+		if index == nil {
+			continue
+		}
+
+		indexType := GetExpressionType(index)
+		if indexType.Type != data.DT_Int && indexType.Type != data.DT_Unknown {
+			p.newError(GetExpressionPosition(index), "Slice index has to be of type int, but is of type "+indexType.String()+".")
+		}
+	}
+
+	return &Node{identifierToken.Position.Combine(closingBracket.Position), NT_Slice, &SliceNode{sliced, start, end, slicedType}}
 }
 
 func (p *Parser) parseObjectField(left *Node, leftType *data.DataType, identifierToken *lexer.Token) *Node {
@@ -289,17 +500,36 @@ func (p *Parser) parseObjectField(left *Node, leftType *data.DataType, identifie
 		p.newError(p.peek().Position, "Can't access a property of "+identifierToken.String()+", because it's not a struct.")
 	} else {
 		// Find struct definition
-		structName := leftType.SubType.(string)
+		structName := leftType.StructName()
 		structSymbol := p.getGlobalSymbol(structName)
 
 		// Check if field exists
 		property, propertyExists := structSymbol.value.(map[string]PropertySymbol)[p.peek().Value]
 
+		// Replace type parameters in property type of generic struct
+		if propertyExists {
+			property.dataType = property.dataType.Substitute(p.objectTypeArguments(leftType))
+		}
+
+		// Method call
+		if !propertyExists && p.peekNext().TokenType == lexer.TT_DL_ParenthesisOpen {
+			methodSymbol := p.getGlobalSymbol(structName + "." + p.peek().Value)
+
+			if methodSymbol != nil {
+				return p.parseFunctionCall(methodSymbol, p.consume(), left)
+			}
+		}
+
 		if !propertyExists {
 			p.newError(p.peek().Position, "Struct "+structName+" doesn't have a property "+p.consume().Value+".")
 		} else {
 			node := &Node{identifierToken.Position.Combine(p.consume().Position), NT_ObjectField, &ObjectFieldNode{left, property.number, property.dataType}}
 
+			// Call of function stored in a property
+			if p.peek().TokenType == lexer.TT_DL_ParenthesisOpen {
+				return p.parseFunctionValueCall(node, p.peekPrevious())
+			}
+
 			// Another field access
 			if p.peek().TokenType == lexer.TT_OP_Dot {
 				return p.parseObjectField(node, property.dataType, p.peekPrevious())
@@ -5,19 +5,32 @@ import (
 	"github.com/DanielNos/neco/lexer"
 )
 
-func (p *Parser) parseFunctionDeclaration() *Node {
+func (p *Parser) parseFunctionDeclaration(className string) *Node {
 	start := p.consume().Position
 	identifierToken := p.consume()
+	identifier := identifierToken.Value
+
+	// Methods are named after their class, functions of imported modules after their module
+	if className != "" {
+		identifier = className + "." + identifier
+	} else {
+		identifier = p.qualifyGlobal(identifier)
+	}
 
 	// Find function symbol
 	function := p.functions[p.functionIndex]
 	p.functionIndex++
 
 	// Enter scope
+	typeParameters := p.parseTypeParameters()
+	headerStart := p.tokenIndex
 	p.consume()
 	p.enterScope()
+	p.indexEnterFunction(start)
+
+	// Insert type parameters and parameters to scope
+	p.insertTypeParameters(typeParameters)
 
-	// Insert parameters to scope
 	for _, parameter := range function.parameters {
 		p.insertSymbol(parameter.Identifier, &Symbol{ST_Variable, &VariableSymbol{parameter.DataType, true, false}})
 	}
@@ -31,6 +44,7 @@ func (p *Parser) parseFunctionDeclaration() *Node {
 		}
 		p.consume()
 	}
+	p.indexParameters(headerStart, function.parameters)
 
 	// Parse body
 	if p.peek().TokenType == lexer.TT_EndOfCommand {
@@ -41,15 +55,16 @@ func (p *Parser) parseFunctionDeclaration() *Node {
 	// Check if function has return statements in all paths
 	if function.returnType.Type != data.DT_Unknown {
 		if !p.verifyReturns(body, function.returnType) {
-			p.newError(returnPosition, "Function "+identifierToken.Value+" with return type "+function.returnType.String()+" does not return a value in all code paths.")
+			p.newError(returnPosition, "Function "+identifier+" with return type "+function.returnType.String()+" does not return a value in all code paths.")
 		}
 	}
 
 	p.leaveScope()
+	p.indexLeaveFunction(p.peekPrevious().Position)
 
 	// Store function name as a string constant for scope trace back
-	p.StringConstants[identifierToken.Value] = -1
-	return &Node{start.Combine(p.peekPrevious().Position), NT_FunctionDeclaration, &FunctionDeclareNode{p.functionIndex - 1, identifierToken.Value, function.parameters, function.returnType, body}}
+	p.StringConstants[identifier] = -1
+	return &Node{start.Combine(p.peekPrevious().Position), NT_FunctionDeclaration, &FunctionDeclareNode{p.functionIndex - 1, identifier, function.parameters, function.returnType, body}}
 }
 
 func (p *Parser) parseParameters() []Parameter {
@@ -63,22 +78,23 @@ func (p *Parser) parseParameters() []Parameter {
 	for {
 		// Collect data typa and identifier
 		dataType := p.parseType()
-		identifier := p.consume().Value
+		identifierToken := p.consume()
+		identifier := identifierToken.Value
 
 		// Create parameter and symbol
 		parameters = append(parameters, Parameter{dataType, identifier, nil})
-		p.insertSymbol(identifier, &Symbol{ST_Variable, &VariableSymbol{dataType, true, false}})
+		p.insertParameter(identifierToken, dataType)
 
 		if p.peek().TokenType == lexer.TT_DL_ParenthesisClose {
 			break
 		}
 		p.consume()
 
-		for p.peek().TokenType == lexer.TT_Identifier {
+		for p.peek().TokenType == lexer.TT_Identifier && !p.isTypeName(p.peek().Value) {
 			// Create parameter and symbol
-			identifier = p.consume().Value
-			parameters = append(parameters, Parameter{dataType, identifier, nil})
-			p.insertSymbol(identifier, &Symbol{ST_Variable, &VariableSymbol{dataType, true, false}})
+			identifierToken = p.consume()
+			parameters = append(parameters, Parameter{dataType, identifierToken.Value, nil})
+			p.insertParameter(identifierToken, dataType)
 
 			p.consume()
 		}
@@ -87,11 +103,27 @@ func (p *Parser) parseParameters() []Parameter {
 	return parameters
 }
 
-func (p *Parser) parseFunctionCall(functionBucketSymbol *Symbol, identifier *lexer.Token) *Node {
+// Inserts parameter and records it's declaration. Parameters can have the same identifiers as variables of outer scopes.
+func (p *Parser) insertParameter(identifierToken *lexer.Token, dataType *data.DataType) {
+	symbol := &Symbol{ST_Variable, &VariableSymbol{dataType, true, false}}
+	p.insertSymbol(identifierToken.Value, symbol)
+
+	if declaration := p.indexDeclaration(symbol, identifierToken.Value, identifierToken.Position); declaration != nil {
+		declaration.IsParameter = true
+	}
+}
+
+func (p *Parser) parseFunctionCall(functionBucketSymbol *Symbol, identifier *lexer.Token, receiver *Node) *Node {
 	// Collect arguments
 	p.consume()
 	arguments, argumentTypes, errorsInArguments := p.parseArguments()
 
+	// Receiver of method is matched as it's first argument
+	matchedArguments := arguments
+	if receiver != nil {
+		matchedArguments = append([]*Node{receiver}, arguments...)
+	}
+
 	// Check if arguments match any function
 	returnType := &data.DataType{data.DT_Unknown, nil}
 	functionNumber := -1
@@ -99,30 +131,190 @@ func (p *Parser) parseFunctionCall(functionBucketSymbol *Symbol, identifier *lex
 	// Try to match arguments to some function from the bucket
 	if functionBucketSymbol != nil && !errorsInArguments {
 		// Function is picked by matching arguments
-		functionSymbol := p.matchArguments(functionBucketSymbol, arguments, identifier)
+		functionSymbol, typeArguments := p.matchArguments(functionBucketSymbol, matchedArguments, identifier)
 
 		if functionSymbol != nil {
+			p.indexFunctionUse(functionBucketSymbol, functionSymbol, identifier)
+
 			// Store values from function symbol
 			returnType = functionSymbol.returnType
 			functionNumber = functionSymbol.number
 
+			// Replace type parameters in return type of generic function
+			if len(typeArguments) != 0 && returnType != nil {
+				returnType = returnType.Substitute(typeArguments)
+			}
+
 			// Set function as used
 			functionSymbol.everCalled = true
 
+			// Check arguments of built-in functions
+			if functionNumber == -1 {
+				p.checkBuiltInArguments(identifier, matchedArguments)
+			}
+
+			// Schema of converted value is passed to toJson
+			if functionNumber == -1 && identifier.Value == "toJson" {
+				arguments = append(arguments, p.jsonSchemaArgument(argumentTypes[0], GetExpressionPosition(arguments[0])))
+				argumentTypes = append(argumentTypes, &data.DataType{data.DT_String, nil})
+			}
+
 			// Insert empty string argument to printLine function without arguments
 			if identifier.Value == "printLine" && len(arguments) == 0 {
 				arguments = append(arguments, &Node{Position: identifier.Position, NodeType: NT_Literal, Value: &LiteralNode{data.DT_String, ""}})
 				argumentTypes = append(argumentTypes, &data.DataType{data.DT_String, nil})
 				p.StringConstants[""] = -1
 			}
+
+			// Keys and values of maps are lists of map's key/value type
+			if functionNumber == -1 && (identifier.Value == "keys" || identifier.Value == "values") {
+				mapType := GetExpressionType(arguments[0]).SubType.(*data.MapType)
+
+				if identifier.Value == "keys" {
+					returnType = &data.DataType{data.DT_List, mapType.Key}
+				} else {
+					returnType = &data.DataType{data.DT_List, mapType.Value}
+				}
+			}
 		}
 	}
 	p.consume()
 
-	return &Node{identifier.Position, NT_FunctionCall, &FunctionCallNode{functionNumber, identifier.Value, arguments, argumentTypes, returnType}}
+	return &Node{identifier.Position, NT_FunctionCall, &FunctionCallNode{functionNumber, identifier.Value, arguments, argumentTypes, returnType, nil, receiver}}
+}
+
+func (p *Parser) parseFunctionValueCall(function *Node, identifier *lexer.Token) *Node {
+	// Collect arguments
+	p.consume()
+	arguments, argumentTypes, errorsInArguments := p.parseArguments()
+	p.consume()
+
+	returnType := &data.DataType{data.DT_Unknown, nil}
+	calledType := GetExpressionType(function)
+
+	// Called expression isn't a function
+	if calledType.Type != data.DT_Function {
+		if calledType.Type != data.DT_Unknown {
+			p.newError(identifier.Position, "Can't call "+identifier.Value+", because it's not a function. It has type "+calledType.String()+".")
+		}
+
+		return &Node{identifier.Position, NT_FunctionCall, &FunctionCallNode{-1, identifier.Value, arguments, argumentTypes, returnType, function, nil}}
+	}
+
+	signature := calledType.SubType.(*data.FunctionType)
+
+	if signature.ReturnType != nil {
+		returnType = signature.ReturnType
+	}
+
+	// Check if arguments match parameters
+	if !errorsInArguments {
+		matched := len(signature.Parameters) == len(argumentTypes)
+
+		for i := 0; matched && i < len(argumentTypes); i++ {
+			matched = signature.Parameters[i].CanBeAssigned(argumentTypes[i])
+		}
+
+		if !matched {
+			p.newError(identifier.Position, "Arguments of call of "+identifier.Value+" don't match it's type "+calledType.String()+".")
+		}
+	}
+
+	return &Node{identifier.Position, NT_FunctionCall, &FunctionCallNode{-1, identifier.Value, arguments, argumentTypes, returnType, function, nil}}
+}
+
+func (p *Parser) parseFunctionReference(bucket *Symbol, identifier *lexer.Token) *Node {
+	functions := bucket.value.(symbolTable)
+
+	// Overloaded functions can't be picked without arguments
+	if len(functions) != 1 {
+		p.newError(identifier.Position, "Function "+identifier.Value+" is overloaded, so it can't be used as a value.")
+		return &Node{identifier.Position, NT_FunctionReference, &FunctionReferenceNode{-1, identifier.Value, &data.DataType{data.DT_Unknown, nil}}}
+	}
+
+	var function *FunctionSymbol
+	for _, symbol := range functions {
+		function = symbol.value.(*FunctionSymbol)
+		p.indexUse(symbol, identifier)
+	}
+
+	// Type arguments of generic functions are inferred from arguments
+	if function.isGeneric() {
+		p.newError(identifier.Position, "Generic function "+identifier.Value+" can't be used as a value.")
+		return &Node{identifier.Position, NT_FunctionReference, &FunctionReferenceNode{-1, identifier.Value, &data.DataType{data.DT_Unknown, nil}}}
+	}
+
+	// Built-in functions don't have a body
+	if function.number == -1 {
+		p.newError(identifier.Position, "Built-in function "+identifier.Value+" can't be used as a value.")
+		return &Node{identifier.Position, NT_FunctionReference, &FunctionReferenceNode{-1, identifier.Value, &data.DataType{data.DT_Unknown, nil}}}
+	}
+
+	// Function can be called through the value
+	function.everCalled = true
+
+	return &Node{identifier.Position, NT_FunctionReference, &FunctionReferenceNode{function.number, identifier.Value, functionType(function.parameters, function.returnType)}}
 }
 
-func (p *Parser) matchArguments(bucket *Symbol, arguments []*Node, identifierToken *lexer.Token) *FunctionSymbol {
+func (p *Parser) parseLambda() *Node {
+	start := p.consume().Position
+
+	// Enter scope
+	p.consume() // (
+	p.enterScope()
+	p.indexEnterFunction(start)
+
+	// Collect parameters and insert them to scope
+	parameters := p.parseParameters()
+	p.consume() // )
+
+	// Collect return type
+	returnType := &data.DataType{data.DT_Unknown, nil}
+	var returnPosition *data.CodePos
+
+	if p.peek().TokenType == lexer.TT_KW_returns {
+		returnPosition = p.consume().Position
+		returnType = p.parseType()
+		returnPosition.EndChar = p.peekPrevious().Position.EndChar
+	}
+
+	// Parse body
+	if p.peek().TokenType == lexer.TT_EndOfCommand {
+		p.consume()
+	}
+	body := p.parseScope(false, true).(*Node)
+
+	// Check if lambda has return statements in all paths
+	if returnType.Type != data.DT_Unknown {
+		if !p.verifyReturns(body, returnType) {
+			p.newError(returnPosition, "Lambda with return type "+returnType.String()+" does not return a value in all code paths.")
+		}
+	}
+
+	p.leaveScope()
+	p.indexLeaveFunction(p.peekPrevious().Position)
+
+	// Store lambda name as a string constant for scope trace back
+	p.StringConstants["lambda"] = -1
+	return &Node{start.Combine(p.peekPrevious().Position), NT_Lambda, &FunctionDeclareNode{-1, "lambda", parameters, returnType, body}}
+}
+
+// Creates data type of function with provided parameters and return type.
+func functionType(parameters []Parameter, returnType *data.DataType) *data.DataType {
+	parameterTypes := make([]*data.DataType, len(parameters))
+
+	for i, parameter := range parameters {
+		parameterTypes[i] = parameter.DataType
+	}
+
+	if returnType.Type == data.DT_Unknown {
+		return &data.DataType{data.DT_Function, &data.FunctionType{parameterTypes, nil}}
+	}
+
+	return &data.DataType{data.DT_Function, &data.FunctionType{parameterTypes, returnType}}
+}
+
+func (p *Parser) matchArguments(bucket *Symbol, arguments []*Node, identifierToken *lexer.Token) (*FunctionSymbol, map[string]*data.DataType) {
 	// Collect argument data types
 	argumentTypes := make([]*data.DataType, len(arguments))
 
@@ -130,33 +322,40 @@ func (p *Parser) matchArguments(bucket *Symbol, arguments []*Node, identifierTok
 		argumentTypes[i] = GetExpressionType(argument)
 	}
 
-	for _, function := range bucket.value.(symbolTable) {
-		// Incorrect argument amount
-		if len(function.value.(*FunctionSymbol).parameters) != len(arguments) {
-			continue
-		}
+	// Functions without type parameters are preferred over generic ones
+	for _, matchGeneric := range []bool{false, true} {
+		for _, function := range bucket.value.(symbolTable) {
+			functionSymbol := function.value.(*FunctionSymbol)
 
-		// Try to match arguments to parameters
-		matched := true
-		for i, parameter := range function.value.(*FunctionSymbol).parameters {
-			if !parameter.DataType.CanBeAssigned(argumentTypes[i]) {
-				matched = false
-				break
+			// Incorrect argument amount or function is matched in the other pass
+			if len(functionSymbol.parameters) != len(arguments) || functionSymbol.isGeneric() != matchGeneric {
+				continue
 			}
-		}
 
-		// Failed to match
-		if !matched {
-			continue
-		}
+			// Try to match arguments to parameters and infer type arguments
+			matched := true
+			typeArguments := map[string]*data.DataType{}
+
+			for i, parameter := range functionSymbol.parameters {
+				if !parameter.DataType.InferTypeArguments(argumentTypes[i], typeArguments) {
+					matched = false
+					break
+				}
+			}
+
+			// Failed to match
+			if !matched {
+				continue
+			}
 
-		// Successfully matched
-		return function.value.(*FunctionSymbol)
+			// Successfully matched
+			return functionSymbol, typeArguments
+		}
 	}
 
 	// Failed to match to all functions in a bucket
 	p.newError(identifierToken.Position, "Failed to match function "+identifierToken.Value+" to any function header. Check if all arguments have the correct type and if there is the correct amount of them.")
-	return nil
+	return nil, nil
 }
 
 func (p *Parser) parseArguments() ([]*Node, []*data.DataType, bool) {
@@ -209,24 +408,38 @@ func (p *Parser) verifyReturns(statementList *Node, returnType *data.DataType) b
 				}
 			}
 
+			return true
+			// Throw ends the code path
+		} else if statement.NodeType == NT_Throw {
 			return true
 			// If statement
 		} else if statement.NodeType == NT_If {
 			ifNode := statement.Value.(*IfNode)
 
 			// Check if bodies
+			allReturn := true
 			for _, ifStatement := range ifNode.IfStatements {
 				if !p.verifyReturns(ifStatement.Body, returnType) {
-					return false
+					allReturn = false
 				}
 			}
 
-			// Check else body
-			if ifNode.ElseBody != nil && !p.verifyReturns(ifNode.ElseBody, returnType) {
-				return false
+			// Check else body, if statement without else body doesn't return in all code paths
+			if ifNode.ElseBody == nil || !p.verifyReturns(ifNode.ElseBody, returnType) {
+				allReturn = false
 			}
 
-			return true
+			// Statements after if statement can still return
+			if allReturn {
+				return true
+			}
+			// Try statement returns if both try and catch bodies return
+		} else if statement.NodeType == NT_Try {
+			tryNode := statement.Value.(*TryNode)
+
+			if p.verifyReturns(tryNode.Body, returnType) && p.verifyReturns(tryNode.CatchBody, returnType) {
+				return true
+			}
 		}
 	}
 
@@ -1,14 +1,50 @@
 package parser
 
 import (
+	"strings"
+	"unicode/utf8"
+
 	data "github.com/DanielNos/neco/dataStructures"
 	"github.com/DanielNos/neco/lexer"
 )
 
+// Splits qualified identifier at current token back to a variable, a dot and a property, if the module name is shadowed by a variable.
+func (p *Parser) splitShadowedModule() {
+	token := p.peek()
+	variable, property, isQualified := strings.Cut(token.Value, ".")
+
+	if token.TokenType != lexer.TT_Identifier || !isQualified {
+		return
+	}
+
+	symbol := p.findSymbol(variable)
+
+	if symbol == nil || symbol.symbolType != ST_Variable {
+		return
+	}
+
+	// Identifiers can't span multiple lines
+	position := token.Position
+	variableEnd := position.StartChar + uint(utf8.RuneCountInString(variable)) - 1
+	propertyStart := position.EndChar - uint(utf8.RuneCountInString(property)) + 1
+
+	split := []*lexer.Token{
+		{Position: &data.CodePos{File: position.File, StartLine: position.StartLine, EndLine: position.StartLine, StartChar: position.StartChar, EndChar: variableEnd}, TokenType: lexer.TT_Identifier, Value: variable},
+		{Position: &data.CodePos{File: position.File, StartLine: position.StartLine, EndLine: position.StartLine, StartChar: variableEnd + 1, EndChar: variableEnd + 1}, TokenType: lexer.TT_OP_Dot, Value: ""},
+		{Position: &data.CodePos{File: position.File, StartLine: position.EndLine, EndLine: position.EndLine, StartChar: propertyStart, EndChar: position.EndChar}, TokenType: lexer.TT_Identifier, Value: property},
+	}
+
+	tokens := make([]*lexer.Token, 0, len(p.tokens)+2)
+	tokens = append(tokens, p.tokens[:p.tokenIndex]...)
+	tokens = append(tokens, split...)
+	p.tokens = append(tokens, p.tokens[p.tokenIndex+1:]...)
+}
+
 func (p *Parser) parseIdentifierStatement() *Node {
+	p.splitShadowedModule()
+
 	// Struct or enum variable declaration
-	symbol := p.getGlobalSymbol(p.peek().Value)
-	if symbol != nil && (symbol.symbolType == ST_Struct || symbol.symbolType == ST_Enum) {
+	if p.isTypeName(p.peek().Value) {
 		return p.parseVariableDeclaration(false)
 	}
 
@@ -49,6 +85,8 @@ func (p *Parser) parseIdentifierStatement() *Node {
 			p.newError(statement.Position, "Can't assign to a function call.")
 		} else if statement.NodeType.IsOperator() {
 			p.newError(statement.Position, "Can't assign to an expression.")
+		} else if statement.NodeType == NT_Slice {
+			p.newError(statement.Position, "Can't assign to a slice.")
 		}
 	}
 
@@ -63,7 +101,7 @@ func (p *Parser) parseVariableIdentifiers(variableType *data.DataType) ([]*Node,
 
 	for p.peek().TokenType != lexer.TT_EndOfFile {
 		identifiers = append(identifiers, p.peek().Value)
-		variables = append(variables, &Node{p.peek().Position, NT_Variable, &VariableNode{p.peek().Value, variableType}})
+		variables = append(variables, &Node{p.peek().Position, NT_Variable, &VariableNode{p.qualifyGlobals([]string{p.peek().Value})[0], variableType}})
 
 		// Check if variable is redeclared
 		symbol := p.getSymbol(p.peek().Value)
@@ -138,5 +138,7 @@ func (p *Parser) parseCondition(removeParenthesis bool) *Node {
 		p.newError(condition.Position, "Condition expression data type has to be Bool.")
 	}
 
+	p.checkConstantCondition(condition)
+
 	return condition
 }
@@ -0,0 +1,340 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	data "github.com/DanielNos/neco/dataStructures"
+	"github.com/DanielNos/neco/lexer"
+)
+
+// Declarations of symbols and their uses collected by parser. It's used by the language server.
+type SymbolIndex struct {
+	Declarations []*Declaration
+	Uses         []*Use
+
+	declarations map[*Symbol]*Declaration
+	positions    map[string]*Declaration // Declarations by their position, so symbols declared twice by the parser share them
+	uses         map[string]bool
+
+	functions []*data.CodePos // Positions of functions, which are being parsed
+}
+
+type Declaration struct {
+	Identifier  string
+	Kind        string
+	Description string        // Declaration with types, for example fun add(int a, int b) -> int
+	Signature   *Signature    // Signature of function, nil for other symbols
+	Position    *data.CodePos // Nil for built-in symbols
+	Function    *data.CodePos // Function in which the symbol is declared, nil for global symbols
+	IsGlobal    bool
+	IsParameter bool
+}
+
+type Signature struct {
+	Label      string
+	Parameters []string
+}
+
+type Use struct {
+	Position    *data.CodePos
+	Declaration *Declaration
+}
+
+func NewSymbolIndex() *SymbolIndex {
+	return &SymbolIndex{
+		Declarations: []*Declaration{},
+		Uses:         []*Use{},
+
+		declarations: map[*Symbol]*Declaration{},
+		positions:    map[string]*Declaration{},
+		uses:         map[string]bool{},
+
+		functions: []*data.CodePos{},
+	}
+}
+
+// Finds declaration or use of symbol at position. Line and character start at 1.
+func (si *SymbolIndex) Find(file string, line, char uint) (*Declaration, *data.CodePos) {
+	for _, use := range si.Uses {
+		if contains(use.Position, file, line, char) {
+			return use.Declaration, use.Position
+		}
+	}
+
+	for _, declaration := range si.Declarations {
+		if declaration.Position != nil && contains(declaration.Position, file, line, char) {
+			return declaration, declaration.Position
+		}
+	}
+
+	return nil, nil
+}
+
+// Collects positions of all uses of declaration.
+func (si *SymbolIndex) References(declaration *Declaration) []*data.CodePos {
+	references := []*data.CodePos{}
+
+	for _, use := range si.Uses {
+		if use.Declaration == declaration {
+			references = append(references, use.Position)
+		}
+	}
+
+	return references
+}
+
+// Collects declarations visible at position: global and built-in symbols, and symbols of the function declared before the position.
+func (si *SymbolIndex) Visible(file string, line, char uint) []*Declaration {
+	visible := []*Declaration{}
+	identifiers := map[string]bool{}
+
+	for _, declaration := range si.Declarations {
+		isVisible := declaration.Position == nil && declaration.IsGlobal ||
+			declaration.Position != nil && *declaration.Position.File == file && (declaration.IsGlobal ||
+				declaration.Function != nil && contains(declaration.Function, file, line, char) && isBefore(declaration.Position, line, char))
+
+		// Overloaded functions are listed once
+		if isVisible && !identifiers[declaration.Identifier] {
+			identifiers[declaration.Identifier] = true
+			visible = append(visible, declaration)
+		}
+	}
+
+	sort.Slice(visible, func(i, j int) bool { return visible[i].Identifier < visible[j].Identifier })
+
+	return visible
+}
+
+// Collects signatures of functions with identifier. Functions of imported modules are qualified with module name.
+func (si *SymbolIndex) Signatures(identifier string) []*Signature {
+	signatures := []*Signature{}
+	labels := map[string]bool{}
+	moduleName, functionName, isQualified := strings.Cut(identifier, ".")
+
+	for _, declaration := range si.Declarations {
+		// Built-in functions are declared in every module
+		if declaration.Signature == nil || labels[declaration.Signature.Label] {
+			continue
+		}
+
+		if declaration.Identifier == identifier || isQualified && declaration.Identifier == functionName && declaration.Position != nil && *declaration.Position.File == moduleName {
+			labels[declaration.Signature.Label] = true
+			signatures = append(signatures, declaration.Signature)
+		}
+	}
+
+	sort.Slice(signatures, func(i, j int) bool { return signatures[i].Label < signatures[j].Label })
+
+	return signatures
+}
+
+func contains(codePos *data.CodePos, file string, line, char uint) bool {
+	if *codePos.File != file || line < codePos.StartLine || line > codePos.EndLine {
+		return false
+	}
+
+	return (line != codePos.StartLine || char >= codePos.StartChar) && (line != codePos.EndLine || char <= codePos.EndChar)
+}
+
+func isBefore(codePos *data.CodePos, line, char uint) bool {
+	return codePos.StartLine < line || codePos.StartLine == line && codePos.StartChar <= char
+}
+
+func positionKey(codePos *data.CodePos) string {
+	return fmt.Sprintf("%s:%d:%d", *codePos.File, codePos.StartLine, codePos.StartChar)
+}
+
+// Records declaration of symbol. Position is nil for built-in symbols.
+func (p *Parser) indexDeclaration(symbol *Symbol, identifier string, position *data.CodePos) *Declaration {
+	if p.Index == nil {
+		return nil
+	}
+
+	// Symbol declared again at the same position, for example parameters in function header and function body
+	if position != nil {
+		if declaration, exists := p.Index.positions[positionKey(position)]; exists {
+			p.Index.declarations[symbol] = declaration
+
+			if !declaration.IsGlobal && declaration.Function == nil && len(p.Index.functions) != 0 {
+				declaration.Function = p.Index.functions[len(p.Index.functions)-1]
+			}
+			return declaration
+		}
+	}
+
+	// Built-in symbols are global
+	isGlobal := p.stack_symbolTableStack.Size <= 1 || position == nil && p.stack_symbolTableStack.Bottom.Value.(symbolTable)[identifier] == symbol
+	declaration := &Declaration{identifier, symbol.symbolType.String(), describeSymbol(symbol, identifier), nil, position, nil, isGlobal, false}
+
+	if symbol.symbolType == ST_Function {
+		declaration.Signature = functionSignature(symbol.value.(*FunctionSymbol), identifier)
+		declaration.IsGlobal = true
+	}
+
+	if !declaration.IsGlobal && len(p.Index.functions) != 0 {
+		declaration.Function = p.Index.functions[len(p.Index.functions)-1]
+	}
+
+	p.Index.Declarations = append(p.Index.Declarations, declaration)
+	p.Index.declarations[symbol] = declaration
+
+	if position != nil {
+		p.Index.positions[positionKey(position)] = declaration
+	}
+
+	return declaration
+}
+
+// Finds position of symbol's declaration. Returns nil if symbols aren't indexed.
+func (p *Parser) declarationPosition(symbol *Symbol) *data.CodePos {
+	if p.Index == nil {
+		return nil
+	}
+
+	if declaration, exists := p.Index.declarations[symbol]; exists {
+		return declaration.Position
+	}
+
+	return nil
+}
+
+// Records use of symbol by token.
+func (p *Parser) indexUse(symbol *Symbol, token *lexer.Token) {
+	if p.Index == nil || symbol == nil {
+		return
+	}
+
+	declaration, exists := p.Index.declarations[symbol]
+
+	// Built-in symbols and parameters created by the parser don't have a declaration
+	if !exists {
+		declaration = p.indexDeclaration(symbol, token.Value, nil)
+	}
+
+	// Tokens can be parsed multiple times
+	key := positionKey(token.Position)
+	if p.Index.uses[key] {
+		return
+	}
+
+	p.Index.uses[key] = true
+	p.Index.Uses = append(p.Index.Uses, &Use{token.Position, declaration})
+}
+
+// Records use of function from bucket.
+func (p *Parser) indexFunctionUse(bucket *Symbol, function *FunctionSymbol, token *lexer.Token) {
+	if p.Index == nil {
+		return
+	}
+
+	for _, symbol := range bucket.value.(symbolTable) {
+		if symbol.value.(*FunctionSymbol) == function {
+			p.indexUse(symbol, token)
+		}
+	}
+}
+
+// Indexes global symbols of module, which weren't declared or used, so they can be completed.
+func (p *Parser) indexGlobals() {
+	if p.Index == nil {
+		return
+	}
+
+	for identifier, symbol := range p.module.symbols {
+		if symbol.symbolType == ST_FunctionBucket {
+			for _, function := range symbol.value.(symbolTable) {
+				if _, exists := p.Index.declarations[function]; !exists {
+					p.indexDeclaration(function, identifier, nil)
+				}
+			}
+		} else if _, exists := p.Index.declarations[symbol]; !exists {
+			p.indexDeclaration(symbol, identifier, nil)
+		}
+	}
+}
+
+// Starts function, which contains declarations of it's parameters and local variables.
+func (p *Parser) indexEnterFunction(start *data.CodePos) {
+	if p.Index != nil {
+		p.Index.functions = append(p.Index.functions, &data.CodePos{start.File, start.StartLine, start.EndLine, start.StartChar, start.EndChar})
+	}
+}
+
+// Ends function at position.
+func (p *Parser) indexLeaveFunction(end *data.CodePos) {
+	if p.Index == nil || len(p.Index.functions) == 0 {
+		return
+	}
+
+	function := p.Index.functions[len(p.Index.functions)-1]
+	function.EndLine, function.EndChar = end.EndLine, end.EndChar
+
+	p.Index.functions = p.Index.functions[:len(p.Index.functions)-1]
+}
+
+// Indexes parameters of function declaration. Parameter symbols are inserted again for the body, so they're matched to identifiers in the header.
+func (p *Parser) indexParameters(headerStart int, parameters []Parameter) {
+	if p.Index == nil {
+		return
+	}
+
+	indexed := map[string]bool{}
+
+	for i := headerStart; i < p.tokenIndex-1; i++ {
+		token := p.tokens[i]
+		next := p.tokens[i+1].TokenType
+
+		if token.TokenType != lexer.TT_Identifier || indexed[token.Value] || next != lexer.TT_DL_Comma && next != lexer.TT_DL_ParenthesisClose {
+			continue
+		}
+
+		for _, parameter := range parameters {
+			if parameter.Identifier == token.Value {
+				indexed[token.Value] = true
+				p.indexDeclaration(p.getSymbol(token.Value), token.Value, token.Position)
+			}
+		}
+	}
+}
+
+func describeSymbol(symbol *Symbol, identifier string) string {
+	switch symbol.symbolType {
+	case ST_Variable:
+		variable := symbol.value.(*VariableSymbol)
+
+		if variable.isConstant {
+			return "const " + variable.VariableType.String() + " " + identifier
+		}
+		return variable.VariableType.String() + " " + identifier
+
+	case ST_Function:
+		return "fun " + functionSignature(symbol.value.(*FunctionSymbol), identifier).Label
+	}
+
+	return symbol.symbolType.String() + " " + identifier
+}
+
+// Creates signature of function. Receiver of method isn't listed in it's parameters.
+func functionSignature(function *FunctionSymbol, identifier string) *Signature {
+	parameters := function.parameters
+
+	if strings.Contains(identifier, ".") && len(parameters) != 0 && parameters[0].Identifier == "self" {
+		parameters = parameters[1:]
+	}
+
+	signature := &Signature{"", make([]string, len(parameters))}
+
+	for i, parameter := range parameters {
+		signature.Parameters[i] = parameter.DataType.String() + " " + parameter.Identifier
+	}
+
+	signature.Label = identifier + "(" + strings.Join(signature.Parameters, ", ") + ")"
+
+	if function.returnType != nil && function.returnType.Type != data.DT_Unknown {
+		signature.Label += " -> " + function.returnType.String()
+	}
+
+	return signature
+}
@@ -0,0 +1,187 @@
+package parser
+
+import (
+	"slices"
+	"strconv"
+
+	data "github.com/DanielNos/neco/dataStructures"
+	"github.com/DanielNos/neco/lexer"
+)
+
+// JSON schemas describe converted types to the virtual machine, because it doesn't know names of struct fields.
+// Schema of a type is one of:
+//
+//	b, i, f, s                 bool, int, flt, str
+//	eName:0,1,2;               enum with it's constant values
+//	?T, [T, #T, {T             option, list, set and map with str keys of type T
+//	oName(field:T,field:T)     object with fields in order of their numbers
+//	@N;                        N-th object of the schema, used by recursive structs
+func (p *Parser) jsonSchema(dataType *data.DataType, position *data.CodePos) string {
+	objects := []string{}
+	schema, unsupported := p.writeJsonSchema(dataType, &objects)
+
+	if unsupported != nil {
+		if unsupported == dataType {
+			p.newError(position, "Values of type "+dataType.String()+" can't be converted to and from JSON.")
+		} else {
+			p.newError(position, "Values of type "+dataType.String()+" can't be converted to and from JSON, because they contain "+unsupported.String()+".")
+		}
+	}
+
+	return schema
+}
+
+// Returns schema of data type, or the data type which can't be represented in JSON.
+func (p *Parser) writeJsonSchema(dataType *data.DataType, objects *[]string) (string, *data.DataType) {
+	switch dataType.Type {
+	case data.DT_Bool:
+		return "b", nil
+
+	case data.DT_Int:
+		return "i", nil
+
+	case data.DT_Float:
+		return "f", nil
+
+	case data.DT_String:
+		return "s", nil
+
+	// Errors were already reported
+	case data.DT_Unknown:
+		return "", nil
+
+	// Enums are represented by their values, tagged unions can't be represented
+	case data.DT_Enum:
+		symbol := p.getGlobalSymbol(dataType.SubType.(string))
+		if symbol == nil {
+			return "", nil
+		}
+
+		constants, isEnum := symbol.value.(map[string]int64)
+		if !isEnum {
+			return "", dataType
+		}
+
+		values := []int64{}
+		for _, value := range constants {
+			values = append(values, value)
+		}
+		slices.Sort(values)
+
+		schema := "e" + dataType.SubType.(string) + ":"
+		for i, value := range values {
+			if i != 0 {
+				schema += ","
+			}
+			schema += strconv.FormatInt(value, 10)
+		}
+
+		return schema + ";", nil
+
+	case data.DT_Option, data.DT_List, data.DT_Set:
+		element, unsupported := p.writeJsonSchema(dataType.SubType.(*data.DataType), objects)
+
+		switch dataType.Type {
+		case data.DT_Option:
+			return "?" + element, unsupported
+		case data.DT_List:
+			return "[" + element, unsupported
+		default:
+			return "#" + element, unsupported
+		}
+
+	// Only maps with string keys can be JSON objects
+	case data.DT_Map:
+		mapType := dataType.SubType.(*data.MapType)
+		if mapType.Key.Type != data.DT_String {
+			return "", dataType
+		}
+
+		value, unsupported := p.writeJsonSchema(mapType.Value, objects)
+		return "{" + value, unsupported
+
+	case data.DT_Object:
+		// Recursive struct references already described object
+		key := dataType.String()
+		if index := slices.Index(*objects, key); index != -1 {
+			return "@" + strconv.Itoa(index) + ";", nil
+		}
+		*objects = append(*objects, key)
+
+		structSymbol := p.getGlobalSymbol(dataType.StructName())
+		if structSymbol == nil {
+			return "", nil
+		}
+
+		// Order fields by their numbers
+		properties := structSymbol.value.(map[string]PropertySymbol)
+		names := make([]string, len(properties))
+
+		for name, property := range properties {
+			names[property.number] = name
+		}
+
+		typeArguments := p.objectTypeArguments(dataType)
+		schema := "o" + dataType.StructName() + "("
+
+		for i, name := range names {
+			field, unsupported := p.writeJsonSchema(properties[name].dataType.Substitute(typeArguments), objects)
+			if unsupported != nil {
+				return "", unsupported
+			}
+
+			if i != 0 {
+				schema += ","
+			}
+			schema += name + ":" + field
+		}
+
+		return schema + ")", nil
+	}
+
+	// Any, functions and type parameters
+	return "", dataType
+}
+
+// Creates string literal with JSON schema of data type, which is passed to JSON built-in functions as the last argument.
+func (p *Parser) jsonSchemaArgument(dataType *data.DataType, position *data.CodePos) *Node {
+	schema := p.jsonSchema(dataType, position)
+	p.StringConstants[schema] = -1
+
+	return &Node{position, NT_Literal, &LiteralNode{data.DT_String, schema}}
+}
+
+// Parses call of fromJson, which requires type argument of decoded value: fromJson<T>(text).
+func (p *Parser) parseFromJson(functionBucketSymbol *Symbol) *Node {
+	identifier := p.consume()
+
+	if p.peek().TokenType != lexer.TT_OP_Lower {
+		p.newError(identifier.Position, "Function fromJson requires type of decoded value, for example fromJson<Person>(text).")
+		return p.parseFunctionCall(functionBucketSymbol, identifier, nil)
+	}
+
+	p.consume() // <
+	typePosition := p.peek().Position
+	decodedType := p.parseType()
+	typePosition = typePosition.Combine(p.peekPrevious().Position)
+
+	if p.peek().TokenType != lexer.TT_OP_Greater {
+		p.newError(p.peek().Position, "Expected closing > after type argument of fromJson, found \""+p.peek().String()+"\" instead.")
+	} else {
+		p.consume() // >
+	}
+
+	// Decoded value is an option, because JSON can be null
+	call := p.parseFunctionCall(functionBucketSymbol, identifier, nil)
+	functionCall := call.Value.(*FunctionCallNode)
+
+	if decodedType.Type != data.DT_Option {
+		decodedType = &data.DataType{data.DT_Option, decodedType}
+	}
+	functionCall.ReturnType = decodedType
+
+	functionCall.Arguments = append(functionCall.Arguments, p.jsonSchemaArgument(decodedType, typePosition))
+	functionCall.ArgumentTypes = append(functionCall.ArgumentTypes, &data.DataType{data.DT_String, nil})
+
+	return call
+}
@@ -37,20 +37,88 @@ func (p *Parser) parseStructLiteral(properties map[string]PropertySymbol) *Node
 	p.consumeEOCs()
 
 	var propertyValues []*Node
+	typeArguments := map[string]*data.DataType{}
 
 	// Collect named properties
 	if p.peek().TokenType == lexer.TT_Identifier && p.peekNext().TokenType == lexer.TT_DL_Colon {
-		propertyValues = p.parseKeyedProperties(properties, identifier.Value)
+		propertyValues = p.parseKeyedProperties(properties, identifier.Value, typeArguments)
 	} else {
-		propertyValues = p.parseProperties(properties, identifier)
+		propertyValues = p.parseProperties(properties, identifier, typeArguments)
 	}
 
 	p.consume() // }
+	position := identifier.Position.Combine(p.peekPrevious().Position)
 
-	return &Node{identifier.Position.Combine(p.peekPrevious().Position), NT_Object, &ObjectNode{identifier.Value, propertyValues}}
+	return &Node{position, NT_Object, &ObjectNode{identifier.Value, propertyValues, p.inferStructType(identifier.Value, typeArguments, position)}}
 }
 
-func (p *Parser) parseKeyedProperties(properties map[string]PropertySymbol, structName string) []*Node {
+func (p *Parser) parseVariant(enumToken *lexer.Token, variants map[string]*VariantSymbol) *Node {
+	identifier := p.consume()
+	payload := []*Node{}
+
+	// Look up variant
+	variant, exists := variants[identifier.Value]
+
+	if !exists {
+		p.newError(identifier.Position, "Enum "+enumToken.Value+" doesn't have a variant "+identifier.Value+".")
+		variant = &VariantSymbol{0, []Parameter{}}
+	}
+
+	p.StringConstants[identifier.Value] = -1
+	p.IntConstants[variant.tag] = -1
+
+	// Collect payload
+	if p.peek().TokenType == lexer.TT_DL_ParenthesisOpen {
+		p.consume() // (
+		arguments, argumentTypes, _ := p.parseArguments()
+		p.consume() // )
+
+		if len(arguments) != len(variant.payload) {
+			p.newError(identifier.Position.Combine(p.peekPrevious().Position), fmt.Sprintf("Variant %s.%s has %d payload values, but %d were given.", enumToken.Value, identifier.Value, len(variant.payload), len(arguments)))
+		} else {
+			// Check payload types
+			for i, argumentType := range argumentTypes {
+				if !variant.payload[i].DataType.CanBeAssigned(argumentType) {
+					p.newError(arguments[i].Position, "Payload value "+variant.payload[i].Identifier+" of variant "+enumToken.Value+"."+identifier.Value+" has type "+variant.payload[i].DataType.String()+", but is assigned expression of type "+argumentType.String()+".")
+				}
+			}
+		}
+
+		payload = arguments
+	} else if len(variant.payload) != 0 {
+		p.newError(identifier.Position, "Variant "+enumToken.Value+"."+identifier.Value+" has a payload. Payload values have to be provided in parentheses.")
+	}
+
+	position := enumToken.Position.Combine(p.peekPrevious().Position)
+
+	return &Node{position, NT_Variant, &VariantNode{enumToken.Value, identifier.Value, variant.tag, payload}}
+}
+
+// Creates type of struct literal. Type arguments of generic structs are taken from inferred type arguments.
+func (p *Parser) inferStructType(structName string, typeArguments map[string]*data.DataType, position *data.CodePos) *data.DataType {
+	typeParameters := p.structTypeParameters[structName]
+
+	if len(typeParameters) == 0 {
+		return &data.DataType{data.DT_Object, structName}
+	}
+
+	objectType := &data.ObjectType{structName, make([]*data.DataType, len(typeParameters))}
+
+	for i, typeParameter := range typeParameters {
+		typeArgument, inferred := typeArguments[typeParameter]
+
+		if !inferred {
+			p.newError(position, "Can't infer type argument of type parameter "+typeParameter+" of struct "+structName+".")
+			typeArgument = &data.DataType{data.DT_Unknown, nil}
+		}
+
+		objectType.TypeArguments[i] = typeArgument
+	}
+
+	return &data.DataType{data.DT_Object, objectType}
+}
+
+func (p *Parser) parseKeyedProperties(properties map[string]PropertySymbol, structName string, typeArguments map[string]*data.DataType) []*Node {
 	propertyValues := map[string]*Node{}
 
 	for p.peek().TokenType != lexer.TT_DL_BraceClose {
@@ -90,7 +158,7 @@ func (p *Parser) parseKeyedProperties(properties map[string]PropertySymbol, stru
 			// Check if expression has correct type
 			if exists {
 				expressionType := GetExpressionType(expression)
-				if !property.dataType.CanBeAssigned(expressionType) {
+				if !property.dataType.InferTypeArguments(expressionType, typeArguments) {
 					p.newError(expression.Position, "Field "+propertyName.Value+" of struct "+structName+" has type "+property.dataType.String()+", but is assigned expression of type "+expressionType.String()+".")
 				}
 			}
@@ -124,7 +192,7 @@ func (p *Parser) parseKeyedProperties(properties map[string]PropertySymbol, stru
 	return orderedValues
 }
 
-func (p *Parser) parseProperties(properties map[string]PropertySymbol, structName *lexer.Token) []*Node {
+func (p *Parser) parseProperties(properties map[string]PropertySymbol, structName *lexer.Token, typeArguments map[string]*data.DataType) []*Node {
 	// Make properties linear
 	orderedProperties := make([]PropertySymbol, len(properties))
 	orderedPropertyNames := make([]string, len(properties))
@@ -150,7 +218,7 @@ func (p *Parser) parseProperties(properties map[string]PropertySymbol, structNam
 			expressionType := GetExpressionType(expression)
 
 			// Check type
-			if !orderedProperties[propertyIndex].dataType.CanBeAssigned(expressionType) {
+			if !orderedProperties[propertyIndex].dataType.InferTypeArguments(expressionType, typeArguments) {
 				p.newError(expression.Position, "Property "+orderedPropertyNames[propertyIndex]+" of struct "+structName.Value+" has type "+orderedProperties[propertyIndex].dataType.String()+", but was assigned expression of type "+expressionType.String()+".")
 			}
 
@@ -175,6 +243,106 @@ func (p *Parser) parseProperties(properties map[string]PropertySymbol, structNam
 	return propertyValues
 }
 
+// Checks if braces starting at current token contain key-value pairs.
+func (p *Parser) isMapLiteral() bool {
+	depth := 0
+
+	for i := p.tokenIndex; i < len(p.tokens); i++ {
+		switch p.tokens[i].TokenType {
+		case lexer.TT_DL_ParenthesisOpen, lexer.TT_DL_BracketOpen, lexer.TT_DL_BraceOpen:
+			depth++
+		case lexer.TT_DL_ParenthesisClose, lexer.TT_DL_BracketClose, lexer.TT_DL_BraceClose:
+			depth--
+
+			if depth == 0 {
+				return false
+			}
+		case lexer.TT_DL_Colon:
+			if depth == 1 {
+				return true
+			}
+		case lexer.TT_DL_Comma:
+			if depth == 1 {
+				return false
+			}
+		}
+	}
+
+	return false
+}
+
+func (p *Parser) parseMapLiteral() *Node {
+	startPosition := p.consume().Position // {
+	p.consumeEOCs()
+
+	// Collect keys and values
+	keys := []*Node{}
+	values := []*Node{}
+
+	keyTypes := map[string]*dataTypeCount{}
+	valueTypes := map[string]*dataTypeCount{}
+
+	keyType := &data.DataType{data.DT_Unknown, nil}
+	valueType := &data.DataType{data.DT_Unknown, nil}
+
+	for p.peek().TokenType != lexer.TT_DL_BraceClose {
+		// Collect key, it can't contain ":"
+		key := p.parseExpression(operatorPrecedence(lexer.TT_OP_UnpackOrDefault))
+		p.collectConstant(key)
+		keyType = p.deriveType(key)
+
+		if p.peek().TokenType != lexer.TT_DL_Colon {
+			p.newError(GetExpressionPosition(key), "Expected \":\" and a value after map key.")
+		} else {
+			p.consume() // :
+
+			// Collect value
+			value := p.parseExpressionRoot()
+			valueType = GetExpressionType(value)
+
+			keys = append(keys, key)
+			values = append(values, value)
+
+			countType(keyTypes, keyType)
+			countType(valueTypes, valueType)
+		}
+
+		// Consume comma
+		if p.peek().TokenType == lexer.TT_DL_Comma {
+			p.consume()
+		}
+
+		p.consumeEOCs()
+	}
+
+	// Check if all keys and values have the same type and set them to the most common type
+	if len(keyTypes) > 1 {
+		keyType = p.checkElementTypes(keyTypes, keys, "Map keys")
+	}
+
+	if len(valueTypes) > 1 {
+		valueType = p.checkElementTypes(valueTypes, values, "Map values")
+	}
+
+	// Key type has to be hashable
+	if !canBeMapKey(keyType) {
+		p.newError(GetExpressionPosition(keys[0]), "Map key type can't be "+keyType.String()+". Only bool, int, flt, str and enums can be used as keys.")
+	}
+
+	return &Node{startPosition.Combine(p.consume().Position), NT_Map, &MapNode{keys, values, &data.DataType{data.DT_Map, &data.MapType{keyType, valueType}}}}
+}
+
+func countType(typeCounts map[string]*dataTypeCount, dataType *data.DataType) {
+	signature := dataType.Signature()
+
+	typeAndCount, exists := typeCounts[signature]
+	if !exists {
+		typeCounts[signature] = &dataTypeCount{dataType, 1}
+	} else {
+		typeAndCount.Count++
+	}
+}
+
 func combineLiteralNodes(left, right *Node, parentNodeType NodeType) *Node {
 	leftLiteral := left.Value.(*LiteralNode)
 	rightLiteral := right.Value.(*LiteralNode)
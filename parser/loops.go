@@ -102,17 +102,23 @@ func (p *Parser) parseFor() *Node {
 	// Parse body
 	body := p.parseScope(false, true).(*Node)
 
-	// Append step to body
+	// Step is stored separately, so continue can jump to it
+	var step []*Node = nil
 	if stepStatement != nil {
-		p.appendScope(stepStatement)
+		step = []*Node{stepStatement}
 	}
 
 	p.leaveScope()
 
-	return &Node{forPosition.Combine(p.peekPrevious().Position), NT_ForLoop, &ForLoopNode{initStatement, body}}
+	return &Node{forPosition.Combine(p.peekPrevious().Position), NT_ForLoop, &ForLoopNode{initStatement, body, step}}
 }
 
 func (p *Parser) parseForEach() *Node {
+	// Ranges are iterated using a counter
+	if p.isRangeForEach() {
+		return p.parseForEachRange()
+	}
+
 	startPosition := p.consume().Position
 
 	// Consume (
@@ -136,7 +142,7 @@ func (p *Parser) parseForEach() *Node {
 	p.appendScope(sizeDeclaration)
 
 	// Set list size variable to list size
-	functionCallNode := &FunctionCallNode{-1, "size", nil, nil, &data.DataType{data.DT_Int, nil}}
+	functionCallNode := &FunctionCallNode{-1, "size", nil, nil, &data.DataType{data.DT_Int, nil}, nil, nil}
 	sizeFunctionCall := &Node{iteratorPosition, NT_FunctionCall, functionCallNode}
 	p.appendScope(&Node{iteratorPosition, NT_Assign, &AssignNode{[]*Node{sizeIdentifierVariable}, sizeFunctionCall}})
 
@@ -165,7 +171,26 @@ func (p *Parser) parseForEach() *Node {
 	p.appendScope(iteratorDeclaration)
 
 	// Insert it into symbol table
-	p.insertSymbol(iteratorIdentifier, &Symbol{ST_Variable, &VariableSymbol{iteratorType, true, false}})
+	p.insertIndexedSymbol(iteratorIdentifier, &Symbol{ST_Variable, &VariableSymbol{iteratorType, true, false}}, iteratorVariable.Position)
+
+	// Collect value variable of map entries
+	var valueVariable *Node = nil
+	var valueTypePosition *data.CodePos
+
+	if p.peek().TokenType == lexer.TT_DL_Comma {
+		p.consume() // ,
+
+		valueTypePosition = p.peek().Position
+		valueType := p.parseType()
+		valueTypePosition = valueTypePosition.Combine(p.peekPrevious().Position)
+
+		valueIdentifier := p.consume().Value
+		valueVariable = &Node{p.peekPrevious().Position, NT_Variable, &VariableNode{valueIdentifier, valueType}}
+
+		// Declare it and insert it into symbol table
+		p.appendScope(&Node{iteratorPosition, NT_VariableDeclaration, &VariableDeclareNode{valueType, false, []string{valueIdentifier}}})
+		p.insertIndexedSymbol(valueIdentifier, &Symbol{ST_Variable, &VariableSymbol{valueType, true, false}}, valueVariable.Position)
+	}
 
 	// Consume in
 	p.consume()
@@ -173,6 +198,34 @@ func (p *Parser) parseForEach() *Node {
 	// Collect enumerated expression
 	expression := p.parseExpressionRoot()
 	elementType := GetExpressionType(expression)
+	iteratedExpression := expression
+
+	var mapType *data.MapType = nil
+
+	// Map is iterated over it's keys
+	if elementType.Type == data.DT_Map {
+		mapType = elementType.SubType.(*data.MapType)
+		keysType := &data.DataType{data.DT_List, mapType.Key}
+
+		// Generate variable with list of map keys in front of the loop
+		keysIdentifier := fmt.Sprintf("@MAP_KEYS_%d", p.tokenIndex)
+		keysVariable := &Node{iteratorPosition, NT_Variable, &VariableNode{keysIdentifier, keysType}}
+		keysDeclaration := &Node{iteratorPosition, NT_VariableDeclaration, &VariableDeclareNode{keysType, false, []string{keysIdentifier}}}
+
+		keysFunctionCall := &Node{iteratorPosition, NT_FunctionCall, &FunctionCallNode{-1, "keys", []*Node{expression}, []*data.DataType{elementType}, keysType, nil, nil}}
+		keysAssignment := &Node{iteratorPosition, NT_Assign, &AssignNode{[]*Node{keysVariable}, keysFunctionCall}}
+
+		outerScope := p.scopeNodeStack.Top.Previous.Value.(*ScopeNode)
+		outerScope.Statements = append(outerScope.Statements, keysDeclaration, keysAssignment)
+
+		iteratedExpression = keysVariable
+		elementType = keysType
+	}
+
+	// Value variable can be used only with maps
+	if valueVariable != nil && mapType == nil && elementType.Type != data.DT_Unknown {
+		p.newError(valueTypePosition, "Only maps can be iterated over with a key and a value variable.")
+	}
 
 	// Set element type to list subtype (if type was derived)
 	if elementType.Type != data.DT_Unknown {
@@ -194,9 +247,22 @@ func (p *Parser) parseForEach() *Node {
 
 	// Assign to iterated_expression[iterator_index] to iterator
 	iteratorIndexVariable := &Node{iteratorPosition, NT_Variable, &VariableNode{indexIdentifier, &data.DataType{data.DT_Int, nil}}}
-	indexExpression := &Node{iteratorPosition, NT_ListValue, &TypedBinaryNode{expression, iteratorIndexVariable, elementType}}
+	indexExpression := &Node{iteratorPosition, NT_ListValue, &TypedBinaryNode{iteratedExpression, iteratorIndexVariable, elementType}}
 	p.appendScope(&Node{iteratorPosition, NT_Assign, &AssignNode{[]*Node{iteratorVariable}, indexExpression}})
 
+	// Assign iterated_expression[iterator] to value
+	if valueVariable != nil && mapType != nil {
+		valueType := valueVariable.Value.(*VariableNode).DataType
+
+		if !valueType.CanBeAssigned(mapType.Value) {
+			p.newErrorNoMessage()
+			logger.Error2CodePos(valueTypePosition, expression.Position, "Can't assign expression of type "+mapType.Value.String()+" to variable of type "+valueType.String()+".")
+		}
+
+		mapValue := &Node{iteratorPosition, NT_ListValue, &TypedBinaryNode{expression, iteratorVariable, mapType.Value}}
+		p.appendScope(&Node{iteratorPosition, NT_Assign, &AssignNode{[]*Node{valueVariable}, mapValue}})
+	}
+
 	// Add enumerated expression to previous length() function call
 	functionCallNode.Arguments = []*Node{expression}
 	functionCallNode.ArgumentTypes = []*data.DataType{elementType}
@@ -204,6 +270,10 @@ func (p *Parser) parseForEach() *Node {
 	// Consume )
 	p.consume()
 
+	if p.peek().TokenType == lexer.TT_EndOfCommand {
+		p.consume()
+	}
+
 	// Collect body
 	body := p.parseScope(false, true).(*Node)
 
@@ -213,12 +283,140 @@ func (p *Parser) parseForEach() *Node {
 
 	addOne := &Node{iteratorPosition, NT_Add, &TypedBinaryNode{iteratorIndexVariable, oneLiteral, &data.DataType{data.DT_Int, nil}}}
 
-	// Insert iterator_index = iterator_index + 1
-	p.appendScope(&Node{iteratorPosition, NT_Assign, &AssignNode{[]*Node{iteratorIndexVariable}, addOne}})
+	// Use iterator_index = iterator_index + 1 as step
+	step := []*Node{{iteratorPosition, NT_Assign, &AssignNode{[]*Node{iteratorIndexVariable}, addOne}}}
+
+	// Leave scope
+	p.leaveScope()
+
+	return &Node{startPosition.Combine(p.peekPrevious().Position), NT_ForLoop, &ForLoopNode{nil, body, step}}
+}
+
+func (p *Parser) isRangeForEach() bool {
+	depth := 0
+
+	for i := p.tokenIndex; i < len(p.tokens); i++ {
+		switch p.tokens[i].TokenType {
+		case lexer.TT_DL_ParenthesisOpen, lexer.TT_DL_BracketOpen, lexer.TT_DL_BraceOpen:
+			depth++
+		case lexer.TT_DL_ParenthesisClose, lexer.TT_DL_BracketClose, lexer.TT_DL_BraceClose:
+			depth--
+
+			if depth == 0 {
+				return false
+			}
+		case lexer.TT_OP_Range, lexer.TT_OP_RangeInclusive:
+			if depth == 1 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (p *Parser) parseForEachRange() *Node {
+	startPosition := p.consume().Position
+
+	// Consume (
+	p.consume()
+	iteratorPosition := p.peek().Position
+	intType := &data.DataType{data.DT_Int, nil}
+
+	// Collect iterator variable
+	typePosition := p.peek().Position
+	iteratorType := p.parseType()
+	typePosition = typePosition.Combine(p.peekPrevious().Position)
+
+	iteratorIdentifier := p.consume().Value
+	iteratorVariable := &Node{p.peekPrevious().Position, NT_Variable, &VariableNode{iteratorIdentifier, iteratorType}}
+
+	// Consume in
+	p.consume()
+
+	// Collect range
+	expression := p.parseExpression(MINIMAL_PRECEDENCE)
+	p.deriveType(expression)
+
+	if expression.NodeType != NT_Range {
+		p.newError(GetExpressionPosition(expression), "Expected a range.")
+		expression = &Node{expression.Position, NT_Range, &RangeNode{expression, expression, nil, false}}
+	}
+	rangeNode := expression.Value.(*RangeNode)
+
+	// Check if integer can be assigned to iterator
+	if !iteratorType.CanBeAssigned(intType) {
+		p.newErrorNoMessage()
+		logger.Error2CodePos(typePosition, expression.Position, "Can't assign expression of type "+intType.String()+" to variable of type "+iteratorType.String()+".")
+	}
+
+	// Range is evaluated only once, store it's start, end and step in variables
+	counterIdentifier := fmt.Sprintf("@LOOP_ITERATOR_%d", p.tokenIndex)
+	endIdentifier := fmt.Sprintf("@RANGE_END_%d", p.tokenIndex)
+	stepIdentifier := fmt.Sprintf("@RANGE_STEP_%d", p.tokenIndex)
+
+	// Step defaults to one
+	step := rangeNode.Step
+	if step == nil {
+		step = &Node{iteratorPosition, NT_Literal, &LiteralNode{data.DT_Int, int64(1)}}
+		p.IntConstants[1] = -1 // Store one in constants
+	}
+
+	identifiers := []string{counterIdentifier, endIdentifier, stepIdentifier}
+	values := []*Node{rangeNode.Start, rangeNode.End, step}
+
+	for i, identifier := range identifiers {
+		variable := &Node{iteratorPosition, NT_Variable, &VariableNode{identifier, intType}}
+		p.appendScope(&Node{iteratorPosition, NT_VariableDeclaration, &VariableDeclareNode{intType, false, []string{identifier}}})
+		p.appendScope(&Node{iteratorPosition, NT_Assign, &AssignNode{[]*Node{variable}, values[i]}})
+	}
+
+	counterVariable := &Node{iteratorPosition, NT_Variable, &VariableNode{counterIdentifier, intType}}
+	endVariable := &Node{iteratorPosition, NT_Variable, &VariableNode{endIdentifier, intType}}
+	stepVariable := &Node{iteratorPosition, NT_Variable, &VariableNode{stepIdentifier, intType}}
+
+	// Enter loop scope
+	p.enterScope()
+
+	// Generate: if !(counter in counter..end step step) { break }
+	counterRange := &Node{iteratorPosition, NT_Range, &RangeNode{counterVariable, endVariable, stepVariable, rangeNode.Inclusive}}
+	inRange := &Node{iteratorPosition, NT_In, &TypedBinaryNode{counterVariable, counterRange, &data.DataType{data.DT_Bool, nil}}}
+	condition := &Node{iteratorPosition, NT_Not, &TypedBinaryNode{nil, inRange, &data.DataType{data.DT_Bool, nil}}}
+	breakNode := &Node{iteratorPosition, NT_Break, nil}
+	ifBody := &Node{iteratorPosition, NT_Scope, &ScopeNode{-1, []*Node{breakNode}}}
+	p.appendScope(&Node{iteratorPosition, NT_If, &IfNode{[]*IfStatement{{condition, ifBody}}, nil}})
+
+	// Declare iterator and assign counter to it
+	p.appendScope(&Node{iteratorPosition, NT_VariableDeclaration, &VariableDeclareNode{iteratorType, false, []string{iteratorIdentifier}}})
+	p.insertIndexedSymbol(iteratorIdentifier, &Symbol{ST_Variable, &VariableSymbol{iteratorType, true, false}}, iteratorVariable.Position)
+	p.appendScope(&Node{iteratorPosition, NT_Assign, &AssignNode{[]*Node{iteratorVariable}, counterVariable}})
+
+	// Consume )
+	p.consume()
+
+	if p.peek().TokenType == lexer.TT_EndOfCommand {
+		p.consume()
+	}
+
+	// Collect body
+	body := p.parseScope(false, true).(*Node)
+
+	// Generate: if !(counter in counter..(end - step) step step) { break }, so counter doesn't overflow after the last iteration
+	lastEnd := &Node{iteratorPosition, NT_Subtract, &TypedBinaryNode{endVariable, stepVariable, intType}}
+	nextRange := &Node{iteratorPosition, NT_Range, &RangeNode{counterVariable, lastEnd, stepVariable, rangeNode.Inclusive}}
+	hasNext := &Node{iteratorPosition, NT_In, &TypedBinaryNode{counterVariable, nextRange, &data.DataType{data.DT_Bool, nil}}}
+	lastCondition := &Node{iteratorPosition, NT_Not, &TypedBinaryNode{nil, hasNext, &data.DataType{data.DT_Bool, nil}}}
+	lastBody := &Node{iteratorPosition, NT_Scope, &ScopeNode{-1, []*Node{{iteratorPosition, NT_Break, nil}}}}
+
+	// Use counter = counter + step as step
+	addStep := &Node{iteratorPosition, NT_Add, &TypedBinaryNode{counterVariable, stepVariable, intType}}
+	loopStep := []*Node{
+		{iteratorPosition, NT_If, &IfNode{[]*IfStatement{{lastCondition, lastBody}}, nil}},
+		{iteratorPosition, NT_Assign, &AssignNode{[]*Node{counterVariable}, addStep}},
+	}
 
 	// Leave scope
 	p.leaveScope()
 
-	// Consume
-	return &Node{startPosition.Combine(p.peekPrevious().Position), NT_Loop, body}
+	return &Node{startPosition.Combine(p.peekPrevious().Position), NT_ForLoop, &ForLoopNode{nil, body, loopStep}}
 }
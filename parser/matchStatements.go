@@ -1,9 +1,12 @@
 package parser
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	data "github.com/DanielNos/neco/dataStructures"
 	"github.com/DanielNos/neco/lexer"
-	"github.com/DanielNos/neco/logger"
 )
 
 func (p *Parser) parseMatch(isExpression bool) *Node {
@@ -12,6 +15,12 @@ func (p *Parser) parseMatch(isExpression bool) *Node {
 	expression := p.parseExpressionRoot()
 	cases := []*Node{}
 
+	// Matched tagged union is stored in a variable, so it's payload can be bound in cases
+	variants := p.taggedUnionVariants(GetExpressionType(expression))
+	if variants != nil {
+		expression = p.storeMatchedExpression(expression)
+	}
+
 	if p.peek().TokenType == lexer.TT_EndOfCommand {
 		p.consume()
 	}
@@ -38,7 +47,7 @@ func (p *Parser) parseMatch(isExpression bool) *Node {
 				defaultCase = p.parseStatement(false)
 			}
 
-			defaultCase = &Node{casePosition, NT_Case, &CaseNode{[]*Node{}, defaultCase}}
+			defaultCase = &Node{casePosition, NT_Case, &CaseNode{[]*Node{}, defaultCase, nil}}
 			caseCount++
 
 			// Parse case
@@ -51,6 +60,12 @@ func (p *Parser) parseMatch(isExpression bool) *Node {
 			caseCount++
 			casePosition := p.peek().Position
 
+			// Tagged union cases are variant patterns
+			if variants != nil {
+				cases = append(cases, p.parseVariantCase(isExpression, expression, variants, &caseCount))
+				continue
+			}
+
 			// Collect case expressions
 			expressions := []*Node{}
 			expressions = append(expressions, p.parseExpressionRoot())
@@ -71,13 +86,144 @@ func (p *Parser) parseMatch(isExpression bool) *Node {
 				caseContent = p.parseStatement(false)
 			}
 
-			cases = append(cases, &Node{casePosition, NT_Case, &CaseNode{expressions, caseContent}})
+			cases = append(cases, &Node{casePosition, NT_Case, &CaseNode{expressions, caseContent, nil}})
 		}
 	}
 
 	p.consume() // }
 
-	return &Node{startPosition, NT_Match, &MatchNode{expression, cases, caseCount, defaultCase, nil}}
+	matchNode := &Node{startPosition, NT_Match, &MatchNode{expression, cases, caseCount, defaultCase, nil}}
+
+	// Match statements on tagged unions have to be exhaustive
+	if variants != nil && !isExpression {
+		p.checkCaseValueCoverage(matchNode)
+	}
+
+	return matchNode
+}
+
+// Returns variants of enum if data type is a tagged union, otherwise nil.
+func (p *Parser) taggedUnionVariants(dataType *data.DataType) map[string]*VariantSymbol {
+	if dataType.Type != data.DT_Enum {
+		return nil
+	}
+
+	symbol := p.getGlobalSymbol(dataType.SubType.(string))
+	if symbol == nil {
+		return nil
+	}
+
+	variants, _ := symbol.value.(map[string]*VariantSymbol)
+	return variants
+}
+
+func (p *Parser) storeMatchedExpression(expression *Node) *Node {
+	expressionType := GetExpressionType(expression)
+
+	// Generate matched value variable declaration
+	identifier := fmt.Sprintf("@MATCHED_%d", p.tokenIndex)
+	variable := &Node{expression.Position, NT_Variable, &VariableNode{identifier, expressionType}}
+	p.appendScope(&Node{expression.Position, NT_VariableDeclaration, &VariableDeclareNode{expressionType, false, []string{identifier}}})
+
+	// Assign matched expression to it
+	p.appendScope(&Node{expression.Position, NT_Assign, &AssignNode{[]*Node{variable}, expression}})
+
+	return variable
+}
+
+func (p *Parser) parseVariantCase(isExpression bool, matchedVariable *Node, variants map[string]*VariantSymbol, caseCount *int) *Node {
+	casePosition := p.peek().Position
+	enumName := GetExpressionType(matchedVariable).SubType.(string)
+
+	// Collect variant patterns
+	pattern, bindings := p.parseVariantPattern(enumName, variants)
+	expressions := []*Node{pattern}
+
+	for p.peek().TokenType == lexer.TT_DL_Comma {
+		p.consume()
+		pattern, patternBindings := p.parseVariantPattern(enumName, variants)
+		expressions = append(expressions, pattern)
+		*caseCount++
+
+		if len(patternBindings) != 0 || len(bindings) != 0 {
+			p.newError(pattern.Position, "Payload can't be bound in a case with multiple variants.")
+		}
+	}
+
+	p.consume() // =>
+
+	// Declare bindings in case scope
+	p.enterScope()
+
+	if len(expressions) == 1 {
+		variant := variants[pattern.Value.(*VariantNode).Identifier]
+
+		for i, binding := range bindings {
+			// Skip ignored payload values and payloads of unknown variants
+			if binding.Value == "_" || variant == nil || i >= len(variant.payload) {
+				continue
+			}
+
+			dataType := variant.payload[i].DataType
+			variable := &Node{binding.Position, NT_Variable, &VariableNode{binding.Value, dataType}}
+			payload := &Node{binding.Position, NT_VariantPayload, &VariantPayloadNode{matchedVariable, i, dataType}}
+
+			p.appendScope(&Node{binding.Position, NT_VariableDeclaration, &VariableDeclareNode{dataType, false, []string{binding.Value}}})
+			p.appendScope(&Node{binding.Position, NT_Assign, &AssignNode{[]*Node{variable}, payload}})
+			p.insertIndexedSymbol(binding.Value, &Symbol{ST_Variable, &VariableSymbol{dataType, true, false}}, binding.Position)
+		}
+	}
+
+	var caseContent *Node
+
+	if isExpression {
+		caseContent = p.parseExpressionRoot()
+	} else {
+		caseContent = p.parseStatement(false)
+	}
+
+	return &Node{casePosition, NT_Case, &CaseNode{expressions, caseContent, p.leaveScope().Statements}}
+}
+
+func (p *Parser) parseVariantPattern(enumName string, variants map[string]*VariantSymbol) (*Node, []*lexer.Token) {
+	identifier := p.consume()
+	bindings := []*lexer.Token{}
+
+	// Look up variant
+	variant, exists := variants[identifier.Value]
+
+	if !exists {
+		p.newError(identifier.Position, "Enum "+enumName+" doesn't have a variant "+identifier.Value+".")
+		variant = &VariantSymbol{-1, []Parameter{}}
+	}
+
+	p.IntConstants[variant.tag] = -1
+
+	// Collect bindings
+	if p.peek().TokenType == lexer.TT_DL_ParenthesisOpen {
+		p.consume() // (
+
+		for p.peek().TokenType != lexer.TT_DL_ParenthesisClose {
+			if p.peek().TokenType != lexer.TT_Identifier {
+				p.newError(p.peek().Position, "Expected payload binding identifier, found \""+p.peek().String()+"\" instead.")
+			}
+			bindings = append(bindings, p.consume())
+
+			if p.peek().TokenType == lexer.TT_DL_Comma {
+				p.consume()
+			}
+		}
+
+		p.consume() // )
+
+		if exists && len(bindings) != len(variant.payload) {
+			p.newError(identifier.Position.Combine(p.peekPrevious().Position), fmt.Sprintf("Variant %s.%s has %d payload values, but %d bindings were given.", enumName, identifier.Value, len(variant.payload), len(bindings)))
+		}
+	}
+
+	position := identifier.Position.Combine(p.peekPrevious().Position)
+
+	return &Node{position, NT_Variant, &VariantNode{enumName, identifier.Value, variant.tag, nil}}, bindings
 }
 
 func (p *Parser) parseMatchExpression() *Node {
@@ -128,7 +274,27 @@ func (p *Parser) checkCaseValueCoverage(matchNode *Node) {
 	match := matchNode.Value.(*MatchNode)
 	matchedExpressionType := GetExpressionType(match.Expression)
 
-	if match.Default != nil && matchedExpressionType.Type != data.DT_Bool {
+	if match.Default != nil && matchedExpressionType.Type != data.DT_Bool && matchedExpressionType.Type != data.DT_Enum {
+		return
+	}
+
+	// Enum
+	if matchedExpressionType.Type == data.DT_Enum {
+		missing := p.missingEnumCases(match, matchedExpressionType.SubType.(string))
+
+		// All values aren't covered
+		if len(missing) != 0 && match.Default == nil {
+			p.newError(matchNode.Position, "Not all possible matched values are covered. Add cases for all possible values or a default case. Missing cases: "+strings.Join(missing, ", ")+".")
+			// All values are covered, but default case exists
+		} else if len(missing) == 0 && match.Default != nil {
+			p.newWarning(W_UnnecessaryDefault, match.Default.Position, "Unnecessary default case. All possible expression types are covered.")
+
+			// Remove redundant default case
+			if p.optimize {
+				match.Default = nil
+			}
+		}
+
 		return
 	}
 
@@ -141,7 +307,7 @@ func (p *Parser) checkCaseValueCoverage(matchNode *Node) {
 			p.newError(matchNode.Position, "Not all possible matched values are covered. Add cases for all possible values or a default case.")
 			// All values are covered, but default case exists
 		} else if isCovered && match.Default != nil {
-			logger.WarningCodePos(match.Default.Position, "Unnecessary default case. All possible expression types are covered.")
+			p.newWarning(W_UnnecessaryDefault, match.Default.Position, "Unnecessary default case. All possible expression types are covered.")
 
 			// Remove redundant default case
 			if p.optimize {
@@ -192,3 +358,51 @@ func checkCoverage(matchNode *MatchNode, dataType data.PrimitiveType, values []a
 
 	return true
 }
+
+// Returns identifiers of enum constants or variants that don't have a case, ordered by their value.
+func (p *Parser) missingEnumCases(matchNode *MatchNode, enumName string) []string {
+	symbol := p.getGlobalSymbol(enumName)
+	if symbol == nil {
+		return []string{}
+	}
+
+	// Collect covered values
+	covered := map[int64]bool{}
+
+	for _, matchCase := range matchNode.Cases {
+		for _, expression := range matchCase.Value.(*CaseNode).Expressions {
+			if expression.NodeType == NT_Enum && expression.Value.(*EnumNode).Identifier == enumName {
+				covered[expression.Value.(*EnumNode).Value] = true
+			} else if expression.NodeType == NT_Variant {
+				covered[expression.Value.(*VariantNode).Tag] = true
+			}
+		}
+	}
+
+	// Collect all values of enum
+	values := map[string]int64{}
+
+	switch constants := symbol.value.(type) {
+	case map[string]int64:
+		values = constants
+	case map[string]*VariantSymbol:
+		for identifier, variant := range constants {
+			values[identifier] = variant.tag
+		}
+	}
+
+	// Find missing values
+	missing := []string{}
+
+	for identifier, value := range values {
+		if !covered[value] {
+			missing = append(missing, identifier)
+		}
+	}
+
+	sort.Slice(missing, func(i, j int) bool {
+		return values[missing[i]] < values[missing[j]]
+	})
+
+	return missing
+}
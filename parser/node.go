@@ -47,13 +47,18 @@ const (
 	NT_ForLoop
 	NT_ForEachLoop
 	NT_Break
+	NT_Continue
 	NT_ListValue
+	NT_Slice
+	NT_Range
 	NT_ListAssign
 	NT_List
 	NT_Variable
 	NT_Literal
 	NT_Delete
 	NT_Enum
+	NT_Variant
+	NT_VariantPayload
 	NT_Object
 	NT_ObjectField
 	NT_Set
@@ -61,6 +66,11 @@ const (
 	NT_IsNone
 	NT_Match
 	NT_Case
+	NT_Try
+	NT_Throw
+	NT_Map
+	NT_Lambda
+	NT_FunctionReference
 )
 
 var NodeTypeToString = map[NodeType]string{
@@ -95,13 +105,18 @@ var NodeTypeToString = map[NodeType]string{
 	NT_ForLoop:             "For",
 	NT_ForEachLoop:         "ForEach",
 	NT_Break:               "Break",
+	NT_Continue:            "Continue",
 	NT_ListValue:           "ListValue",
+	NT_Slice:               "Slice",
+	NT_Range:               "Range",
 	NT_ListAssign:          "ListAssign",
 	NT_List:                "List",
 	NT_Variable:            "Variable",
 	NT_Literal:             "Literal",
 	NT_Delete:              "Delete",
 	NT_Enum:                "Enum",
+	NT_Variant:             "Variant",
+	NT_VariantPayload:      "VariantPayload",
 	NT_Object:              "Object",
 	NT_ObjectField:         "ObjectField",
 	NT_Set:                 "Set",
@@ -109,6 +124,11 @@ var NodeTypeToString = map[NodeType]string{
 	NT_IsNone:              "IsNone",
 	NT_Match:               "Match",
 	NT_Case:                "Case",
+	NT_Try:                 "Try",
+	NT_Throw:               "Throw",
+	NT_Map:                 "Map",
+	NT_Lambda:              "Lambda",
+	NT_FunctionReference:   "FunctionReference",
 	NT_Ternary:             "Ternary",
 	NT_TernaryBranches:     "TernaryBranches",
 }
@@ -180,6 +200,14 @@ type FunctionCallNode struct {
 	Arguments     []*Node
 	ArgumentTypes []*data.DataType
 	ReturnType    *data.DataType
+	Function      *Node // Expression of called function value, nil if function is called by it's name
+	Receiver      *Node // Object on which a method is called, nil if it's not a method
+}
+
+type FunctionReferenceNode struct {
+	Number     int
+	Identifier string
+	DataType   *data.DataType
 }
 
 type IfNode struct {
@@ -195,6 +223,7 @@ type IfStatement struct {
 type ForLoopNode struct {
 	InitStatement []*Node
 	Body          *Node
+	StepStatement []*Node
 }
 
 type ForEachLoopNode struct {
@@ -208,6 +237,24 @@ type ListNode struct {
 	DataType *data.DataType
 }
 
+type MapNode struct {
+	Keys     []*Node
+	Values   []*Node
+	DataType *data.DataType
+}
+
+type SliceNode struct {
+	Expression *Node
+	Start, End *Node // nil if index is omitted
+	DataType   *data.DataType
+}
+
+type RangeNode struct {
+	Start, End *Node
+	Step       *Node // nil if step is omitted
+	Inclusive  bool
+}
+
 type ListAssignNode struct {
 	Identifier         string
 	ListSymbol         *VariableSymbol
@@ -220,9 +267,23 @@ type EnumNode struct {
 	Value      int64
 }
 
+type VariantNode struct {
+	Enum       string
+	Identifier string
+	Tag        int64
+	Payload    []*Node
+}
+
+type VariantPayloadNode struct {
+	Variant      *Node
+	PayloadIndex int
+	DataType     *data.DataType
+}
+
 type ObjectNode struct {
 	Identifier string
 	Properties []*Node
+	DataType   *data.DataType
 }
 
 type ObjectFieldNode struct {
@@ -242,6 +303,13 @@ type MatchNode struct {
 type CaseNode struct {
 	Expressions []*Node
 	Statement   *Node
+	Bindings    []*Node
+}
+
+type TryNode struct {
+	Body            *Node
+	ErrorIdentifier string
+	CatchBody       *Node
 }
 
 var TokenTypeToNodeType = map[lexer.TokenType]NodeType{
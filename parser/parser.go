@@ -26,12 +26,17 @@ var TokenTypeToDataType = map[lexer.TokenType]data.PrimitiveType{
 
 	lexer.TT_KW_list: data.DT_List,
 	lexer.TT_KW_set:  data.DT_Set,
+	lexer.TT_KW_map:  data.DT_Map,
 
 	lexer.TT_LT_None: data.DT_None,
 }
 
 type Parser struct {
-	tokens []*lexer.Token
+	moduleTokens [][]*lexer.Token // Tokens of modules, every module is after modules it imports
+	tokens       []*lexer.Token   // Tokens of currently parsed module
+
+	modules map[string]*ModuleSymbol
+	module  *ModuleSymbol // Currently parsed module
 
 	tokenIndex int
 
@@ -43,6 +48,8 @@ type Parser struct {
 	functions     []*FunctionSymbol
 	functionIndex int
 
+	structTypeParameters map[string][]string // Type parameters of generic structs
+
 	ErrorCount      uint
 	totalErrorCount uint
 
@@ -51,11 +58,18 @@ type Parser struct {
 	StringConstants map[string]int
 
 	optimize bool
+
+	Index    *SymbolIndex // Collects declarations and uses of symbols if it's set
+	Warnings *[]Warning   // Collects warnings instead of printing them if it's set, warnings of linter rules are only checked then
 }
 
-func NewParser(tokens []*lexer.Token, previousErrors uint, optimize bool) Parser {
+func NewParser(moduleTokens [][]*lexer.Token, previousErrors uint, optimize bool) Parser {
 	return Parser{
-		tokens: tokens,
+		moduleTokens: moduleTokens,
+		tokens:       moduleTokens[len(moduleTokens)-1],
+
+		modules: map[string]*ModuleSymbol{},
+		module:  nil,
 
 		tokenIndex: 0,
 
@@ -67,6 +81,8 @@ func NewParser(tokens []*lexer.Token, previousErrors uint, optimize bool) Parser
 		functions:     []*FunctionSymbol{},
 		functionIndex: 0,
 
+		structTypeParameters: map[string][]string{},
+
 		ErrorCount:      0,
 		totalErrorCount: previousErrors,
 
@@ -146,18 +162,82 @@ func (p *Parser) leaveScope() *ScopeNode {
 }
 
 func (p *Parser) skipStatement() {
-	for p.peek().TokenType != lexer.TT_EndOfCommand {
+	depth := 0
+
+	// Skip until EOC which isn't inside of a lambda body or a literal
+	for p.peek().TokenType != lexer.TT_EndOfCommand || depth > 0 {
+		if p.peek().TokenType.IsOpeningDelimiter() {
+			depth++
+		} else if p.peek().TokenType.IsClosingDelimiter() {
+			depth--
+		}
 		p.consume()
 	}
 
 	p.consume()
 }
 
+// Skips function header and body.
+func (p *Parser) skipFunction() {
+	for p.peek().TokenType != lexer.TT_DL_BraceOpen {
+		p.consume()
+	}
+	p.consume() // {
+
+	depth := 1
+	for depth > 0 {
+		if p.peek().TokenType.IsOpeningDelimiter() {
+			depth++
+		} else if p.peek().TokenType.IsClosingDelimiter() {
+			depth--
+		}
+		p.consume()
+	}
+}
+
 func (p *Parser) Parse() *Node {
-	return p.parseModule()
+	globals := []*Node{}
+	functions := []*Node{}
+	var entryModule *ModuleNode
+
+	// Parse imported modules before modules that import them
+	for i, tokens := range p.moduleTokens {
+		module := p.parseModule(tokens, i == len(p.moduleTokens)-1)
+
+		// Globals of all modules have to be declared before functions
+		statements := module.Statements.Statements
+		globalCount := 0
+
+		for globalCount < len(statements) && (statements[globalCount].NodeType == NT_VariableDeclaration || statements[globalCount].NodeType == NT_Assign) {
+			globalCount++
+		}
+
+		globals = append(globals, statements[:globalCount]...)
+		functions = append(functions, statements[globalCount:]...)
+
+		entryModule = module
+	}
+
+	entryModule.Statements.Statements = append(globals, functions...)
+
+	// No entry function
+	if p.getGlobalSymbol("entry") == nil {
+		p.newWarning(W_MissingEntry, nil, "The entry() function wasn't found. The compiled program won't be executable by itself.")
+	}
+
+	p.checkFunctionCalls()
+	p.indexGlobals()
+
+	return &Node{p.peek().Position, NT_Module, entryModule}
 }
 
-func (p *Parser) parseModule() *Node {
+func (p *Parser) parseModule(tokens []*lexer.Token, isEntry bool) *ModuleNode {
+	p.tokens = tokens
+	p.tokenIndex = 0
+
+	p.scopeNodeStack = data.NewStack()
+	p.stack_symbolTableStack = data.NewStack()
+
 	// Collect module path and name
 	modulePath := p.consume().Value
 	pathParts := strings.Split(modulePath, "/")
@@ -171,8 +251,13 @@ func (p *Parser) parseModule() *Node {
 	p.enterScope()
 	p.StringConstants[moduleName] = -1
 
+	// Register module
+	p.module = &ModuleSymbol{moduleName, isEntry, p.stack_symbolTableStack.Bottom.Value.(symbolTable), map[*Symbol]bool{}}
+	p.modules[moduleName] = p.module
+
 	// Insert built-in functions
 	p.insertBuiltInFunctions()
+	p.insertBuiltInStructs()
 
 	// Collect global variables, enums, structs and function headers
 	p.collectGlobals()
@@ -180,36 +265,106 @@ func (p *Parser) parseModule() *Node {
 	// Parse module
 	scopeNode := p.parseScope(false, false)
 
-	// Create module node
-	var moduleNode any = &ModuleNode{modulePath, moduleName, scopeNode.(*ScopeNode)}
-	module := &Node{p.peek().Position, NT_Module, moduleNode}
+	return &ModuleNode{modulePath, moduleName, scopeNode.(*ScopeNode)}
+}
 
-	// No entry function
-	if p.getGlobalSymbol("entry") == nil {
-		logger.Warning("The entry() function wasn't found. The compiled program won't be executable by itself.")
+// Parses input of an interactive session. Symbols declared by previous inputs stay declared.
+// Symbols declared by input with errors are removed, because it won't be executed.
+func (p *Parser) ParseInput(tokens []*lexer.Token) *Node {
+	p.tokens = tokens
+	p.tokenIndex = 0
+	p.ErrorCount = 0
+
+	moduleName := p.consume().Value
+	p.StringConstants[moduleName] = -1
+
+	// First input enters global scope
+	if p.module == nil {
+		p.enterScope()
+
+		p.module = &ModuleSymbol{moduleName, true, p.stack_symbolTableStack.Bottom.Value.(symbolTable), map[*Symbol]bool{}}
+		p.modules[moduleName] = p.module
+
+		p.insertBuiltInFunctions()
+		p.insertBuiltInStructs()
+	}
+
+	// Leave scopes left open by previous input
+	for p.scopeNodeStack.Size > 1 {
+		p.leaveScope()
+	}
+
+	scope := p.scopeNodeStack.Top.Value.(*ScopeNode)
+	scope.Statements = []*Node{}
+
+	declaredSymbols := map[string]bool{}
+	for identifier := range p.module.symbols {
+		declaredSymbols[identifier] = true
+	}
+	functionCount := len(p.functions)
+
+	// Forget symbols of invalid input. Parser can fail on invalid input after it's errors were reported.
+	defer func() {
+		if p.ErrorCount == 0 {
+			return
+		}
+		recover()
+
+		for identifier := range p.module.symbols {
+			if !declaredSymbols[identifier] {
+				delete(p.module.symbols, identifier)
+			}
+		}
+
+		p.functions = p.functions[:functionCount]
+		p.functionIndex = functionCount
+	}()
+
+	p.collectGlobals()
+	p.parseScope(false, false)
+
+	for _, statement := range scope.Statements {
+		if statement.NodeType == NT_Return {
+			p.newError(statement.Position, "Return statement can't be used outside of a function.")
+		}
+	}
+
+	return &Node{tokens[0].Position, NT_Module, &ModuleNode{moduleName, moduleName, scope}}
+}
+
+// Reserves numbers of functions created by the code generator without a symbol (lambdas), so functions declared by later inputs don't reuse them.
+func (p *Parser) ReserveFunctionNumbers(functionCount int) {
+	for len(p.functions) < functionCount {
+		p.functions = append(p.functions, nil)
 	}
+	p.functionIndex = len(p.functions)
+}
+
+// Warns about functions that were never called. Public functions of imported modules are meant to be called by other programs.
+func (p *Parser) checkFunctionCalls() {
+	for _, module := range p.modules {
+		for identifier, symbol := range module.symbols {
+			// Try to find function bucket symbol
+			if symbol.symbolType != ST_FunctionBucket {
+				continue
+			}
 
-	// Check if all functions were called
-	for identifier, symbol := range p.stack_symbolTableStack.Bottom.Value.(symbolTable) {
-		// Try to find function bucket symbol
-		if symbol.symbolType == ST_FunctionBucket {
 			// Check if every function in the bucket was ever called
 			for _, functionSymbol := range symbol.value.(symbolTable) {
-				if !functionSymbol.value.(*FunctionSymbol).everCalled {
-					logger.Warning("Function " + identifier + " was never called.")
+				if !functionSymbol.value.(*FunctionSymbol).everCalled && (module.isEntry || !module.public[functionSymbol]) {
+					p.newWarning(W_UnusedFunction, p.declarationPosition(functionSymbol), "Function "+identifier+" was never called.")
 				}
 			}
 		}
 	}
-
-	return module
 }
 
 func (p *Parser) parseScope(enterScope, packInNode bool) any {
 	// Consume opening brace
 	opening := p.peek().Position
+	hasBraces := p.peek().TokenType == lexer.TT_DL_BraceOpen
 
-	if p.peek().TokenType == lexer.TT_DL_BraceOpen {
+	if hasBraces {
 		p.consume()
 	}
 
@@ -220,10 +375,16 @@ func (p *Parser) parseScope(enterScope, packInNode bool) any {
 	scope := p.scopeNodeStack.Top.Value.(*ScopeNode)
 
 	// Collect statements
+	statementCount := 0
+
 	for !p.peek().IsEndOfFileOf(p.tokens[0]) {
 		statement := p.parseStatement(enterScope)
 
 		if statement == nil {
+			if hasBraces && statementCount == 0 {
+				p.checkEmptyBlock(opening, p.peekPrevious().Position)
+			}
+
 			if packInNode {
 				return &Node{opening, NT_Scope, scope}
 			} else {
@@ -232,6 +393,7 @@ func (p *Parser) parseScope(enterScope, packInNode bool) any {
 		}
 
 		scope.Statements = append(scope.Statements, statement)
+		statementCount++
 	}
 
 	// Un-exited scope
@@ -249,7 +411,7 @@ func (p *Parser) parseScope(enterScope, packInNode bool) any {
 
 func (p *Parser) parseStatement(enteredScope bool) *Node {
 	// Variable declaration
-	if p.peek().TokenType.IsVariableType() {
+	if p.peek().TokenType.IsVariableType() || p.isFunctionType() {
 		// Skip statement if it's in root scope (global variable)
 		if p.scopeNodeStack.Size == 1 {
 			p.skipStatement()
@@ -273,7 +435,7 @@ func (p *Parser) parseStatement(enteredScope bool) *Node {
 
 	// Function declaration
 	case lexer.TT_KW_fun:
-		return p.parseFunctionDeclaration()
+		return p.parseFunctionDeclaration("")
 
 	// Leave scope
 	case lexer.TT_DL_BraceClose:
@@ -334,6 +496,15 @@ func (p *Parser) parseStatement(enteredScope bool) *Node {
 	case lexer.TT_KW_break:
 		return &Node{p.consume().Position, NT_Break, nil}
 
+	// Continue
+	case lexer.TT_KW_continue:
+		return &Node{p.consume().Position, NT_Continue, nil}
+
+	// Class
+	case lexer.TT_KW_class:
+		p.parseClass()
+		return p.parseStatement(enteredScope)
+
 	// Struct, enum
 	case lexer.TT_KW_struct, lexer.TT_KW_enum:
 		// Skip over enums and structs, because they were registered in collectGlobals()
@@ -352,12 +523,25 @@ func (p *Parser) parseStatement(enteredScope bool) *Node {
 	case lexer.TT_KW_match:
 		return p.parseMatch(false)
 
+	// Try
+	case lexer.TT_KW_try:
+		return p.parseTry()
+
+	// Throw
+	case lexer.TT_KW_throw:
+		return p.parseThrow()
+
 	// Ignore imports
 	case lexer.TT_KW_import:
 		p.consume()
 		p.consume()
 		return p.parseStatement(enteredScope)
 
+	// Visibility of declarations was collected with globals
+	case lexer.TT_KW_pub:
+		p.consume()
+		return p.parseStatement(enteredScope)
+
 	// Ignore StartOfFiles
 	case lexer.TT_StartOfFile:
 		p.StringConstants[p.consume().Value] = -1
@@ -381,37 +565,74 @@ func (p *Parser) parseStatement(enteredScope bool) *Node {
 	panic(p.peek().Position.String() + " Unexpected token " + p.peek().TokenType.String() + " \"" + p.consume().String() + "\".")
 }
 
+func (p *Parser) isFunctionType() bool {
+	return p.peek().TokenType == lexer.TT_KW_fun && p.peekNext().TokenType == lexer.TT_DL_ParenthesisOpen
+}
+
 func (p *Parser) parseType() *data.DataType {
+	// Function type
+	if p.peek().TokenType == lexer.TT_KW_fun {
+		return p.parseFunctionType()
+	}
+
 	// Convert current token to data type
 	variableType := &data.DataType{TokenTypeToDataType[p.peek().TokenType], nil}
 
-	// Token is not a data type keyword => it's enum or struct
+	// Token is not a data type keyword => it's type parameter, enum or struct
 	if variableType.Type == data.DT_Unknown {
-		symbol := p.getGlobalSymbol(p.peek().Value)
+		// Type parameter of generic function or struct
+		if p.isTypeParameter(p.peek().Value) {
+			variableType.Type = data.DT_Generic
+		} else {
+			symbol := p.findSymbol(p.peek().Value)
 
-		// Neither primitive or user defined type => type can't be determined
-		if symbol == nil {
-			p.consume()
-			return variableType // DT_Unknown
-		}
+			// Neither primitive or user defined type => type can't be determined
+			if symbol == nil {
+				if p.isPrivateSymbol(p.peek().Value) {
+					p.newError(p.peek().Position, "Type "+p.peek().Value+" isn't public.")
+				}
+
+				p.consume()
+				return variableType // DT_Unknown
+			}
 
-		// Symbol is a struct
-		if symbol.symbolType == ST_Struct {
-			variableType.Type = data.DT_Object
-			// Symbol is a enum
-		} else if symbol.symbolType == ST_Enum {
-			variableType.Type = data.DT_Enum
+			p.indexUse(symbol, p.peek())
+
+			// Symbol is a struct
+			if symbol.symbolType == ST_Struct {
+				variableType.Type = data.DT_Object
+				// Symbol is a enum
+			} else if symbol.symbolType == ST_Enum {
+				variableType.Type = data.DT_Enum
+			}
 		}
 
-		// Set sub-type to struct/enum name
+		// Set sub-type to type parameter/struct/enum name
 		variableType.SubType = p.peek().Value
 	}
 
 	// Create data type
 	p.consume()
 
-	// Insert subtype to list data type
-	if variableType.IsCompositeType() {
+	// Collect type arguments of generic struct
+	if variableType.Type == data.DT_Object && len(p.structTypeParameters[variableType.SubType.(string)]) != 0 {
+		variableType.SubType = p.parseTypeArguments(variableType.SubType.(string))
+	}
+
+	// Insert key and value types to map data type
+	if variableType.Type == data.DT_Map {
+		p.consume() // <
+		keyType := p.parseType()
+		p.consume() // ,
+		variableType.SubType = &data.MapType{keyType, p.parseType()}
+		p.consume() // >
+
+		// Only hashable types can be used as keys
+		if !canBeMapKey(keyType) {
+			p.newError(p.peekPrevious().Position, "Map key type can't be "+keyType.String()+". Only bool, int, flt, str and enums can be used as keys.")
+		}
+		// Insert subtype to list data type
+	} else if variableType.IsCompositeType() {
 		p.consume() // <
 		variableType.SubType = p.parseType()
 		p.consume() // >
@@ -426,6 +647,37 @@ func (p *Parser) parseType() *data.DataType {
 	return variableType
 }
 
+func (p *Parser) parseFunctionType() *data.DataType {
+	p.consume() // fun
+	p.consume() // (
+
+	// Collect parameter types
+	parameters := []*data.DataType{}
+
+	for p.peek().TokenType != lexer.TT_DL_ParenthesisClose {
+		parameters = append(parameters, p.parseType())
+
+		if p.peek().TokenType == lexer.TT_DL_Comma {
+			p.consume()
+		}
+	}
+	p.consume() // )
+
+	// Collect return type
+	var returnType *data.DataType
+
+	if p.peek().TokenType == lexer.TT_KW_returns {
+		p.consume()
+		returnType = p.parseType()
+	}
+
+	return &data.DataType{data.DT_Function, &data.FunctionType{parameters, returnType}}
+}
+
+func canBeMapKey(dataType *data.DataType) bool {
+	return dataType.Type <= data.DT_String || dataType.Type == data.DT_Enum
+}
+
 func (p *Parser) consumeEOCs() {
 	for p.peek().TokenType == lexer.TT_EndOfCommand {
 		p.consume()
@@ -465,3 +717,116 @@ func (p *Parser) parseDelete() *Node {
 
 	return &Node{position, NT_Delete, expression}
 }
+
+func (p *Parser) parseTypeArguments(structName string) *data.ObjectType {
+	typeParameters := p.structTypeParameters[structName]
+	typeArguments := []*data.DataType{}
+	position := p.peekPrevious().Position
+
+	// Type arguments are missing
+	if p.peek().TokenType != lexer.TT_OP_Lower {
+		p.newError(position, "Struct "+structName+" is generic, so it's type arguments have to be specified.")
+	} else {
+		p.consume() // <
+
+		for p.peek().TokenType != lexer.TT_OP_Greater {
+			typeArguments = append(typeArguments, p.parseType())
+
+			if p.peek().TokenType == lexer.TT_DL_Comma {
+				p.consume()
+			}
+		}
+
+		p.consume() // >
+		position = position.Combine(p.peekPrevious().Position)
+
+		// Incorrect amount of type arguments
+		if len(typeArguments) != len(typeParameters) {
+			p.newError(position, "Struct "+structName+fmt.Sprintf(" has %d type parameter/s, but %d type argument/s were provided.", len(typeParameters), len(typeArguments)))
+		}
+	}
+
+	// Replace missing type arguments with unknown types
+	for len(typeArguments) < len(typeParameters) {
+		typeArguments = append(typeArguments, &data.DataType{data.DT_Unknown, nil})
+	}
+
+	return &data.ObjectType{structName, typeArguments[:len(typeParameters)]}
+}
+
+// Collects identifiers of type parameters of generic function or struct.
+func (p *Parser) parseTypeParameters() []string {
+	typeParameters := []string{}
+
+	if p.peek().TokenType != lexer.TT_OP_Lower {
+		return typeParameters
+	}
+	p.consume() // <
+
+	for p.peek().TokenType != lexer.TT_OP_Greater {
+		typeParameter := p.consume()
+
+		// Check for redeclaration
+		for _, previous := range typeParameters {
+			if previous == typeParameter.Value {
+				p.newError(typeParameter.Position, "Type parameter "+typeParameter.Value+" is already declared.")
+			}
+		}
+		typeParameters = append(typeParameters, typeParameter.Value)
+
+		if p.peek().TokenType == lexer.TT_DL_Comma {
+			p.consume()
+		}
+	}
+	p.consume() // >
+
+	return typeParameters
+}
+
+func (p *Parser) insertTypeParameters(typeParameters []string) {
+	for _, typeParameter := range typeParameters {
+		p.insertSymbol(typeParameter, &Symbol{ST_TypeParameter, nil})
+	}
+}
+
+func (p *Parser) isTypeName(identifier string) bool {
+	symbol := p.getGlobalSymbol(identifier)
+	return symbol != nil && (symbol.symbolType == ST_Struct || symbol.symbolType == ST_Enum) || p.isTypeParameter(identifier)
+}
+
+func (p *Parser) isTypeParameter(identifier string) bool {
+	symbol := p.findSymbol(identifier)
+	return symbol != nil && symbol.symbolType == ST_TypeParameter
+}
+
+// Creates data type of struct. Generic structs have their own type parameters as type arguments.
+func (p *Parser) structType(structName string) *data.DataType {
+	typeParameters := p.structTypeParameters[structName]
+
+	if len(typeParameters) == 0 {
+		return &data.DataType{data.DT_Object, structName}
+	}
+
+	typeArguments := make([]*data.DataType, len(typeParameters))
+	for i, typeParameter := range typeParameters {
+		typeArguments[i] = &data.DataType{data.DT_Generic, typeParameter}
+	}
+
+	return &data.DataType{data.DT_Object, &data.ObjectType{structName, typeArguments}}
+}
+
+// Creates map of type arguments of an object of generic struct.
+func (p *Parser) objectTypeArguments(objectType *data.DataType) map[string]*data.DataType {
+	typeArguments := map[string]*data.DataType{}
+
+	subType, isGeneric := objectType.SubType.(*data.ObjectType)
+	if !isGeneric {
+		return typeArguments
+	}
+
+	for i, typeParameter := range p.structTypeParameters[subType.Name] {
+		typeArguments[typeParameter] = subType.TypeArguments[i]
+	}
+
+	return typeArguments
+}
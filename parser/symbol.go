@@ -1,7 +1,11 @@
 package parser
 
 import (
+	"fmt"
+	"strings"
+
 	data "github.com/DanielNos/neco/dataStructures"
+	"github.com/DanielNos/neco/lexer"
 )
 
 type Symbol struct {
@@ -17,6 +21,8 @@ const (
 	ST_Function
 	ST_Struct
 	ST_Enum
+	ST_TypeParameter
+	ST_Module
 )
 
 func (st SymbolType) String() string {
@@ -29,6 +35,10 @@ func (st SymbolType) String() string {
 		return "struct"
 	case ST_Enum:
 		return "enum"
+	case ST_TypeParameter:
+		return "type parameter"
+	case ST_Module:
+		return "module"
 	}
 
 	return "UNDEFINED"
@@ -36,6 +46,11 @@ func (st SymbolType) String() string {
 
 type SymbolValue any
 
+type VariantSymbol struct {
+	tag     int64
+	payload []Parameter
+}
+
 type VariableSymbol struct {
 	VariableType  *data.DataType
 	isInitialized bool
@@ -49,17 +64,86 @@ type FunctionSymbol struct {
 	everCalled bool
 }
 
+func (fs *FunctionSymbol) isGeneric() bool {
+	for _, parameter := range fs.parameters {
+		if parameter.DataType.IsGeneric() {
+			return true
+		}
+	}
+
+	return false
+}
+
 type PropertySymbol struct {
 	number   int
 	dataType *data.DataType
 }
 
+type ModuleSymbol struct {
+	name    string
+	isEntry bool
+	symbols symbolTable      // Global symbols of module
+	public  map[*Symbol]bool // Symbols visible to modules importing this module
+}
+
+// Looks up global symbol of module. Types are declared with identifiers qualified with module name, other symbols without it.
+func (ms *ModuleSymbol) lookup(identifier string) *Symbol {
+	if symbol, exists := ms.symbols[identifier]; exists {
+		return symbol
+	}
+
+	if symbol, exists := ms.symbols[strings.TrimPrefix(identifier, ms.name+".")]; exists {
+		return symbol
+	}
+
+	return nil
+}
+
+// Looks up global symbol of module visible to modules importing it. Function buckets are filtered to public functions only.
+func (ms *ModuleSymbol) lookupPublic(identifier string) *Symbol {
+	symbol := ms.lookup(identifier)
+
+	if symbol == nil {
+		return nil
+	}
+
+	// Collect public functions
+	if symbol.symbolType == ST_FunctionBucket {
+		functions := symbolTable{}
+
+		for id, function := range symbol.value.(symbolTable) {
+			if ms.public[function] {
+				functions[id] = function
+			}
+		}
+
+		if len(functions) == 0 {
+			return nil
+		}
+
+		return &Symbol{ST_FunctionBucket, functions}
+	}
+
+	if !ms.public[symbol] {
+		return nil
+	}
+
+	return symbol
+}
+
 type symbolTable map[string]*Symbol
 
 func (p *Parser) insertSymbol(key string, symbol *Symbol) {
 	p.stack_symbolTableStack.Top.Value.(symbolTable)[key] = symbol
 }
 
+// Inserts symbol declared at position and records it's declaration.
+func (p *Parser) insertIndexedSymbol(key string, symbol *Symbol, position *data.CodePos) {
+	p.checkShadowing(key, position)
+	p.insertSymbol(key, symbol)
+	p.indexDeclaration(symbol, key, position)
+}
+
 func (p *Parser) findSymbol(identifier string) *Symbol {
 	stackNode := p.stack_symbolTableStack.Top
 
@@ -73,7 +157,72 @@ func (p *Parser) findSymbol(identifier string) *Symbol {
 		stackNode = stackNode.Previous
 	}
 
-	return nil
+	return p.findImportedSymbol(identifier)
+}
+
+// Finds symbol by identifier qualified with module name. Symbols of imported modules have to be public.
+func (p *Parser) findImportedSymbol(identifier string) *Symbol {
+	moduleName, _, isQualified := strings.Cut(identifier, ".")
+
+	if !isQualified {
+		return nil
+	}
+
+	// Symbol of current module
+	if moduleName == p.module.name {
+		return p.module.lookup(identifier)
+	}
+
+	// Symbol of imported module
+	module := p.importedModule(moduleName)
+
+	if module == nil {
+		return nil
+	}
+
+	return module.lookupPublic(identifier)
+}
+
+// Checks if qualified identifier is a symbol of imported module, which isn't public.
+func (p *Parser) isPrivateSymbol(identifier string) bool {
+	moduleName, _, isQualified := strings.Cut(identifier, ".")
+
+	if !isQualified {
+		return false
+	}
+
+	module := p.importedModule(moduleName)
+
+	return module != nil && module.lookup(identifier) != nil && module.lookupPublic(identifier) == nil
+}
+
+// Checks if qualified identifier is a built-in function accessed through an imported module. Returns name of the module and of the function.
+func (p *Parser) isQualifiedBuiltInFunction(identifier string) (string, string, bool) {
+	moduleName, function, isQualified := strings.Cut(identifier, ".")
+
+	if !isQualified {
+		return "", "", false
+	}
+
+	module := p.importedModule(moduleName)
+
+	if module == nil || module.lookupPublic(identifier) != nil {
+		return "", "", false
+	}
+
+	symbol := module.lookup(identifier)
+
+	return moduleName, function, symbol != nil && symbol.symbolType == ST_FunctionBucket && p.isBuiltInFunction(symbol)
+}
+
+func (p *Parser) importedModule(moduleName string) *ModuleSymbol {
+	symbol, exists := p.stack_symbolTableStack.Bottom.Value.(symbolTable)[moduleName]
+
+	if !exists || symbol.symbolType != ST_Module {
+		return nil
+	}
+
+	return symbol.value.(*ModuleSymbol)
 }
 
 func (p *Parser) getSymbol(identifier string) *Symbol {
@@ -92,9 +241,49 @@ func (p *Parser) getGlobalSymbol(identifier string) *Symbol {
 	if exists {
 		return symbol
 	}
+
+	// Types of values can come from any module, even if it isn't imported by the current module
+	moduleName, _, isQualified := strings.Cut(identifier, ".")
+
+	if module, exists := p.modules[moduleName]; isQualified && exists {
+		return module.lookup(identifier)
+	}
+
 	return nil
 }
 
+// Marks global symbol of current module as visible to modules importing it.
+func (p *Parser) markPublic(symbol *Symbol) {
+	p.module.public[symbol] = true
+}
+
+// Checks if declaration starting at token index is marked as public with keyword pub.
+func (p *Parser) isPublic(declarationStart int) bool {
+	// Skip keyword const
+	if declarationStart > 0 && p.tokens[declarationStart-1].TokenType == lexer.TT_KW_const {
+		declarationStart--
+	}
+
+	return declarationStart > 0 && p.tokens[declarationStart-1].TokenType == lexer.TT_KW_pub
+}
+
+// Returns identifier of variable used in generated code. Global variables of imported modules are qualified with module name, so they don't collide.
+func (p *Parser) variableIdentifier(identifier string, symbol *Symbol) string {
+	if !strings.Contains(identifier, ".") && p.stack_symbolTableStack.Bottom.Value.(symbolTable)[identifier] == symbol {
+		return p.qualifyGlobal(identifier)
+	}
+
+	return identifier
+}
+
+func (p *Parser) qualifyGlobal(identifier string) string {
+	if p.module.isEntry {
+		return identifier
+	}
+
+	return p.module.name + "." + identifier
+}
+
 func (p *Parser) insertFunction(name string, functionSymbol *FunctionSymbol) *Symbol {
 	// Find bucket
 	bucket, exists := p.stack_symbolTableStack.Bottom.Value.(symbolTable)[name]
@@ -113,9 +302,20 @@ func (p *Parser) insertFunction(name string, functionSymbol *FunctionSymbol) *Sy
 }
 
 func createParametersIdentifier(parameters []Parameter) string {
+	// Collect type parameters, so they can be replaced by their order
+	typeParameters := []string{}
+	for _, parameter := range parameters {
+		parameter.DataType.CollectTypeParameters(&typeParameters)
+	}
+
+	typeArguments := map[string]*data.DataType{}
+	for i, typeParameter := range typeParameters {
+		typeArguments[typeParameter] = &data.DataType{data.DT_Generic, fmt.Sprintf("$%d", i)}
+	}
+
 	id := ""
 	for _, parameter := range parameters {
-		id += "." + parameter.DataType.String()
+		id += "." + parameter.DataType.Substitute(typeArguments).String()
 	}
 
 	return id
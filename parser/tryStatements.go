@@ -0,0 +1,63 @@
+package parser
+
+import (
+	data "github.com/DanielNos/neco/dataStructures"
+	"github.com/DanielNos/neco/lexer"
+)
+
+func (p *Parser) parseTry() *Node {
+	startPosition := p.consume().Position
+
+	if p.peek().TokenType == lexer.TT_EndOfCommand {
+		p.consume()
+	}
+
+	// Collect try body
+	body := p.parseScope(true, true).(*Node)
+
+	if p.peek().TokenType == lexer.TT_EndOfCommand {
+		p.consume()
+	}
+
+	p.consume() // catch
+
+	// Enter catch scope
+	p.enterScope()
+
+	// Collect error variable and insert it into symbol table
+	errorIdentifier := ""
+
+	if p.peek().TokenType == lexer.TT_DL_ParenthesisOpen {
+		p.consume()
+		errorToken := p.consume()
+		errorIdentifier = errorToken.Value
+		p.consume()
+
+		p.insertIndexedSymbol(errorIdentifier, &Symbol{ST_Variable, &VariableSymbol{&data.DataType{data.DT_Object, "Error"}, true, false}}, errorToken.Position)
+	}
+
+	if p.peek().TokenType == lexer.TT_EndOfCommand {
+		p.consume()
+	}
+
+	// Collect catch body
+	catchBody := p.parseScope(false, true).(*Node)
+	p.leaveScope()
+
+	return &Node{startPosition, NT_Try, &TryNode{body, errorIdentifier, catchBody}}
+}
+
+func (p *Parser) parseThrow() *Node {
+	startPosition := p.consume().Position
+
+	// Collect thrown expression
+	expression := p.parseExpressionRoot()
+	expressionType := GetExpressionType(expression)
+
+	// Only messages and errors can be thrown
+	if expressionType.Type != data.DT_String && !expressionType.Equals(&data.DataType{data.DT_Object, "Error"}) && expressionType.Type != data.DT_Unknown {
+		p.newError(expression.Position, "Thrown expression has to be of type str or Error, found "+expressionType.String()+" instead.")
+	}
+
+	return &Node{startPosition, NT_Throw, expression}
+}
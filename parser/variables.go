@@ -7,6 +7,7 @@ import (
 
 func (p *Parser) parseVariableDeclaration(constant bool) *Node {
 	startPosition := p.peek().Position
+	declarationStart := p.tokenIndex
 
 	// Collect data type
 	variableType := p.parseType()
@@ -15,7 +16,7 @@ func (p *Parser) parseVariableDeclaration(constant bool) *Node {
 	variableNodes, variableIdentifiers := p.parseVariableIdentifiers(variableType)
 
 	// Create node
-	declareNode := &Node{startPosition.Combine(variableNodes[len(variableNodes)-1].Position), NT_VariableDeclaration, &VariableDeclareNode{variableType, constant, variableIdentifiers}}
+	declareNode := &Node{startPosition.Combine(variableNodes[len(variableNodes)-1].Position), NT_VariableDeclaration, &VariableDeclareNode{variableType, constant, p.qualifyGlobals(variableIdentifiers)}}
 
 	// End
 	if p.peek().TokenType == lexer.TT_EndOfCommand {
@@ -43,13 +44,34 @@ func (p *Parser) parseVariableDeclaration(constant bool) *Node {
 	}
 
 	// Insert symbols
-	for _, id := range variableIdentifiers {
-		p.insertSymbol(id, &Symbol{ST_Variable, &VariableSymbol{variableType, declareNode.NodeType == NT_Assign, constant}})
+	public := p.scopeNodeStack.Size == 1 && p.isPublic(declarationStart)
+
+	for i, id := range variableIdentifiers {
+		symbol := &Symbol{ST_Variable, &VariableSymbol{variableType, declareNode.NodeType == NT_Assign, constant}}
+		p.insertIndexedSymbol(id, symbol, variableNodes[i].Position)
+
+		if public {
+			p.markPublic(symbol)
+		}
 	}
 
 	return declareNode
 }
 
+// Qualifies identifiers of variables declared in root scope of imported module.
+func (p *Parser) qualifyGlobals(identifiers []string) []string {
+	if p.scopeNodeStack.Size != 1 {
+		return identifiers
+	}
+
+	qualified := make([]string, len(identifiers))
+	for i, identifier := range identifiers {
+		qualified[i] = p.qualifyGlobal(identifier)
+	}
+
+	return qualified
+}
+
 func (p *Parser) parseAssignment(assignedTo []*Node, startOfStatement *data.CodePos) (*Node, *data.DataType) {
 	assign := p.consume()
 	expressionStart := p.peek().Position
@@ -58,6 +80,12 @@ func (p *Parser) parseAssignment(assignedTo []*Node, startOfStatement *data.Code
 	expression := p.parseExpressionRoot()
 	expressionType := GetExpressionType(expression)
 
+	// Empty braces assigned to a map are an empty map
+	if expression.NodeType == NT_Set && len(expression.Value.(*ListNode).Nodes) == 0 && GetExpressionType(assignedTo[0]).Type == data.DT_Map {
+		expressionType = GetExpressionType(assignedTo[0]).Copy()
+		expression = &Node{expression.Position, NT_Map, &MapNode{[]*Node{}, []*Node{}, expressionType}}
+	}
+
 	// Incompatible data types
 	expressionPosition := data.CodePos{expressionStart.File, expressionStart.StartLine, expressionStart.EndLine, expressionStart.StartChar, p.peekPrevious().Position.EndChar}
 
@@ -83,6 +111,9 @@ func (p *Parser) parseAssignment(assignedTo []*Node, startOfStatement *data.Code
 			}
 
 			symbol = p.findSymbol(left.Value.(*VariableNode).Identifier)
+		} else if target.NodeType == NT_Slice {
+			// Slices can't be assigned to, error is reported by the statement parser
+			continue
 		} else {
 			panic("Can't check if node is constant.")
 		}
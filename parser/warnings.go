@@ -0,0 +1,83 @@
+package parser
+
+import (
+	data "github.com/DanielNos/neco/dataStructures"
+	"github.com/DanielNos/neco/logger"
+)
+
+// Rules of warnings produced by the parser
+const (
+	W_UnusedFunction     = "unused-function"
+	W_MissingEntry       = "missing-entry"
+	W_EmptyStruct        = "empty-struct"
+	W_UnnecessaryDefault = "unnecessary-default"
+	W_Shadowing          = "shadowing"
+	W_ConstantCondition  = "constant-condition"
+	W_EmptyBlock         = "empty-block"
+	W_RedundantDefault   = "redundant-default"
+)
+
+type Warning struct {
+	Rule     string
+	Position *data.CodePos // Nil for warnings about the whole program
+	Message  string
+}
+
+// Prints warning, or collects it if warnings are collected.
+func (p *Parser) newWarning(rule string, position *data.CodePos, message string) {
+	if p.Warnings != nil {
+		*p.Warnings = append(*p.Warnings, Warning{rule, position, message})
+		return
+	}
+
+	if position == nil {
+		logger.Warning(message)
+	} else {
+		logger.WarningCodePos(position, message)
+	}
+}
+
+// Warns about variable, which hides a variable of an outer scope. It's only checked when warnings are collected.
+func (p *Parser) checkShadowing(identifier string, position *data.CodePos) {
+	if p.Warnings == nil || p.stack_symbolTableStack.Size <= 1 {
+		return
+	}
+
+	for stackNode := p.stack_symbolTableStack.Top.Previous; stackNode != nil; stackNode = stackNode.Previous {
+		symbol, exists := stackNode.Value.(symbolTable)[identifier]
+
+		if exists && symbol.symbolType == ST_Variable {
+			p.newWarning(W_Shadowing, position, "Variable "+identifier+" shadows a variable declared in an outer scope.")
+			return
+		}
+	}
+}
+
+// Warns about condition, which is always true or always false. It's only checked when warnings are collected.
+func (p *Parser) checkConstantCondition(condition *Node) {
+	if p.Warnings != nil && isConstantExpression(condition) {
+		p.newWarning(W_ConstantCondition, condition.Position, "Condition is always the same, because it consists only of literals.")
+	}
+}
+
+// Warns about block without statements. It's only checked when warnings are collected.
+func (p *Parser) checkEmptyBlock(opening, closing *data.CodePos) {
+	if p.Warnings != nil {
+		p.newWarning(W_EmptyBlock, opening.Combine(closing), "Block is empty.")
+	}
+}
+
+func isConstantExpression(expression *Node) bool {
+	switch expression.NodeType {
+	case NT_Literal:
+		return true
+
+	case NT_Not, NT_And, NT_Or, NT_Add, NT_Subtract, NT_Multiply, NT_Divide, NT_Power, NT_Modulo,
+		NT_Equal, NT_NotEqual, NT_Lower, NT_Greater, NT_LowerEqual, NT_GreaterEqual:
+		binaryNode := expression.Value.(*TypedBinaryNode)
+
+		return (binaryNode.Left == nil || isConstantExpression(binaryNode.Left)) && isConstantExpression(binaryNode.Right)
+	}
+
+	return false
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	codeGen "github.com/DanielNos/neco/codeGenerator"
+	"github.com/DanielNos/neco/lexer"
+	"github.com/DanielNos/neco/logger"
+	"github.com/DanielNos/neco/parser"
+	"github.com/DanielNos/neco/syntaxAnalyzer"
+	VM "github.com/DanielNos/neco/virtualMachine"
+)
+
+const REPL_MODULE = "repl"
+
+// Interactive session. Symbols declared by inputs and values of variables are kept between inputs.
+type Repl struct {
+	syntaxAnalyzer *syntaxAnalyzer.SyntaxAnalyzer
+	parser         *parser.Parser
+	codeGenerator  *codeGen.CodeGenerator
+	virtualMachine *VM.VirtualMachine
+	reader         *bufio.Reader
+
+	printTokens bool
+	drawTree    bool
+}
+
+func NewRepl() *Repl {
+	analyzer := syntaxAnalyzer.NewSyntaxAnalyzer(nil, 0)
+	p := parser.NewParser([][]*lexer.Token{nil}, 0, false)
+	virtualMachine := VM.NewVirtualMachine(REPL_MODULE, nil)
+
+	return &Repl{
+		syntaxAnalyzer: &analyzer,
+		parser:         &p,
+		codeGenerator:  codeGen.NewGenerator(nil, p.IntConstants, p.FloatConstants, p.StringConstants, false),
+		virtualMachine: virtualMachine,
+		reader:         virtualMachine.InputReader(),
+
+		printTokens: false,
+		drawTree:    false,
+	}
+}
+
+func (r *Repl) Run() {
+	fmt.Printf("NeCo %d.%d.%d REPL. Use :help to list commands.\n", VM.VERSION_MAJOR, VM.VERSION_MINOR, VM.VERSION_PATCH)
+
+	for {
+		input, ok := r.readInput()
+
+		// End of input
+		if !ok {
+			fmt.Println()
+			return
+		}
+
+		command := strings.TrimSpace(input)
+
+		if len(command) == 0 {
+			continue
+		} else if command[0] == ':' {
+			if !r.executeCommand(command) {
+				return
+			}
+		} else {
+			r.execute(input)
+		}
+	}
+}
+
+// Reads input from standard input. Input continues on next lines until all of it's braces, brackets and parentheses are closed.
+func (r *Repl) readInput() (string, bool) {
+	fmt.Print("> ")
+	input := ""
+
+	for {
+		line, err := r.reader.ReadString('\n')
+		input += line
+
+		if err != nil {
+			return input, len(strings.TrimSpace(input)) != 0
+		}
+
+		if delimiterDepth(input) <= 0 {
+			return input, true
+		}
+
+		fmt.Print(". ")
+	}
+}
+
+// Returns number of unclosed delimiters and comments in text. Delimiters in strings and comments aren't counted.
+func delimiterDepth(text string) int {
+	depth := 0
+	commentDepth := 0
+	inString := false
+	inLineComment := false
+
+	for i := 0; i < len(text); i++ {
+		switch {
+		case inLineComment:
+			inLineComment = text[i] != '\n'
+
+		case commentDepth > 0:
+			if strings.HasPrefix(text[i:], "*/") {
+				commentDepth--
+				i++
+			} else if strings.HasPrefix(text[i:], "/*") {
+				commentDepth++
+				i++
+			}
+
+		case inString:
+			if text[i] == '\\' {
+				i++
+			} else if text[i] == '"' || text[i] == '\n' {
+				inString = false
+			}
+
+		case text[i] == '"':
+			inString = true
+
+		case strings.HasPrefix(text[i:], "//"):
+			inLineComment = true
+			i++
+
+		case strings.HasPrefix(text[i:], "/*"):
+			commentDepth++
+			i++
+
+		case text[i] == '{' || text[i] == '[' || text[i] == '(':
+			depth++
+
+		case text[i] == '}' || text[i] == ']' || text[i] == ')':
+			depth--
+		}
+	}
+
+	return depth + commentDepth
+}
+
+// Executes command starting with a colon. Returns false if the session should end.
+func (r *Repl) executeCommand(command string) bool {
+	name, argument, _ := strings.Cut(command, " ")
+
+	switch name {
+	case ":help":
+		fmt.Println(":type [expression]  Prints type of expression.")
+		fmt.Println(":tokens             Toggles printing of lexed tokens.")
+		fmt.Println(":tree               Toggles drawing of abstract syntax trees.")
+		fmt.Println(":quit               Ends the session.")
+
+	case ":type":
+		r.printType(argument)
+
+	case ":tokens":
+		r.printTokens = !r.printTokens
+		fmt.Printf("Printing of tokens is %s.\n", enabledToString(r.printTokens))
+
+	case ":tree":
+		r.drawTree = !r.drawTree
+		fmt.Printf("Drawing of syntax trees is %s.\n", enabledToString(r.drawTree))
+
+	case ":quit", ":exit":
+		return false
+
+	default:
+		logger.Error("Unknown command " + name + ". Use :help to list commands.")
+	}
+
+	return true
+}
+
+func enabledToString(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// Executes declarations and statements of input. Values of bare expressions and called functions are printed.
+func (r *Repl) execute(input string) {
+	tokens, ok := r.analyze(input, false)
+	if !ok {
+		return
+	}
+
+	tree, ok := r.parse(tokens)
+	if !ok {
+		return
+	}
+
+	// Generate code
+	functionCount := r.codeGenerator.FunctionCount()
+	errorCount := r.codeGenerator.ErrorCount
+
+	r.codeGenerator.GenerateInput(tree)
+	r.parser.ReserveFunctionNumbers(r.codeGenerator.FunctionCount())
+
+	if r.codeGenerator.ErrorCount != errorCount {
+		return
+	}
+
+	r.virtualMachine.ExecuteInput(r.codeGenerator.Constants, r.codeGenerator.FunctionsInstructions, r.codeGenerator.InputFunctions(functionCount), r.codeGenerator.GlobalsInstructions)
+}
+
+// Prints type of expression without executing it.
+func (r *Repl) printType(expression string) {
+	if len(strings.TrimSpace(expression)) == 0 {
+		logger.Error("Command :type requires an expression, for example :type 1 + 2.")
+		return
+	}
+
+	tokens, ok := r.analyze(expression, true)
+	if !ok {
+		return
+	}
+
+	tree, ok := r.parse(tokens)
+	if !ok {
+		return
+	}
+
+	// Expression is the argument of it's conversion to string
+	conversion := tree.Value.(*parser.ModuleNode).Statements.Statements[0].Value.(*parser.FunctionCallNode)
+	fmt.Println(conversion.ArgumentTypes[0])
+}
+
+// Lexes input and analyzes it's syntax. Bare expressions are wrapped in a conversion to string, which prints their value.
+func (r *Repl) analyze(input string, isExpression bool) ([]*lexer.Token, bool) {
+	// Lexer requires last line to be terminated
+	if !strings.HasSuffix(input, "\n") {
+		input += "\n"
+	}
+
+	lex := lexer.NewLexerFromFile(REPL_MODULE, io.NopCloser(strings.NewReader(input)))
+	tokens := lex.Lex()
+
+	if lex.ErrorCount != 0 {
+		return nil, false
+	}
+
+	if isExpression || isBareExpression(tokens) {
+		tokens = wrapExpression(tokens)
+	}
+
+	tokens = r.syntaxAnalyzer.AnalyzeInput(tokens)
+
+	if r.printTokens {
+		printTokens(tokens)
+		fmt.Println()
+	}
+
+	return tokens, r.syntaxAnalyzer.ErrorCount == 0
+}
+
+func (r *Repl) parse(tokens []*lexer.Token) (*parser.Node, bool) {
+	tree := r.parser.ParseInput(tokens)
+
+	if r.parser.ErrorCount != 0 {
+		return nil, false
+	}
+
+	if r.drawTree {
+		parser.Visualize(tree)
+		fmt.Println()
+	}
+
+	return tree, true
+}
+
+// Returns tokens of input without StartOfFile, EndOfFile and surrounding EOCs.
+func inputTokens(tokens []*lexer.Token) []*lexer.Token {
+	start, end := 1, len(tokens)-1
+
+	for start < end && tokens[start].TokenType == lexer.TT_EndOfCommand {
+		start++
+	}
+
+	for end > start && tokens[end-1].TokenType == lexer.TT_EndOfCommand {
+		end--
+	}
+
+	return tokens[start:end]
+}
+
+// Checks if input is an expression, which isn't a valid statement. Calls of functions are statements.
+func isBareExpression(tokens []*lexer.Token) bool {
+	tokens = inputTokens(tokens)
+
+	if len(tokens) == 0 {
+		return false
+	}
+
+	// Assignments and declarations with assigned values
+	depth := 0
+	for _, token := range tokens {
+		if token.TokenType.IsOpeningDelimiter() {
+			depth++
+		} else if token.TokenType.IsClosingDelimiter() {
+			depth--
+		} else if depth == 0 && token.TokenType.IsAssignKeyword() {
+			return false
+		}
+	}
+
+	first := tokens[0].TokenType
+	followedByParenthesis := len(tokens) > 1 && tokens[1].TokenType == lexer.TT_DL_ParenthesisOpen
+
+	switch first {
+	// Lambdas and conversions
+	case lexer.TT_KW_fun, lexer.TT_KW_int, lexer.TT_KW_flt, lexer.TT_KW_str:
+		return followedByParenthesis
+
+	case lexer.TT_Identifier:
+		// Declaration of struct or enum variable
+		if len(tokens) > 1 && tokens[1].TokenType == lexer.TT_Identifier {
+			return false
+		}
+		if len(tokens) > 2 && tokens[1].TokenType == lexer.TT_OP_QuestionMark && tokens[2].TokenType == lexer.TT_Identifier {
+			return false
+		}
+
+		return !isCall(tokens)
+	}
+
+	return first.IsLiteral() || first.IsUnaryOperator() || first == lexer.TT_DL_ParenthesisOpen || first == lexer.TT_DL_BracketOpen
+}
+
+// Checks if tokens are a call of function or method, which receiver is a variable or it's field.
+func isCall(tokens []*lexer.Token) bool {
+	if tokens[len(tokens)-1].TokenType != lexer.TT_DL_ParenthesisClose {
+		return false
+	}
+
+	// Find opening parenthesis of arguments
+	depth := 0
+	opening := len(tokens) - 1
+
+	for ; opening >= 0; opening-- {
+		if tokens[opening].TokenType.IsClosingDelimiter() {
+			depth++
+		} else if tokens[opening].TokenType.IsOpeningDelimiter() {
+			depth--
+		}
+
+		if depth == 0 {
+			break
+		}
+	}
+
+	// Called function is identifiers separated by dots
+	if opening < 1 || opening%2 == 0 {
+		return false
+	}
+
+	for i := 0; i < opening; i++ {
+		if i%2 == 0 && tokens[i].TokenType != lexer.TT_Identifier || i%2 == 1 && tokens[i].TokenType != lexer.TT_OP_Dot {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Wraps tokens of expression in a conversion to string: str(expression).
+func wrapExpression(tokens []*lexer.Token) []*lexer.Token {
+	expression := inputTokens(tokens)
+
+	if len(expression) == 0 {
+		return tokens
+	}
+
+	start := expression[0].Position
+	end := expression[len(expression)-1].Position
+
+	wrapped := []*lexer.Token{tokens[0]}
+	wrapped = append(wrapped, &lexer.Token{Position: start, TokenType: lexer.TT_Identifier, Value: "str"}, &lexer.Token{Position: start, TokenType: lexer.TT_DL_ParenthesisOpen, Value: "("})
+	wrapped = append(wrapped, expression...)
+	wrapped = append(wrapped, &lexer.Token{Position: end, TokenType: lexer.TT_DL_ParenthesisClose, Value: ")"}, &lexer.Token{Position: end, TokenType: lexer.TT_EndOfCommand, Value: ""})
+
+	return append(wrapped, tokens[len(tokens)-1])
+}
@@ -0,0 +1,44 @@
+package std
+
+import (
+	"embed"
+	"io/fs"
+	"strings"
+)
+
+const PREFIX = "std/"
+const SOURCE_EXTENSION = ".neco"
+
+//go:embed *.neco
+var modules embed.FS
+
+// Checks if import path refers to a module of the standard library.
+func IsModule(importPath string) bool {
+	if !strings.HasPrefix(importPath, PREFIX) {
+		return false
+	}
+
+	_, err := fs.Stat(modules, fileName(importPath))
+	return err == nil
+}
+
+// Opens source of standard library module.
+func Open(importPath string) (fs.File, error) {
+	return modules.Open(fileName(importPath))
+}
+
+// Lists names of all standard library modules.
+func Modules() []string {
+	entries, _ := modules.ReadDir(".")
+	names := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		names = append(names, PREFIX+strings.TrimSuffix(entry.Name(), SOURCE_EXTENSION))
+	}
+
+	return names
+}
+
+func fileName(importPath string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(importPath, PREFIX), SOURCE_EXTENSION) + SOURCE_EXTENSION
+}
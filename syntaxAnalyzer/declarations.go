@@ -79,6 +79,56 @@ func (sn *SyntaxAnalyzer) analyzeFunctionDeclaration() {
 		sn.consume()
 	}
 
+	// Type parameters are types only inside of the function
+	typeParameters := sn.analyzeTypeParameters()
+	sn.analyzeFunctionHeaderAndBody()
+	sn.forgetTypeParameters(typeParameters)
+}
+
+// Analyzes type parameters of generic function or struct and registers them as types. Returns the newly registered types.
+func (sn *SyntaxAnalyzer) analyzeTypeParameters() []string {
+	typeParameters := []string{}
+
+	if sn.peek().TokenType != lexer.TT_OP_Lower {
+		return typeParameters
+	}
+	sn.consume() // <
+
+	for sn.peek().TokenType != lexer.TT_OP_Greater && sn.peek().TokenType != lexer.TT_EndOfCommand && sn.peek().TokenType != lexer.TT_EndOfFile {
+		// Collect identifier
+		if sn.peek().TokenType != lexer.TT_Identifier {
+			sn.newError(sn.peek(), "Expected type parameter identifier, found \""+sn.consume().String()+"\" instead.")
+		} else if !sn.customTypes[sn.peek().Value] {
+			sn.customTypes[sn.peek().Value] = true
+			typeParameters = append(typeParameters, sn.consume().Value)
+		} else {
+			sn.consume()
+		}
+
+		if sn.peek().TokenType == lexer.TT_DL_Comma {
+			sn.consume()
+		} else if sn.peek().TokenType != lexer.TT_OP_Greater {
+			sn.newError(sn.peek(), "Expected \",\" or \">\" after type parameter, found \""+sn.consume().String()+"\" instead.")
+		}
+	}
+
+	// Consume closing token
+	if sn.peek().TokenType == lexer.TT_OP_Greater {
+		sn.consume()
+	} else {
+		sn.newError(sn.peek(), "Expected \">\" after type parameters.")
+	}
+
+	return typeParameters
+}
+
+func (sn *SyntaxAnalyzer) forgetTypeParameters(typeParameters []string) {
+	for _, typeParameter := range typeParameters {
+		delete(sn.customTypes, typeParameter)
+	}
+}
+
+func (sn *SyntaxAnalyzer) analyzeFunctionHeaderAndBody() {
 	// Check for opening parenthesis
 	if sn.peek().TokenType != lexer.TT_DL_ParenthesisOpen {
 		sn.newError(sn.peekPrevious(), "Expected opening parenthesis after function identifier, found \""+sn.peek().String()+"\" instead.")
@@ -107,10 +157,12 @@ func (sn *SyntaxAnalyzer) analyzeFunctionDeclaration() {
 			sn.newError(sn.peek(), "Expected return type after keyword ->, found \""+sn.peek().String()+"\" instead.")
 		} else {
 			// Check if type is valid
-			if !sn.peek().TokenType.IsVariableType() && !(sn.peek().TokenType == lexer.TT_Identifier && sn.customTypes[sn.peek().Value]) {
+			if sn.isTypeStart() {
+				sn.analyzeType()
+			} else {
 				sn.newError(sn.peek(), "Expected return type after keyword ->, found \""+sn.peek().String()+"\" instead.")
+				sn.consume()
 			}
-			sn.consume()
 		}
 	}
 
@@ -149,10 +201,10 @@ func (sn *SyntaxAnalyzer) analyzeFunctionDeclaration() {
 func (sn *SyntaxAnalyzer) analyzeParameters() {
 	for sn.peek().TokenType != lexer.TT_EndOfFile && sn.peek().TokenType != lexer.TT_EndOfCommand {
 		// Check type
-		if !sn.peek().TokenType.IsVariableType() {
+		if !sn.isTypeStart() {
 			sn.newError(sn.peek(), "Expected variable type at start of parameters, found \""+sn.peek().String()+"\" instead.")
 		} else {
-			sn.consume()
+			sn.analyzeType()
 		}
 
 		// Check identifier
@@ -167,7 +219,7 @@ func (sn *SyntaxAnalyzer) analyzeParameters() {
 			sn.consume()
 
 			// Multiple identifiers
-			for sn.peek().TokenType == lexer.TT_Identifier {
+			for sn.peek().TokenType == lexer.TT_Identifier && !sn.customTypes[sn.peek().Value] {
 				sn.consume()
 
 				if sn.peek().TokenType == lexer.TT_DL_Comma {
@@ -203,11 +255,22 @@ func (sn *SyntaxAnalyzer) analyzeParameters() {
 	}
 }
 
+func (sn *SyntaxAnalyzer) isTypeStart() bool {
+	return sn.peek().TokenType.IsVariableType() || sn.peek().TokenType == lexer.TT_KW_fun || sn.peek().TokenType == lexer.TT_Identifier && sn.customTypes[sn.peek().Value]
+}
+
 func (sn *SyntaxAnalyzer) analyzeType() {
 	if sn.peek().TokenType.IsCompositeType() {
 		sn.analyzeCompositeType()
+	} else if sn.peek().TokenType == lexer.TT_KW_fun {
+		sn.analyzeFunctionType()
 	} else {
 		sn.consume()
+
+		// Type arguments of generic struct
+		if sn.peekPrevious().TokenType == lexer.TT_Identifier && sn.peek().TokenType == lexer.TT_OP_Lower {
+			sn.analyzeTypeArguments()
+		}
 	}
 
 	if sn.peek().TokenType == lexer.TT_OP_QuestionMark {
@@ -217,7 +280,7 @@ func (sn *SyntaxAnalyzer) analyzeType() {
 
 func (sn *SyntaxAnalyzer) analyzeCompositeType() {
 	// Consume type
-	sn.consume()
+	isMap := sn.consume().TokenType == lexer.TT_KW_map
 
 	// Consume opening token
 	if sn.peek().TokenType == lexer.TT_OP_Lower {
@@ -227,12 +290,27 @@ func (sn *SyntaxAnalyzer) analyzeCompositeType() {
 	}
 
 	// Analyze sub-type
-	if sn.peek().TokenType.IsVariableType() {
+	if sn.isTypeStart() {
 		sn.analyzeType()
 	} else {
 		sn.newError(sn.peek(), "Expected subtype in composite data type.")
 	}
 
+	// Analyze value type of map
+	if isMap {
+		if sn.peek().TokenType == lexer.TT_DL_Comma {
+			sn.consume()
+		} else {
+			sn.newError(sn.peek(), "Expected \",\" after map key type.")
+		}
+
+		if sn.isTypeStart() {
+			sn.analyzeType()
+		} else {
+			sn.newError(sn.peek(), "Expected value type in map data type.")
+		}
+	}
+
 	// Consume closing token
 	if sn.peek().TokenType == lexer.TT_OP_Greater {
 		sn.consume()
@@ -240,3 +318,73 @@ func (sn *SyntaxAnalyzer) analyzeCompositeType() {
 		sn.newError(sn.peek(), "Expected \">\" after composite data type sub-type.")
 	}
 }
+
+func (sn *SyntaxAnalyzer) analyzeTypeArguments() {
+	sn.consume() // <
+
+	for sn.peek().TokenType != lexer.TT_OP_Greater && sn.peek().TokenType != lexer.TT_EndOfCommand && sn.peek().TokenType != lexer.TT_EndOfFile {
+		if sn.isTypeStart() {
+			sn.analyzeType()
+		} else {
+			sn.newError(sn.peek(), "Expected type argument, found \""+sn.consume().String()+"\" instead.")
+		}
+
+		if sn.peek().TokenType == lexer.TT_DL_Comma {
+			sn.consume()
+		} else if sn.peek().TokenType != lexer.TT_OP_Greater {
+			sn.newError(sn.peek(), "Expected \",\" or \">\" after type argument, found \""+sn.consume().String()+"\" instead.")
+		}
+	}
+
+	// Consume closing token
+	if sn.peek().TokenType == lexer.TT_OP_Greater {
+		sn.consume()
+	} else {
+		sn.newError(sn.peek(), "Expected \">\" after type arguments.")
+	}
+}
+
+func (sn *SyntaxAnalyzer) analyzeFunctionType() {
+	sn.consume() // fun
+
+	// Consume opening parenthesis
+	if sn.peek().TokenType == lexer.TT_DL_ParenthesisOpen {
+		sn.consume()
+	} else {
+		sn.newError(sn.peek(), "Expected \"(\" after keyword fun in function type.")
+	}
+
+	// Analyze parameter types
+	for sn.peek().TokenType != lexer.TT_DL_ParenthesisClose && sn.peek().TokenType != lexer.TT_EndOfCommand && sn.peek().TokenType != lexer.TT_EndOfFile {
+		if sn.isTypeStart() {
+			sn.analyzeType()
+		} else {
+			sn.newError(sn.peek(), "Expected parameter type in function type, found \""+sn.consume().String()+"\" instead.")
+		}
+
+		if sn.peek().TokenType == lexer.TT_DL_Comma {
+			sn.consume()
+		} else if sn.peek().TokenType != lexer.TT_DL_ParenthesisClose {
+			sn.newError(sn.peek(), "Expected \",\" or \")\" after parameter type, found \""+sn.consume().String()+"\" instead.")
+		}
+	}
+
+	// Consume closing parenthesis
+	if sn.peek().TokenType == lexer.TT_DL_ParenthesisClose {
+		sn.consume()
+	} else {
+		sn.newError(sn.peek(), "Expected \")\" after parameter types of function type.")
+		return
+	}
+
+	// Analyze return type
+	if sn.peek().TokenType == lexer.TT_KW_returns {
+		sn.consume()
+
+		if sn.isTypeStart() {
+			sn.analyzeType()
+		} else {
+			sn.newError(sn.peek(), "Expected return type after keyword ->, found \""+sn.peek().String()+"\" instead.")
+		}
+	}
+}
@@ -7,7 +7,7 @@ import (
 func (sn *SyntaxAnalyzer) registerEnumsAndStructs() {
 	for sn.peek().TokenType != lexer.TT_EndOfFile {
 		// Register enum
-		if sn.peek().TokenType == lexer.TT_KW_enum || sn.peek().TokenType == lexer.TT_KW_struct {
+		if sn.peek().TokenType == lexer.TT_KW_enum || sn.peek().TokenType == lexer.TT_KW_struct || sn.peek().TokenType == lexer.TT_KW_class {
 			sn.consume()
 
 			if sn.peek().TokenType == lexer.TT_Identifier {
@@ -20,11 +20,11 @@ func (sn *SyntaxAnalyzer) registerEnumsAndStructs() {
 }
 
 func (sn *SyntaxAnalyzer) analyzeStructDefinition() {
-	sn.consume()
+	keyword := sn.consume()
 
 	// Check identifier
 	if sn.peek().TokenType != lexer.TT_Identifier {
-		sn.newError(sn.peek(), "Expected identifier after keyword struct.")
+		sn.newError(sn.peek(), "Expected identifier after keyword "+keyword.String()+".")
 
 		if sn.peek().TokenType != lexer.TT_DL_BraceOpen {
 			sn.consume()
@@ -33,8 +33,12 @@ func (sn *SyntaxAnalyzer) analyzeStructDefinition() {
 		sn.consume()
 	}
 
+	// Type parameters are types only inside of the struct
+	typeParameters := sn.analyzeTypeParameters()
+	defer sn.forgetTypeParameters(typeParameters)
+
 	// Check opening brace
-	if !sn.lookFor(lexer.TT_DL_BraceOpen, "struct identifier", "opening brace", false) {
+	if !sn.lookFor(lexer.TT_DL_BraceOpen, keyword.String()+" identifier", "opening brace", false) {
 		return
 	}
 	sn.consume()
@@ -43,9 +47,12 @@ func (sn *SyntaxAnalyzer) analyzeStructDefinition() {
 	for sn.peek().TokenType != lexer.TT_EndOfFile {
 		sn.consumeEOCs()
 
-		// Property
-		if sn.peek().TokenType.IsVariableType() || sn.peek().TokenType == lexer.TT_Identifier && sn.customTypes[sn.peek().Value] {
-			sn.consume()
+		// Method
+		if keyword.TokenType == lexer.TT_KW_class && sn.peek().TokenType == lexer.TT_KW_fun && sn.peekNext().TokenType == lexer.TT_Identifier {
+			sn.analyzeFunctionDeclaration()
+			// Property
+		} else if sn.isTypeStart() {
+			sn.analyzeType()
 
 			// Valid identifier
 			if sn.peek().TokenType == lexer.TT_Identifier {
@@ -119,11 +126,16 @@ func (sn *SyntaxAnalyzer) analyzeEnumDefinition() {
 		// Enum name
 		if sn.peek().TokenType == lexer.TT_Identifier {
 			identifier := sn.consume()
+			hasPayload := false
 
 			// Set custom value
 			if sn.peek().TokenType == lexer.TT_KW_Assign {
 				sn.consume()
 				sn.analyzeExpression()
+				// Payload
+			} else if sn.peek().TokenType == lexer.TT_DL_ParenthesisOpen {
+				sn.analyzeVariantPayload()
+				hasPayload = true
 			}
 
 			// Allow only EOCs and } after enum name
@@ -132,6 +144,9 @@ func (sn *SyntaxAnalyzer) analyzeEnumDefinition() {
 			} else if sn.peek().TokenType == lexer.TT_DL_BraceClose {
 				sn.consume()
 				break
+				// Variants with payload can be separated by commas
+			} else if sn.peek().TokenType == lexer.TT_DL_Comma && hasPayload {
+				sn.consume()
 				// , instead of ;
 			} else if sn.peek().TokenType == lexer.TT_DL_Comma {
 				sn.newError(sn.consume(), "Unexpected token \",\" after enum name. Did you want \";\"?")
@@ -163,3 +178,36 @@ func (sn *SyntaxAnalyzer) analyzeEnumDefinition() {
 		}
 	}
 }
+
+func (sn *SyntaxAnalyzer) analyzeVariantPayload() {
+	sn.consume() // (
+
+	for sn.peek().TokenType != lexer.TT_DL_ParenthesisClose && sn.peek().TokenType != lexer.TT_EndOfCommand && sn.peek().TokenType != lexer.TT_EndOfFile {
+		// Check type
+		if sn.isTypeStart() {
+			sn.analyzeType()
+		} else {
+			sn.newError(sn.peek(), "Expected payload type, found \""+sn.consume().String()+"\" instead.")
+		}
+
+		// Check identifier
+		if sn.peek().TokenType == lexer.TT_Identifier {
+			sn.consume()
+		} else {
+			sn.newError(sn.peek(), "Expected payload identifier after payload type, found \""+sn.peek().String()+"\" instead.")
+		}
+
+		if sn.peek().TokenType == lexer.TT_DL_Comma {
+			sn.consume()
+		} else if sn.peek().TokenType != lexer.TT_DL_ParenthesisClose {
+			sn.newError(sn.peek(), "Expected \",\" or \")\" after payload, found \""+sn.consume().String()+"\" instead.")
+		}
+	}
+
+	// Consume closing parenthesis
+	if sn.peek().TokenType == lexer.TT_DL_ParenthesisClose {
+		sn.consume()
+	} else {
+		sn.newError(sn.peek(), "Expected \")\" after enum variant payload.")
+	}
+}
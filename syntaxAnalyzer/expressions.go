@@ -9,6 +9,11 @@ func (sn *SyntaxAnalyzer) analyzeRestOfExpression() {
 		sn.consume()
 	}
 
+	// Colon of a slice without end index
+	if sn.peek().TokenType == lexer.TT_DL_Colon && sn.peekNext().TokenType == lexer.TT_DL_BracketClose {
+		return
+	}
+
 	if sn.peek().TokenType.IsBinaryOperator() {
 		sn.consume()
 		sn.analyzeExpression()
@@ -89,6 +94,14 @@ func (sn *SyntaxAnalyzer) analyzeExpression() {
 		return
 	}
 
+	// Lambda
+	if sn.peek().TokenType == lexer.TT_KW_fun {
+		sn.consume()
+		sn.analyzeFunctionHeaderAndBody()
+		sn.analyzeRestOfExpression()
+		return
+	}
+
 	// Identifier
 	if sn.peek().TokenType == lexer.TT_Identifier {
 		sn.analyzeIdentifier()
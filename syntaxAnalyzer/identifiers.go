@@ -38,6 +38,11 @@ func (sn *SyntaxAnalyzer) analyzeIdentifierStatement() {
 	if sn.peek().TokenType.IsAssignKeyword() {
 		sn.analyzeAssignment()
 	} else if sn.peek().TokenType == lexer.TT_EndOfCommand {
+		// Method call
+		if sn.peekPrevious().TokenType == lexer.TT_DL_ParenthesisClose {
+			return
+		}
+
 		// Missing assign keyword
 		sn.newErrorFromTo(sn.peek().Position.StartLine, startOfStatement.Position.StartChar, sn.peekPrevious().Position.EndChar, "Expression can't be a statement.")
 	} else if sn.peek().TokenType.IsOperator() {
@@ -55,7 +60,12 @@ func (sn *SyntaxAnalyzer) analyzeIdentifierStatement() {
 }
 
 func (sn *SyntaxAnalyzer) analyzeIdentifier() {
-	sn.consume() // ID
+	identifier := sn.consume() // ID
+
+	// Type argument of built-in function fromJson
+	if identifier.Value == "fromJson" && sn.peek().TokenType == lexer.TT_OP_Lower {
+		sn.analyzeTypeArguments()
+	}
 
 	// List element
 	if sn.peek().TokenType == lexer.TT_DL_BracketOpen {
@@ -63,6 +73,15 @@ func (sn *SyntaxAnalyzer) analyzeIdentifier() {
 
 		sn.analyzeExpression()
 
+		// Slice without start or end index
+		if sn.peek().TokenType == lexer.TT_DL_Colon {
+			sn.consume() // :
+
+			if sn.peek().TokenType != lexer.TT_DL_BracketClose {
+				sn.analyzeExpression()
+			}
+		}
+
 		// Missing closing bracket
 		if sn.peek().TokenType != lexer.TT_DL_BracketClose {
 			sn.newError(openingBracket, "Index is missing closing bracket.")
@@ -15,7 +15,7 @@ func (sn *SyntaxAnalyzer) analyzeForEachLoop() {
 	}
 
 	// Check type
-	if !sn.peek().TokenType.IsVariableType() {
+	if !sn.isTypeStart() {
 		sn.newError(sn.peek(), "Expected variable type, found \""+sn.peek().String()+"\" instead.")
 	} else {
 		sn.analyzeType()
@@ -28,6 +28,25 @@ func (sn *SyntaxAnalyzer) analyzeForEachLoop() {
 		sn.consume()
 	}
 
+	// Value variable of map entries
+	if sn.peek().TokenType == lexer.TT_DL_Comma {
+		sn.consume()
+
+		// Check type
+		if !sn.isTypeStart() {
+			sn.newError(sn.peek(), "Expected variable type, found \""+sn.peek().String()+"\" instead.")
+		} else {
+			sn.analyzeType()
+		}
+
+		// Check variable identifier
+		if sn.peek().TokenType != lexer.TT_Identifier {
+			sn.newError(sn.peek(), "Expected variable identifier after variable type, found \""+sn.peek().String()+"\" instead.")
+		} else {
+			sn.consume()
+		}
+	}
+
 	// Check keyword in
 	if sn.peek().TokenType != lexer.TT_OP_In {
 		sn.newError(sn.peek(), "Expected keyword in after variable identifier, found \""+sn.peek().String()+"\" instead.")
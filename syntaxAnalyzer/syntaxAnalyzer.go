@@ -7,7 +7,6 @@ import (
 	"github.com/DanielNos/neco/errors"
 	"github.com/DanielNos/neco/lexer"
 	"github.com/DanielNos/neco/logger"
-	"github.com/DanielNos/neco/utils"
 )
 
 type SyntaxAnalyzer struct {
@@ -15,6 +14,7 @@ type SyntaxAnalyzer struct {
 	tokenIndex int
 
 	customTypes map[string]bool
+	modules     map[string]bool // Names of imported modules
 
 	ErrorCount      uint
 	totalErrorCount uint
@@ -23,6 +23,7 @@ type SyntaxAnalyzer struct {
 func NewSyntaxAnalyzer(tokens []*lexer.Token, previousErrors uint) SyntaxAnalyzer {
 	return SyntaxAnalyzer{tokens,
 		0,
+		map[string]bool{"Error": true},
 		map[string]bool{},
 		0,
 		previousErrors,
@@ -128,12 +129,22 @@ func (sn *SyntaxAnalyzer) Analyze() []*lexer.Token {
 	}
 
 	sn.registerEnumsAndStructs()
+	sn.mergeQualifiedIdentifiers()
 	sn.resetTokenPointer()
 	sn.analyzeStatementList(false)
 
 	return sn.tokens
 }
 
+// Analyzes input of an interactive session. Types declared by previous inputs stay declared.
+func (sn *SyntaxAnalyzer) AnalyzeInput(tokens []*lexer.Token) []*lexer.Token {
+	sn.tokens = tokens
+	sn.tokenIndex = 0
+	sn.ErrorCount = 0
+
+	return sn.Analyze()
+}
+
 func (sn *SyntaxAnalyzer) lookFor(tokenType lexer.TokenType, afterWhat, name string, optional bool) bool {
 	if sn.peek().TokenType != tokenType {
 		// Skip 1 EOC
@@ -180,8 +191,14 @@ func (sn *SyntaxAnalyzer) analyzeStatement(isScope bool) bool {
 
 	switch sn.peek().TokenType {
 
-	case lexer.TT_KW_fun: // Function declaration
-		sn.analyzeFunctionDeclaration()
+	case lexer.TT_KW_fun:
+		// Variable of function type
+		if sn.peekNext().TokenType == lexer.TT_DL_ParenthesisOpen {
+			sn.analyzeVariableDeclaration(false)
+			// Function declaration
+		} else {
+			sn.analyzeFunctionDeclaration()
+		}
 
 	case lexer.TT_Identifier: // Identifiers
 		sn.analyzeIdentifierStatement()
@@ -207,7 +224,7 @@ func (sn *SyntaxAnalyzer) analyzeStatement(isScope bool) bool {
 	case lexer.TT_DL_BraceOpen: // Enter scope
 		sn.analyzeScope()
 
-	case lexer.TT_KW_struct: // Struct
+	case lexer.TT_KW_struct, lexer.TT_KW_class: // Struct, class
 		sn.analyzeStructDefinition()
 
 	case lexer.TT_KW_enum: // Enum
@@ -234,7 +251,7 @@ func (sn *SyntaxAnalyzer) analyzeStatement(isScope bool) bool {
 	case lexer.TT_KW_forEach: // ForEach loop
 		sn.analyzeForEachLoop()
 
-	case lexer.TT_KW_break: // Break
+	case lexer.TT_KW_break, lexer.TT_KW_continue: // Break, continue
 		sn.consume()
 
 	case lexer.TT_KW_return: // Return
@@ -251,6 +268,22 @@ func (sn *SyntaxAnalyzer) analyzeStatement(isScope bool) bool {
 	case lexer.TT_KW_match: // Match
 		sn.analyzeMatchStatement(false)
 
+	case lexer.TT_KW_try: // Try
+		sn.analyzeTryStatement()
+
+	case lexer.TT_KW_catch: // Catch
+		sn.newError(sn.peek(), "Catch block is missing a try block.")
+		sn.analyzeCatchBlock()
+
+	case lexer.TT_KW_throw: // Throw
+		sn.consume()
+
+		if sn.peek().TokenType == lexer.TT_EndOfCommand || sn.peek().TokenType == lexer.TT_EndOfFile {
+			sn.newError(sn.peek(), "Expected thrown expression after keyword throw.")
+		} else {
+			sn.analyzeExpression()
+		}
+
 	case lexer.TT_KW_default:
 		return false
 
@@ -258,6 +291,10 @@ func (sn *SyntaxAnalyzer) analyzeStatement(isScope bool) bool {
 		sn.analyzeImport()
 		return false
 
+	case lexer.TT_KW_pub: // Public declaration
+		sn.analyzePublicDeclaration(isScope)
+		return false
+
 	case lexer.TT_StartOfFile, lexer.TT_EndOfFile: // Ignore file markers
 		sn.consume()
 		return false
@@ -308,10 +345,114 @@ func (sn *SyntaxAnalyzer) analyzeImport() {
 		sn.newError(sn.peek(), "Expected file identifier after import.")
 		return
 	}
+	sn.consume()
+}
+
+func (sn *SyntaxAnalyzer) analyzePublicDeclaration(isScope bool) {
+	pub := sn.consume()
+
+	// Only global declarations can be public
+	if isScope {
+		sn.newError(pub, "Keyword pub can be used only on global declarations.")
+	}
+
+	switch sn.peek().TokenType {
+	case lexer.TT_KW_fun, lexer.TT_KW_struct, lexer.TT_KW_class, lexer.TT_KW_enum, lexer.TT_KW_const:
+		sn.analyzeStatement(isScope)
+
+	default:
+		if sn.isTypeStart() {
+			sn.analyzeStatement(isScope)
+		} else {
+			sn.newError(sn.peek(), "Expected declaration after keyword pub.")
+		}
+	}
+}
+
+// Collects paths of imported modules. Path segments separated by slashes are merged in to a single identifier token.
+// Returns tokens with merged paths and the path tokens.
+func CollectImports(tokens []*lexer.Token) ([]*lexer.Token, []*lexer.Token) {
+	merged := make([]*lexer.Token, 0, len(tokens))
+	imports := []*lexer.Token{}
+
+	for i := 0; i < len(tokens); i++ {
+		merged = append(merged, tokens[i])
+
+		if tokens[i].TokenType != lexer.TT_KW_import || i+1 == len(tokens) || tokens[i+1].TokenType != lexer.TT_Identifier {
+			continue
+		}
 
-	// Tokenize imported file
-	lexer := lexer.NewLexer(sn.consume().Value)
-	importedTokens := lexer.Lex()
+		// Collect path segments
+		i++
+		path := &lexer.Token{Position: tokens[i].Position, TokenType: lexer.TT_Identifier, Value: tokens[i].Value}
+
+		for i+2 < len(tokens) && tokens[i+1].TokenType == lexer.TT_OP_Divide && tokens[i+2].TokenType == lexer.TT_Identifier {
+			path.Value += "/" + tokens[i+2].Value
+			path.Position = path.Position.Combine(tokens[i+2].Position)
+			i += 2
+		}
+
+		merged = append(merged, path)
+		imports = append(imports, path)
+	}
+
+	return merged, imports
+}
+
+// Registers an imported module and types declared by it, so they can be used by analyzed tokens.
+func (sn *SyntaxAnalyzer) ImportModule(name string, tokens []*lexer.Token) {
+	sn.modules[name] = true
+
+	for i := 0; i < len(tokens)-1; i++ {
+		if tokens[i].TokenType == lexer.TT_KW_enum || tokens[i].TokenType == lexer.TT_KW_struct || tokens[i].TokenType == lexer.TT_KW_class {
+			if tokens[i+1].TokenType == lexer.TT_Identifier {
+				sn.customTypes[tokens[i+1].Value] = true
+			}
+		}
+	}
+}
+
+// Prefixes identifiers of enums and structs declared by analyzed tokens with module name,
+// so they don't collide with types of other modules.
+func (sn *SyntaxAnalyzer) QualifyTypes(moduleName string) {
+	// Collect declared types
+	declaredTypes := map[string]bool{}
+
+	for i := 0; i < len(sn.tokens)-1; i++ {
+		if sn.tokens[i].TokenType == lexer.TT_KW_enum || sn.tokens[i].TokenType == lexer.TT_KW_struct || sn.tokens[i].TokenType == lexer.TT_KW_class {
+			if sn.tokens[i+1].TokenType == lexer.TT_Identifier {
+				declaredTypes[sn.tokens[i+1].Value] = true
+			}
+		}
+	}
+
+	// Rename all uses of the types, properties and enum constants with the same identifier are skipped
+	for i, token := range sn.tokens {
+		if token.TokenType == lexer.TT_Identifier && declaredTypes[token.Value] && (i == 0 || sn.tokens[i-1].TokenType != lexer.TT_OP_Dot) {
+			token.Value = moduleName + "." + token.Value
+		}
+	}
+}
+
+// Merges identifiers of imported modules with identifiers following them in to a single qualified identifier.
+func (sn *SyntaxAnalyzer) mergeQualifiedIdentifiers() {
+	merged := make([]*lexer.Token, 0, len(sn.tokens))
+
+	for i := 0; i < len(sn.tokens); i++ {
+		token := sn.tokens[i]
+
+		// Identifier of imported module followed by a dot and an identifier
+		if token.TokenType == lexer.TT_Identifier && sn.modules[token.Value] && i+2 < len(sn.tokens) &&
+			sn.tokens[i+1].TokenType == lexer.TT_OP_Dot && sn.tokens[i+2].TokenType == lexer.TT_Identifier &&
+			(i == 0 || sn.tokens[i-1].TokenType != lexer.TT_OP_Dot && sn.tokens[i-1].TokenType != lexer.TT_KW_import) {
+
+			merged = append(merged, &lexer.Token{Position: token.Position.Combine(sn.tokens[i+2].Position), TokenType: lexer.TT_Identifier, Value: token.Value + "." + sn.tokens[i+2].Value})
+			i += 2
+			continue
+		}
+
+		merged = append(merged, token)
+	}
 
-	sn.tokens = utils.InsertAt(sn.tokens, importedTokens, sn.tokenIndex)
+	sn.tokens = merged
 }
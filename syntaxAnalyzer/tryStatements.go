@@ -0,0 +1,52 @@
+package syntaxAnalyzer
+
+import "github.com/DanielNos/neco/lexer"
+
+func (sn *SyntaxAnalyzer) analyzeTryStatement() {
+	sn.consume() // try
+
+	// Check body
+	if !sn.lookFor(lexer.TT_DL_BraceOpen, "keyword try", "opening brace", false) {
+		return
+	}
+	sn.analyzeScope()
+
+	// Skip 1 EOC
+	if sn.peek().TokenType == lexer.TT_EndOfCommand && sn.peekNext().TokenType == lexer.TT_KW_catch {
+		sn.consume()
+	}
+
+	// Check catch block
+	if sn.peek().TokenType != lexer.TT_KW_catch {
+		sn.newError(sn.peekPrevious(), "Try block is missing a catch block.")
+		return
+	}
+
+	sn.analyzeCatchBlock()
+}
+
+func (sn *SyntaxAnalyzer) analyzeCatchBlock() {
+	sn.consume() // catch
+
+	// Check error variable
+	if sn.peek().TokenType == lexer.TT_DL_ParenthesisOpen {
+		sn.consume()
+
+		if sn.peek().TokenType == lexer.TT_Identifier {
+			sn.consume()
+		} else {
+			sn.newError(sn.peek(), "Expected error variable identifier, found \""+sn.peek().String()+"\" instead.")
+		}
+
+		if sn.peek().TokenType == lexer.TT_DL_ParenthesisClose {
+			sn.consume()
+		} else {
+			sn.newError(sn.peek(), "Expected closing parenthesis after error variable identifier, found \""+sn.peek().String()+"\" instead.")
+		}
+	}
+
+	// Check body
+	if sn.lookFor(lexer.TT_DL_BraceOpen, "keyword catch", "opening brace", false) {
+		sn.analyzeScope()
+	}
+}
@@ -1,8 +1,11 @@
 package tests
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 	"testing"
 )
 
@@ -162,7 +165,7 @@ func TestImports(t *testing.T) {
 
 	output := buildAndRun(t, "imports")
 
-	correctOutput := "Hello World!\n123 64\n"
+	correctOutput := "Hello World!\n123 64\nHi\n1\nBye!\nShadowed\n"
 	if string(output) != correctOutput {
 		t.Fatalf("Output of imports:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
 	}
@@ -172,6 +175,30 @@ func TestImports(t *testing.T) {
 	})
 }
 
+func TestImportedBuiltIn(t *testing.T) {
+	buildNeCo(t)
+
+	source := `import std/strings
+
+fun entry() {
+	printLine(str(strings.length("abc")))
+}
+`
+	path := t.TempDir() + "/importedBuiltIn.neco"
+	os.WriteFile(path, []byte(source), 0644)
+
+	output, err := exec.Command("./neco", "build", path).CombinedOutput()
+
+	if err == nil {
+		t.Fatalf("Build of built-in function accessed through module has succeeded.")
+	}
+
+	message := "Function length is a built-in function, it isn't a member of module strings."
+	if !strings.Contains(string(output), message) {
+		t.Fatalf("Output of build:\n\"%s\"\nis missing:\n\"%s\"", string(output), message)
+	}
+}
+
 func TestEnums(t *testing.T) {
 	buildNeCo(t)
 
@@ -216,3 +243,931 @@ func TestLists(t *testing.T) {
 		os.Remove("neco")
 	})
 }
+
+func TestMaps(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "maps")
+
+	correctOutput := `{"Alice": 31, "Bob": 25}
+26
+3
+true
+false
+Bob 26
+Carol 40
+Bob Carol 
+["Bob", "Carol"]
+[26, 40]
+{1: ["a", "b"]}
+{}
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of maps:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+
+	t.Cleanup(func() {
+		os.Remove("neco")
+	})
+}
+
+func TestFunctions(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "functions")
+
+	correctOutput := `42
+49
+3
+1
+15
+2 6 101 
+10
+Hello World!
+fun
+0 2 3 
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of functions:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+
+	t.Cleanup(func() {
+		os.Remove("neco")
+	})
+}
+
+func TestReturnPaths(t *testing.T) {
+	buildNeCo(t)
+
+	source := `fun sign(int n) -> int {
+	if (n < 0) {
+		printLine("negative")
+	}
+	return 1
+}
+
+fun missing(int n) -> int {
+	if (n < 0) {
+		return -1
+	}
+}
+
+fun entry() {
+	printLine(str(sign(-5) + missing(5)))
+}
+`
+	path := t.TempDir() + "/returnPaths.neco"
+	os.WriteFile(path, []byte(source), 0644)
+
+	output, err := exec.Command("./neco", "build", path).CombinedOutput()
+
+	if err == nil {
+		t.Fatalf("Build of function without return in all code paths has succeeded.")
+	}
+
+	// If statement without else doesn't return in all code paths, statements after it still can
+	message := "Function missing with return type int does not return a value in all code paths."
+	if !strings.Contains(string(output), message) {
+		t.Fatalf("Output of build:\n\"%s\"\nis missing:\n\"%s\"", string(output), message)
+	}
+
+	if strings.Contains(string(output), "Function sign") {
+		t.Fatalf("Output of build:\n\"%s\"\nreports function sign, which returns in all code paths.", string(output))
+	}
+}
+
+func TestClasses(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "classes")
+
+	correctOutput := `Hi, I'm Daniel.
+Hi, I'm Daniel. I'm 20.
+false
+22
+true
+Hi, I'm Peter. I'm 21.
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of classes:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+
+	t.Cleanup(func() {
+		os.Remove("neco")
+	})
+}
+
+func TestContinue(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "continue")
+
+	correctOutput := `1 3 5 7 9 
+1 2 4 5 6 
+1 3 4 5 
+1 3 4 6 
+01 02 10 12 20 21 
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of continue:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+
+	t.Cleanup(func() {
+		os.Remove("neco")
+	})
+}
+
+func TestGenerics(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "generics")
+
+	correctOutput := `3
+a
+one 1
+[3, 5]
+int 5
+generic five
+15
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of generics:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+
+	t.Cleanup(func() {
+		os.Remove("neco")
+	})
+}
+
+func TestTaggedUnions(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "taggedUnions")
+
+	correctOutput := `12
+7
+0
+big number 42
+number 7
+word
+end
+Rect(1, 2)
+End
+2
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of taggedUnions:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "errors")
+
+	correctOutput := `12
+-1
+5
+caught: Division by zero.
+0
+rethrown: List index out of range. List size: 3, index: 5.
+inner 0 outer
+inner 2 outer
+Integer divide by zero.
+done
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of errors:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+}
+
+func TestInterpolation(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "interpolation")
+
+	correctOutput := `Hello neco, you are 3 years old
+3
+4 is more than 3
+Sum: 3.5, list: [1, 2, 3], bool: true
+Nested: inner 6
+Point: Point{1, 2}
+Escaped: {age} {
+Literal: {} {"name": "neco"}
+Length: 4!
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of interpolation:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+}
+
+func TestInterpolationErrors(t *testing.T) {
+	buildNeCo(t)
+
+	source := `fun entry() {
+	int age = 3
+	printLine("a {} b")
+	printLine("Age: {age")
+	printLine("end")
+}
+`
+	path := t.TempDir() + "/interpolationErrors.neco"
+	os.WriteFile(path, []byte(source), 0644)
+
+	output, err := exec.Command("./neco", "build", path).CombinedOutput()
+
+	if err == nil {
+		t.Fatalf("Build of invalid interpolated expressions has succeeded.")
+	}
+
+	// Each invalid expression is reported once and the rest of the file is analyzed without errors
+	messages := []string{
+		"3:15 Interpolated expression is empty. Use \\{ to insert a brace.",
+		"4:18 Interpolated expression is missing a closing brace.",
+		"Lexical analysis failed with 2 error/s.",
+		"Passed syntax analysis.",
+		"Passed semantic analysis.",
+	}
+
+	for _, message := range messages {
+		if !strings.Contains(string(output), message) {
+			t.Fatalf("Output of build:\n\"%s\"\nis missing:\n\"%s\"", string(output), message)
+		}
+	}
+}
+
+func TestSlices(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "slices")
+
+	correctOutput := `é
+éll
+héllo
+wörld
+llo wör
+[2, 3]
+[1, 2, 3, 4, 5]
+[4, 5]
+[1, 2, 3, 4, 5] [9, 2]
+[3, 4]
+[]
+List slice out of range. Length is 5, slice is [3:9].
+String slice out of range. Length is 11, slice is [4:2].
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of slices:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+}
+
+func TestRanges(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "ranges")
+
+	correctOutput := `0 1 2 3 4 
+0 5 10 
+6 4 2 
+1 3 5 7 
+true
+false
+true
+false
+true
+true
+true
+9223372036854775800 9223372036854775805 
+-9223372036854775800 -9223372036854775804 
+0 1 2 3 
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of ranges:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+}
+
+func TestStandardLibrary(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "standardLibrary")
+
+	correctOutput := `true 3 olléh Élan
+["a", "ñ", "b"] ["a", "b", "c"] ["one", "two", "three"]
+true 12 true 6 120 5 180
+true 4 [5, 1, 4, 1, 3] 14
+[3, 4, 5] ["6", "2", "8", "2", "10"]
+["x", "x", "x"]
+3, 1, 4, 1, 5
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of standardLibrary:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+}
+
+func TestStrings(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "strings")
+
+	correctOutput := `[héllo wörld] [héllo wörld  ] [  héllo wörld]
+["héllo", "wörld"] ["a", "ñ", "b"]
+héllo, wörld
+cöt höt
+true true false
+6 -1
+ñññ 007 éaba|
+Daniel is 20 years old, 100%
+Can't repeat string -1 times.
+Can't repeat string 9223372036854775807 times. Maximum size of string is 1073741824 bytes.
+Can't pad string to width 9223372036854775807. Maximum width is 268435456 characters.
+Not enough values for format string "%s %s". Got 1 value/s.
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of strings:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+}
+
+func TestMath(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "math")
+
+	correctOutput := `4 1024 1.4142135623730951 1
+1 3 3
+0 -1 true
+-2 2.5 10 0
+-1 1 3 1.5
+true true true false
+-2 -1 1
+Integer overflow. 3 to the power of 40 doesn't fit in an int.
+Can't raise int 2 to negative power -1. Use flt values instead.
+Can't convert +Inf to an int. It's out of int range.
+Can't clamp value. Minimum 5 is greater than maximum 0.
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of math:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+}
+
+func TestListFunctions(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "listFunctions")
+
+	correctOutput := `[9, 5, 3, 8, 1, 7]
+7 [9, 5, 3, 8, 1]
+3 -1
+[1, 3, 5, 8, 9] [9, 5, 3, 8, 1]
+[9, 8, 5, 3, 1]
+["apple", "fig", "pear"] [-1, 0.5, 2.5]
+[] ["apple", "fig", "pear"]
+[0, 1, 2, 3, 4] []
+[1, 2, 3] [0, 3, 1, 2]
+Can't pop from an empty list.
+Can't insert to list at index 10. List size is 5.
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of listFunctions:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "files")
+
+	correctOutput := `none true
+none
+none
+first line
+second line
+third líne
+
+["first line", "second line", "third líne"]
+["report.txt"]
+FIRST LINE
+SECOND LINE
+THIRD LÍNE
+none none
+Failed to write src/filesOutput/missing/report.txt. No such file or directory.
+none false
+none false
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of files:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+
+	t.Cleanup(func() {
+		os.RemoveAll("src/filesOutput")
+	})
+}
+
+func TestArguments(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "arguments")
+
+	correctOutput := `0
+missing
+none
+set
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of arguments:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+
+	// Arguments are passed to entry and it's return value is the exit code
+	cmd := exec.Command("./neco", "run", "src/arguments", "--", "first", "second argument")
+	output, err := cmd.Output()
+
+	correctOutput = `2
+first
+second argument
+missing
+none
+set
+`
+	if exitError, isExitError := err.(*exec.ExitError); !isExitError || exitError.ExitCode() != 2 {
+		t.Fatalf("Exit code of arguments should be 2: %v", err)
+	}
+
+	if string(output) != correctOutput {
+		t.Fatalf("Output of arguments:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+
+	// Computed exit code
+	cmd = exec.Command("./neco", "src/arguments", "a", "b", "c")
+	err = cmd.Run()
+
+	if exitError, isExitError := err.(*exec.ExitError); !isExitError || exitError.ExitCode() != 30 {
+		t.Fatalf("Exit code of arguments should be 30: %v", err)
+	}
+}
+
+func TestTime(t *testing.T) {
+	buildNeCo(t)
+
+	// Formatted times depend on local time zone
+	t.Setenv("TZ", "UTC")
+
+	output := buildAndRun(t, "time")
+
+	correctOutput := `true true
+true
+1970-01-01 00:00:00
+2023-11-14T22:13:20.123
+10:13PM
+1700000000000
+1699920000000
+none
+true
+Can't sleep for negative duration -1 ms.
+Can't sleep for 9223372036854775807 ms. Maximum duration is 9223372036854 ms.
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of time:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+}
+
+func TestJson(t *testing.T) {
+	buildNeCo(t)
+
+	output := buildAndRun(t, "json")
+
+	correctOutput := `{"name":"Ann \"A\" <B>","age":31,"height":1.7,"active":true,"tags":["a","b"],"address":{"street":"Main","number":null},"scores":{"math":1,"art":2},"role":1}
+true
+[1,2,3]
+{"value":1,"children":[{"value":2,"children":[]}]}
+{"value":1,"children":[{"value":2,"children":[]}]}
+[1, 2, 3]
+3
+none
+Person{"Bob", 40, 1.8, false, [], none, {}, 0}
+Can't decode JSON. Expected int at $.age, found 40.5.
+Can't decode JSON. Expected str at $.tags[1], found 2.
+Can't decode JSON. Expected str at $.address.street, found null.
+Can't decode JSON. Field $.active of type bool is missing.
+Can't decode JSON. Expected Role at $.role, found 7.
+Can't decode invalid JSON. Unexpected end of text.
+Can't decode JSON. Expected Person at $, found array.
+`
+	if string(output) != correctOutput {
+		t.Fatalf("Output of json:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+}
+
+func TestRepl(t *testing.T) {
+	buildNeCo(t)
+
+	input := `int x = 5
+x + 1
+fun add(int a, int b) -> int {
+	return a * b + x
+}
+add(2, 3)
+list<int> numbers = [3, 1, 2]
+sort(numbers)
+numbers
+var scale = fun(int a) -> int { return a * x }
+scale(3)
+:type add
+:type numbers[0] > 2
+struct Point {
+	int x
+	int y
+}
+Point{1, 2}
+1 / 0
+x = x + 1
+x
+undeclared
+fun greet() {
+	printLine("hi")
+}
+greet()
+printLine("end")
+`
+	cmd := exec.Command("./neco", "repl")
+	cmd.Stdin = strings.NewReader(input)
+	output, err := cmd.Output()
+
+	if err != nil {
+		t.Fatalf("Failed to run repl: " + string(output) + "\n" + err.Error())
+	}
+
+	correctOutput := "NeCo 0.2.0 REPL. Use :help to list commands.\n" +
+		"> > 6\n" +
+		"> . . > 11\n" +
+		"> > > [1, 2, 3]\n" +
+		"> > 15\n" +
+		"> fun(int, int) -> int\n" +
+		"> bool\n" +
+		"> . . . > Point{1, 2}\n" +
+		"> \033[91mPanic: Integer divide by zero.\033[0m\nTraceback:\n" +
+		"   1 file repl.neco, line 1, function repl()\n" +
+		"> > 6\n" +
+		"> > . . > hi\n" +
+		"> end\n" +
+		"> \n"
+
+	if string(output) != correctOutput {
+		t.Fatalf("Output of repl:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+}
+
+func TestLsp(t *testing.T) {
+	buildNeCo(t)
+
+	source := `fun add(int a, int b) -> int {
+	return a + b
+}
+
+fun entry() {
+	int sum = add(1, 2)
+	printLine("🐱" + str(sum))
+	missing()
+}
+`
+	open, _ := json.Marshal(source)
+
+	messages := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///lsp.neco","text":` + string(open) + `}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///lsp.neco"},"position":{"line":5,"character":5}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"textDocument/definition","params":{"textDocument":{"uri":"file:///lsp.neco"},"position":{"line":5,"character":11}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"textDocument/references","params":{"textDocument":{"uri":"file:///lsp.neco"},"position":{"line":0,"character":12},"context":{"includeDeclaration":true}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"textDocument/signatureHelp","params":{"textDocument":{"uri":"file:///lsp.neco"},"position":{"line":5,"character":18}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///lsp.neco"},"position":{"line":6,"character":22}}}`,
+		`{"jsonrpc":"2.0","id":7,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	}
+
+	input := ""
+	for _, message := range messages {
+		input += fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(message), message)
+	}
+
+	cmd := exec.Command("./neco", "lsp")
+	cmd.Stdin = strings.NewReader(input)
+	output, err := cmd.Output()
+
+	if err != nil {
+		t.Fatalf("Failed to run language server: " + string(output) + "\n" + err.Error())
+	}
+
+	correctResponses := []string{
+		`"diagnostics":[{"range":{"start":{"line":7,"character":1},"end":{"line":7,"character":8}},"severity":1,"source":"neco","message":"Function missing is not declared in this scope."}]`,
+		`{"jsonrpc":"2.0","id":2,"result":{"contents":{"kind":"markdown","value":"` + "```neco\\nint sum\\n```" + `"},"range":{"start":{"line":5,"character":5},"end":{"line":5,"character":8}}}}`,
+		`{"jsonrpc":"2.0","id":3,"result":{"uri":"file:///lsp.neco","range":{"start":{"line":0,"character":4},"end":{"line":0,"character":7}}}}`,
+		`{"jsonrpc":"2.0","id":4,"result":[{"uri":"file:///lsp.neco","range":{"start":{"line":0,"character":12},"end":{"line":0,"character":13}}},{"uri":"file:///lsp.neco","range":{"start":{"line":1,"character":8},"end":{"line":1,"character":9}}}]}`,
+		`{"jsonrpc":"2.0","id":5,"result":{"signatures":[{"label":"add(int a, int b) -> int","parameters":[{"label":"int a"},{"label":"int b"}]}],"activeSignature":0,"activeParameter":1}}`,
+		`{"jsonrpc":"2.0","id":6,"result":{"contents":{"kind":"markdown","value":"` + "```neco\\nint sum\\n```" + `"},"range":{"start":{"line":6,"character":22},"end":{"line":6,"character":25}}}}`,
+		`{"jsonrpc":"2.0","id":7,"result":null}`,
+		`"positionEncoding":"utf-16"`,
+	}
+
+	for _, response := range correctResponses {
+		if !strings.Contains(string(output), response) {
+			t.Fatalf("Output of language server:\n\"%s\"\nis missing:\n\"%s\"", string(output), response)
+		}
+	}
+}
+
+func TestFmt(t *testing.T) {
+	buildNeCo(t)
+
+	source := `import std/math
+
+
+// Adds numbers
+fun add(int a,int b)->int{return a+b}
+fun entry(){
+
+	map<str, list<int>> groups = {"a":[1,-2]}
+  var point = Point{1,2} /* point */
+	if(add(1,2)>2){printLine("{add(1, 2)}")}else{
+		printLine( "small" )
+	}
+}
+
+struct Point{
+	int x
+	int y
+}
+`
+	correctOutput := `import std/math
+
+// Adds numbers
+fun add(int a, int b) -> int {
+    return a + b
+}
+fun entry() {
+    map<str, list<int>> groups = {"a": [1, -2]}
+    var point = Point{1, 2} /* point */
+    if (add(1, 2) > 2) {
+        printLine("{add(1, 2)}")
+    } else {
+        printLine("small")
+    }
+}
+
+struct Point {
+    int x
+    int y
+}
+`
+	path := t.TempDir() + "/format.neco"
+	os.WriteFile(path, []byte(source), 0644)
+
+	// Print formatted code
+	output, err := exec.Command("./neco", "fmt", path).Output()
+
+	if err != nil {
+		t.Fatalf("Failed to format: " + string(output) + "\n" + err.Error())
+	}
+
+	if string(output) != correctOutput {
+		t.Fatalf("Output of fmt:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+
+	// Check unformatted file
+	if exec.Command("./neco", "fmt", "--check", path).Run() == nil {
+		t.Fatalf("Check of unformatted file has succeeded.")
+	}
+
+	// Write formatted code and check it
+	if output, err = exec.Command("./neco", "fmt", "--write", path).Output(); err != nil {
+		t.Fatalf("Failed to write formatted code: " + string(output) + "\n" + err.Error())
+	}
+
+	written, _ := os.ReadFile(path)
+	if string(written) != correctOutput {
+		t.Fatalf("Written code:\n\"%s\"\nwanted:\n\"%s\"", string(written), correctOutput)
+	}
+
+	if output, err = exec.Command("./neco", "fmt", "--check", path).Output(); err != nil {
+		t.Fatalf("Check of formatted file has failed: " + string(output) + "\n" + err.Error())
+	}
+}
+
+func TestFmtAllman(t *testing.T) {
+	buildNeCo(t)
+
+	source := `fun entry()
+{
+	list<int> numbers = [1, 2]
+	forEach (int n in numbers)
+	{
+		if (n > 1)
+		{
+			printLine("big") }
+		else
+		{
+			printLine("small")
+		}
+	}
+	try
+	{
+		printLine(str(numbers[2]))
+	}
+	catch (err)
+	{
+		printLine(err.message)
+	}
+	var apply = fun(int a) -> int
+	{
+		return a
+	}
+	printLine(str(call(fun(int a) -> int { return a })))
+	{
+		int scoped = 1
+	}
+}
+
+fun call(fun(int) -> int f) -> int
+{
+	return f(1)
+}
+`
+	correctOutput := `fun entry() {
+    list<int> numbers = [1, 2]
+    forEach (int n in numbers) {
+        if (n > 1) {
+            printLine("big")
+        } else {
+            printLine("small")
+        }
+    }
+    try {
+        printLine(str(numbers[2]))
+    } catch (err) {
+        printLine(err.message)
+    }
+    var apply = fun(int a) -> int {
+        return a
+    }
+    printLine(str(call(fun(int a) -> int { return a })))
+    {
+        int scoped = 1
+    }
+}
+
+fun call(fun(int) -> int f) -> int {
+    return f(1)
+}
+`
+	path := t.TempDir() + "/allman.neco"
+	os.WriteFile(path, []byte(source), 0644)
+
+	output, err := exec.Command("./neco", "fmt", path).Output()
+
+	if err != nil {
+		t.Fatalf("Failed to format: " + string(output) + "\n" + err.Error())
+	}
+
+	if string(output) != correctOutput {
+		t.Fatalf("Output of fmt:\n\"%s\"\nwanted:\n\"%s\"", string(output), correctOutput)
+	}
+}
+
+func TestLint(t *testing.T) {
+	buildNeCo(t)
+
+	source := `import std/math
+
+const int max_size = 5
+
+fun add(int a, int unused) -> int {
+	return a
+}
+
+fun entry() {
+	int count = add(1, 2)
+	int ignored = 1 // lint:ignore unused-variable
+
+	if (true) {
+	}
+
+	while (count > 0) {
+		int count = 0
+		printLine(str(count))
+		break
+	}
+
+	// lint:ignore
+	int Bad_name = 1
+
+	if (count > 2) {
+		// Nothing to do
+	}
+
+	printLine(str(none ?! 2))
+}
+`
+	correctLints := []string{
+		"Module math is imported, but never used. [unused-import]",
+		"Constant max_size should be in UPPER_CASE or camelCase. [naming]",
+		"Parameter unused is never used. [unused-parameter]",
+		"Condition is always the same, because it consists only of literals. [constant-condition]",
+		"Block is empty. [empty-block]",
+		"Variable count shadows a variable declared in an outer scope. [shadowing]",
+		"Left side of operator ?! is always none, so it's always replaced by the default. [redundant-default]",
+	}
+
+	path := t.TempDir() + "/lint.neco"
+	os.WriteFile(path, []byte(source), 0644)
+
+	output, err := exec.Command("./neco", "lint", path, "-l", "warning").CombinedOutput()
+
+	if err == nil {
+		t.Fatalf("Lint of file with lints has succeeded.")
+	}
+
+	lints := []string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "[WARNING]") {
+			// Skip module and position
+			lints = append(lints, strings.SplitN(strings.TrimSpace(line[len("[WARNING]"):]), " ", 3)[2])
+		}
+	}
+
+	if strings.Join(lints, "\n") != strings.Join(correctLints, "\n") {
+		t.Fatalf("Lints:\n\"%s\"\nwanted:\n\"%s\"", strings.Join(lints, "\n"), strings.Join(correctLints, "\n"))
+	}
+
+	// Disabled rules aren't reported
+	if output, err = exec.Command("./neco", "lint", path, "--disable", "all", "--enable", "missing-entry").CombinedOutput(); err != nil {
+		t.Fatalf("Lint with disabled rules has failed: " + string(output) + "\n" + err.Error())
+	}
+}
+
+func TestDisasm(t *testing.T) {
+	buildNeCo(t)
+
+	source := `fun double(int a) -> int {
+	return a * 2
+}
+
+fun entry() {
+	for (int i = 0; i < 3; i += 1) {
+		printLine(str(double(i)))
+	}
+}
+`
+	directory := t.TempDir()
+	os.WriteFile(directory+"/disasm.neco", []byte(source), 0644)
+
+	if output, err := exec.Command("./neco", "build", directory+"/disasm.neco", "-s").CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build disasm.neco: " + string(output) + "\n" + err.Error())
+	}
+
+	output, err := exec.Command("./neco", "disasm", directory+"/disasm").Output()
+
+	if err != nil {
+		t.Fatalf("Failed to disassemble: " + string(output) + "\n" + err.Error())
+	}
+
+	// Collect instructions without byte offsets and indexes
+	instructions := map[string]bool{}
+	for _, line := range strings.Split(string(output), "\n") {
+		if fields := strings.Fields(line); len(fields) > 2 && strings.HasPrefix(fields[0], "0x") {
+			instructions[strings.Join(fields[2:], " ")] = true
+		}
+	}
+
+	for _, expected := range []string{"Version 0.2.0", "Function 0 double() instructions", "Function 1 entry() instructions", "Strings", "Integers"} {
+		if !strings.Contains(string(output), expected) {
+			t.Fatalf("Output of disasm doesn't contain \"%s\":\n%s", expected, string(output))
+		}
+	}
+
+	for _, expected := range []string{"disasm.neco:1 push_scope", "disasm.neco:2 int_mul", "disasm.neco:7 call 0 double()", "disasm.neco:7 call_builtin 1 printLine()"} {
+		found := false
+
+		for instruction := range instructions {
+			if strings.HasPrefix(instruction, expected) {
+				found = true
+			}
+		}
+
+		if !found {
+			t.Fatalf("Output of disasm doesn't contain instruction \"%s\":\n%s", expected, string(output))
+		}
+	}
+
+	// Jumps are resolved to indexes of instructions
+	if !strings.Contains(string(output), "jmp_back") || !strings.Contains(string(output), "-> ") {
+		t.Fatalf("Output of disasm doesn't contain resolved jumps:\n%s", string(output))
+	}
+
+	// Truncated binaries are reported as corrupted
+	binary, _ := os.ReadFile(directory + "/disasm")
+
+	for _, length := range []int{4, 20, len(binary) / 2, len(binary) - 1} {
+		os.WriteFile(directory+"/truncated", binary[:length], 0644)
+		output, err := exec.Command("./neco", "disasm", directory+"/truncated").CombinedOutput()
+
+		if err == nil || !strings.Contains(string(output), "File isn't a NeCo binary or is corrupted.") {
+			t.Fatalf("Disassembly of binary truncated to %d bytes hasn't failed as corrupted:\n%s", length, string(output))
+		}
+	}
+}
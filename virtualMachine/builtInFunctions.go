@@ -1,12 +1,17 @@
 package virtualMachine
 
 import (
+	"cmp"
 	"fmt"
 	"math"
 	"math/rand"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
@@ -29,19 +34,96 @@ const (
 	BIF_AbsInt
 	BIF_AbsFloat
 
+	BIF_Sqrt
+	BIF_PowerInt
+	BIF_PowerFloat
+	BIF_Exp
+	BIF_Log
+	BIF_Log2
+	BIF_Log10
+
+	BIF_Sin
+	BIF_Cos
+	BIF_Tan
+	BIF_Asin
+	BIF_Acos
+	BIF_Atan
+	BIF_Atan2
+
+	BIF_MinInt
+	BIF_MinFloat
+	BIF_MaxInt
+	BIF_MaxFloat
+	BIF_ClampInt
+	BIF_ClampFloat
+	BIF_SignInt
+	BIF_SignFloat
+
+	BIF_IsNaN
+	BIF_IsInf
+
 	BIF_ReadLine
 	BIF_ReadChar
 
+	BIF_ReadFile
+	BIF_ReadLines
+	BIF_ListDir
+	BIF_FileExists
+	BIF_WriteFile
+	BIF_AppendFile
+	BIF_MakeDir
+	BIF_RemoveFile
+
+	BIF_GetEnv
+	BIF_SetEnv
+
 	BIF_StringLength
 	BIF_ListLength
 
+	BIF_Insert
+	BIF_Pop
+	BIF_Reverse
+	BIF_Sort
+	BIF_Clear
+	BIF_Sorted
+	BIF_Copy
+	BIF_Range
+
+	BIF_MapKeys
+	BIF_MapValues
+
 	BIF_ToLower
 	BIF_ToUpper
 
+	BIF_Split
+	BIF_Join
+	BIF_Replace
+	BIF_Contains
+	BIF_StartsWith
+	BIF_EndsWith
+	BIF_IndexOf
+	BIF_Trim
+	BIF_TrimLeft
+	BIF_TrimRight
+	BIF_Repeat
+	BIF_PadLeft
+	BIF_PadRight
+	BIF_Format
+
 	BIF_RandomInt
 	BIF_RandomFloat
 	BIF_RandomRangeInt
 
+	BIF_ToJson
+	BIF_FromJson
+
+	BIF_Now
+	BIF_NowFloat
+	BIF_Monotonic
+	BIF_Sleep
+	BIF_FormatTime
+	BIF_ParseTime
+
 	BIF_ParseInt
 	BIF_ParseFloat
 
@@ -82,22 +164,26 @@ func (vm *VirtualMachine) callBuiltInFunction(functionCode int) {
 		vm.stack.Push(math.Floor(vm.stack.Pop().(float64)))
 
 	case BIF_FloorToInt:
-		vm.stack.Push(int64(vm.stack.Pop().(float64)))
+		vm.stack.Push(vm.floatToInt(math.Floor(vm.stack.Pop().(float64))))
 
 	case BIF_Ceil:
 		vm.stack.Push(math.Ceil(vm.stack.Pop().(float64)))
 
 	case BIF_CeilToInt:
-		vm.stack.Push(int64(math.Ceil(vm.stack.Pop().(float64))))
+		vm.stack.Push(vm.floatToInt(math.Ceil(vm.stack.Pop().(float64))))
 
 	case BIF_Round:
 		vm.stack.Push(math.Round(vm.stack.Pop().(float64)))
 
 	case BIF_RoundToInt:
-		vm.stack.Push(int64(math.Round(vm.stack.Pop().(float64))))
+		vm.stack.Push(vm.floatToInt(math.Round(vm.stack.Pop().(float64))))
 
 	// Absolute values
 	case BIF_AbsInt:
+		if (*vm.stack.Top()).(int64) == math.MinInt64 {
+			vm.panic(fmt.Sprintf("Integer overflow. Absolute value of %d doesn't fit in an int.", int64(math.MinInt64)))
+		}
+
 		if (*vm.stack.Top()).(int64) < 0 {
 			vm.stack.Push(-vm.stack.Pop().(int64))
 		}
@@ -105,6 +191,124 @@ func (vm *VirtualMachine) callBuiltInFunction(functionCode int) {
 	case BIF_AbsFloat:
 		vm.stack.Push(math.Abs(vm.stack.Pop().(float64)))
 
+	// Powers, roots and logarithms
+	case BIF_Sqrt:
+		vm.stack.Push(math.Sqrt(vm.stack.Pop().(float64)))
+
+	case BIF_PowerInt:
+		exponent := vm.stack.Pop().(int64)
+		base := vm.stack.Pop().(int64)
+
+		if exponent < 0 {
+			vm.panic(fmt.Sprintf("Can't raise int %d to negative power %d. Use flt values instead.", base, exponent))
+		}
+
+		result, overflow := checkedPowerInt64(base, exponent)
+		if overflow {
+			vm.panic(fmt.Sprintf("Integer overflow. %d to the power of %d doesn't fit in an int.", base, exponent))
+		}
+		vm.stack.Push(result)
+
+	case BIF_PowerFloat:
+		exponent := vm.stack.Pop().(float64)
+		vm.stack.Push(math.Pow(vm.stack.Pop().(float64), exponent))
+
+	case BIF_Exp:
+		vm.stack.Push(math.Exp(vm.stack.Pop().(float64)))
+
+	case BIF_Log:
+		vm.stack.Push(math.Log(vm.stack.Pop().(float64)))
+
+	case BIF_Log2:
+		vm.stack.Push(math.Log2(vm.stack.Pop().(float64)))
+
+	case BIF_Log10:
+		vm.stack.Push(math.Log10(vm.stack.Pop().(float64)))
+
+	// Trigonometric functions
+	case BIF_Sin:
+		vm.stack.Push(math.Sin(vm.stack.Pop().(float64)))
+
+	case BIF_Cos:
+		vm.stack.Push(math.Cos(vm.stack.Pop().(float64)))
+
+	case BIF_Tan:
+		vm.stack.Push(math.Tan(vm.stack.Pop().(float64)))
+
+	case BIF_Asin:
+		vm.stack.Push(math.Asin(vm.stack.Pop().(float64)))
+
+	case BIF_Acos:
+		vm.stack.Push(math.Acos(vm.stack.Pop().(float64)))
+
+	case BIF_Atan:
+		vm.stack.Push(math.Atan(vm.stack.Pop().(float64)))
+
+	case BIF_Atan2:
+		x := vm.stack.Pop().(float64)
+		vm.stack.Push(math.Atan2(vm.stack.Pop().(float64), x))
+
+	// Minimum, maximum and clamping
+	case BIF_MinInt:
+		vm.stack.Push(min(vm.stack.Pop().(int64), vm.stack.Pop().(int64)))
+
+	case BIF_MinFloat:
+		vm.stack.Push(min(vm.stack.Pop().(float64), vm.stack.Pop().(float64)))
+
+	case BIF_MaxInt:
+		vm.stack.Push(max(vm.stack.Pop().(int64), vm.stack.Pop().(int64)))
+
+	case BIF_MaxFloat:
+		vm.stack.Push(max(vm.stack.Pop().(float64), vm.stack.Pop().(float64)))
+
+	case BIF_ClampInt:
+		maximum := vm.stack.Pop().(int64)
+		minimum := vm.stack.Pop().(int64)
+
+		if minimum > maximum {
+			vm.panic(fmt.Sprintf("Can't clamp value. Minimum %d is greater than maximum %d.", minimum, maximum))
+		}
+		vm.stack.Push(min(max(vm.stack.Pop().(int64), minimum), maximum))
+
+	case BIF_ClampFloat:
+		maximum := vm.stack.Pop().(float64)
+		minimum := vm.stack.Pop().(float64)
+
+		if minimum > maximum {
+			vm.panic(fmt.Sprintf("Can't clamp value. Minimum %v is greater than maximum %v.", minimum, maximum))
+		}
+		vm.stack.Push(min(max(vm.stack.Pop().(float64), minimum), maximum))
+
+	case BIF_SignInt:
+		value := vm.stack.Pop().(int64)
+
+		if value > 0 {
+			vm.stack.Push(INT_1)
+		} else if value < 0 {
+			vm.stack.Push(int64(-1))
+		} else {
+			vm.stack.Push(INT_0)
+		}
+
+	case BIF_SignFloat:
+		value := vm.stack.Pop().(float64)
+
+		if value > 0 {
+			vm.stack.Push(1.0)
+		} else if value < 0 {
+			vm.stack.Push(-1.0)
+		} else {
+			// Zero and NaN are returned unchanged
+			vm.stack.Push(value)
+		}
+
+	// Special float values
+	case BIF_IsNaN:
+		vm.stack.Push(math.IsNaN(vm.stack.Pop().(float64)))
+
+	case BIF_IsInf:
+		vm.stack.Push(math.IsInf(vm.stack.Pop().(float64), 0))
+
 	// Reading from terminal
 	case BIF_ReadLine:
 		line, _ := vm.reader.ReadString('\n')
@@ -114,12 +318,157 @@ func (vm *VirtualMachine) callBuiltInFunction(functionCode int) {
 		char, _, _ := vm.reader.ReadRune()
 		vm.stack.Push(string(char))
 
+	// Reading files and directories
+	case BIF_ReadFile:
+		content, err := os.ReadFile(vm.stack.Pop().(string))
+
+		if err != nil {
+			vm.stack.Push(nil)
+		} else {
+			vm.stack.Push(string(content))
+		}
+
+	case BIF_ReadLines:
+		content, err := os.ReadFile(vm.stack.Pop().(string))
+
+		if err != nil {
+			vm.stack.Push(nil)
+		} else {
+			vm.stack.Push(splitLines(string(content)))
+		}
+
+	case BIF_ListDir:
+		entries, err := os.ReadDir(vm.stack.Pop().(string))
+
+		if err != nil {
+			vm.stack.Push(nil)
+		} else {
+			names := make([]any, len(entries))
+			for i, entry := range entries {
+				names[i] = entry.Name()
+			}
+			vm.stack.Push(names)
+		}
+
+	case BIF_FileExists:
+		_, err := os.Stat(vm.stack.Pop().(string))
+		vm.stack.Push(err == nil)
+
+	// Modifying files and directories
+	case BIF_WriteFile:
+		text := vm.stack.Pop().(string)
+		path := vm.stack.Pop().(string)
+		vm.stack.Push(vm.fileError("write", path, os.WriteFile(path, []byte(text), 0644)))
+
+	case BIF_AppendFile:
+		text := vm.stack.Pop().(string)
+		path := vm.stack.Pop().(string)
+
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err == nil {
+			_, err = file.WriteString(text)
+			file.Close()
+		}
+		vm.stack.Push(vm.fileError("append to", path, err))
+
+	case BIF_MakeDir:
+		path := vm.stack.Pop().(string)
+		vm.stack.Push(vm.fileError("make directory", path, os.MkdirAll(path, 0755)))
+
+	case BIF_RemoveFile:
+		path := vm.stack.Pop().(string)
+		vm.stack.Push(vm.fileError("remove", path, os.Remove(path)))
+
+	// Environment variables
+	case BIF_GetEnv:
+		value, exists := os.LookupEnv(vm.stack.Pop().(string))
+
+		if exists {
+			vm.stack.Push(value)
+		} else {
+			vm.stack.Push(nil)
+		}
+
+	case BIF_SetEnv:
+		value := vm.stack.Pop().(string)
+		name := vm.stack.Pop().(string)
+		vm.stack.Push(vm.fileError("set environment variable", name, os.Setenv(name, value)))
+
 	// Sizes
 	case BIF_StringLength:
 		vm.stack.Push(int64(len([]rune(vm.stack.Pop().(string)))))
 
 	case BIF_ListLength:
-		vm.stack.Push(int64(len(vm.stack.Pop().([]any))))
+		if valueMap, ok := (*vm.stack.Top()).(*orderedMap); ok {
+			vm.stack.items[vm.stack.size-1] = int64(valueMap.Size())
+		} else {
+			vm.stack.Push(int64(len(vm.stack.Pop().([]any))))
+		}
+
+	// Modifying lists, modified lists are pushed so they can be stored back to their variable
+	case BIF_Insert:
+		value := vm.stack.Pop()
+		index := vm.stack.Pop().(int64)
+		list := vm.stack.Pop().([]any)
+
+		if index < 0 || index > int64(len(list)) {
+			vm.panic(fmt.Sprintf("Can't insert to list at index %d. List size is %d.", index, len(list)))
+		}
+
+		newList := make([]any, 0, len(list)+1)
+		newList = append(newList, list[:index]...)
+		newList = append(newList, value)
+		vm.stack.Push(append(newList, list[index:]...))
+
+	case BIF_Pop:
+		list := vm.stack.Pop().([]any)
+
+		if len(list) == 0 {
+			vm.panic("Can't pop from an empty list.")
+		}
+
+		vm.stack.Push(list[len(list)-1])
+		vm.stack.Push(append([]any{}, list[:len(list)-1]...))
+
+	case BIF_Reverse:
+		list := vm.stack.Pop().([]any)
+		newList := make([]any, len(list))
+
+		for i, element := range list {
+			newList[len(list)-1-i] = element
+		}
+		vm.stack.Push(newList)
+
+	case BIF_Sort, BIF_Sorted:
+		newList := append([]any{}, vm.stack.Pop().([]any)...)
+		slices.SortStableFunc(newList, compareOrdered)
+		vm.stack.Push(newList)
+
+	case BIF_Clear:
+		vm.stack.items[vm.stack.size-1] = []any{}
+
+	case BIF_Copy:
+		vm.stack.Push(append([]any{}, vm.stack.Pop().([]any)...))
+
+	case BIF_Range:
+		count := vm.stack.Pop().(int64)
+
+		if count < 0 {
+			vm.panic(fmt.Sprintf("Can't create range of %d numbers.", count))
+		}
+
+		list := make([]any, count)
+		for i := range list {
+			list[i] = int64(i)
+		}
+		vm.stack.Push(list)
+
+	// Map keys and values
+	case BIF_MapKeys:
+		vm.stack.items[vm.stack.size-1] = vm.stack.items[vm.stack.size-1].(*orderedMap).Keys()
+
+	case BIF_MapValues:
+		vm.stack.items[vm.stack.size-1] = vm.stack.items[vm.stack.size-1].(*orderedMap).Values()
 
 	// String functions
 	case BIF_ToLower:
@@ -128,6 +477,106 @@ func (vm *VirtualMachine) callBuiltInFunction(functionCode int) {
 	case BIF_ToUpper:
 		vm.stack.Push(strings.ToUpper(vm.stack.Pop().(string)))
 
+	case BIF_Split:
+		separator := vm.stack.Pop().(string)
+		parts := strings.Split(vm.stack.Pop().(string), separator)
+
+		list := make([]any, len(parts))
+		for i, part := range parts {
+			list[i] = part
+		}
+		vm.stack.Push(list)
+
+	case BIF_Join:
+		separator := vm.stack.Pop().(string)
+		list := vm.stack.Pop().([]any)
+
+		parts := make([]string, len(list))
+		for i, part := range list {
+			parts[i] = part.(string)
+		}
+		vm.stack.Push(strings.Join(parts, separator))
+
+	case BIF_Replace:
+		replacement := vm.stack.Pop().(string)
+		old := vm.stack.Pop().(string)
+		vm.stack.Push(strings.ReplaceAll(vm.stack.Pop().(string), old, replacement))
+
+	case BIF_Contains:
+		part := vm.stack.Pop().(string)
+		vm.stack.Push(strings.Contains(vm.stack.Pop().(string), part))
+
+	case BIF_StartsWith:
+		prefix := vm.stack.Pop().(string)
+		vm.stack.Push(strings.HasPrefix(vm.stack.Pop().(string), prefix))
+
+	case BIF_EndsWith:
+		suffix := vm.stack.Pop().(string)
+		vm.stack.Push(strings.HasSuffix(vm.stack.Pop().(string), suffix))
+
+	case BIF_IndexOf:
+		// Index of list element
+		if list, isList := vm.stack.items[vm.stack.size-2].([]any); isList {
+			value := vm.stack.Pop()
+			vm.stack.items[vm.stack.size-1] = int64(-1)
+
+			for i, element := range list {
+				if element == value {
+					vm.stack.items[vm.stack.size-1] = int64(i)
+					break
+				}
+			}
+			break
+		}
+
+		// Index of substring
+		part := vm.stack.Pop().(string)
+		text := vm.stack.Pop().(string)
+
+		// Convert byte index to rune index
+		index := strings.Index(text, part)
+		if index != -1 {
+			index = utf8.RuneCountInString(text[:index])
+		}
+		vm.stack.Push(int64(index))
+
+	case BIF_Trim:
+		vm.stack.Push(strings.TrimSpace(vm.stack.Pop().(string)))
+
+	case BIF_TrimLeft:
+		vm.stack.Push(strings.TrimLeftFunc(vm.stack.Pop().(string), unicode.IsSpace))
+
+	case BIF_TrimRight:
+		vm.stack.Push(strings.TrimRightFunc(vm.stack.Pop().(string), unicode.IsSpace))
+
+	case BIF_Repeat:
+		count := vm.stack.Pop().(int64)
+		if count < 0 {
+			vm.panic(fmt.Sprintf("Can't repeat string %d times.", count))
+		}
+
+		text := vm.stack.Pop().(string)
+		if len(text) > 0 && count > MAX_STRING_SIZE/int64(len(text)) {
+			vm.panic(fmt.Sprintf("Can't repeat string %d times. Maximum size of string is %d bytes.", count, MAX_STRING_SIZE))
+		}
+		vm.stack.Push(strings.Repeat(text, int(count)))
+
+	case BIF_PadLeft:
+		padding := vm.stack.Pop().(string)
+		width := vm.stack.Pop().(int64)
+		text := vm.stack.Pop().(string)
+		vm.stack.Push(vm.padding(text, width, padding) + text)
+
+	case BIF_PadRight:
+		padding := vm.stack.Pop().(string)
+		width := vm.stack.Pop().(int64)
+		text := vm.stack.Pop().(string)
+		vm.stack.Push(text + vm.padding(text, width, padding))
+
+	case BIF_Format:
+		values := vm.stack.Pop().([]any)
+		vm.stack.Push(vm.format(vm.stack.Pop().(string), values))
+
 	// Random numbers
 	case BIF_RandomInt:
 		vm.stack.Push(int64(rand.Uint64()))
@@ -138,13 +587,55 @@ func (vm *VirtualMachine) callBuiltInFunction(functionCode int) {
 	case BIF_RandomRangeInt:
 		vm.stack.Push(rand.Int63n(vm.stack.Pop().(int64)-(*vm.stack.Top()).(int64)+1) + vm.stack.Pop().(int64))
 
+	// JSON, schema of the value is the last argument
+	case BIF_ToJson:
+		schema := vm.jsonSchema(vm.stack.Pop().(string))
+		vm.stack.Push(vm.toJson(vm.stack.Pop(), schema))
+
+	case BIF_FromJson:
+		schema := vm.jsonSchema(vm.stack.Pop().(string))
+		vm.stack.Push(vm.fromJson(vm.stack.Pop().(string), schema))
+
+	// Time
+	case BIF_Now:
+		vm.stack.Push(time.Now().UnixMilli())
+
+	case BIF_NowFloat:
+		vm.stack.Push(float64(time.Now().UnixNano()) / float64(time.Second))
+
+	case BIF_Monotonic:
+		vm.stack.Push(float64(time.Since(vm.startTime)) / float64(time.Millisecond))
+
+	case BIF_Sleep:
+		vm.sleep(vm.stack.Pop().(int64))
+
+	case BIF_FormatTime:
+		layout := timeLayout(vm.stack.Pop().(string))
+		vm.stack.Push(time.UnixMilli(vm.stack.Pop().(int64)).Format(layout))
+
+	case BIF_ParseTime:
+		layout := timeLayout(vm.stack.Pop().(string))
+		parsed, err := time.ParseInLocation(layout, vm.stack.Pop().(string), time.Local)
+
+		if err != nil {
+			vm.stack.Push(nil)
+		} else {
+			vm.stack.Push(parsed.UnixMilli())
+		}
+
 	// Parsing numbers
 	case BIF_ParseInt:
-		integer, _ := strconv.ParseInt(vm.stack.items[vm.stack.size-1].(string), 10, 64)
+		integer, err := strconv.ParseInt(vm.stack.items[vm.stack.size-1].(string), 10, 64)
+		if err != nil {
+			vm.panic("Can't parse \"" + vm.stack.items[vm.stack.size-1].(string) + "\" as an int.")
+		}
 		vm.stack.items[vm.stack.size-1] = integer
 
 	case BIF_ParseFloat:
-		float, _ := strconv.ParseFloat(vm.stack.items[vm.stack.size-1].(string), 64)
+		float, err := strconv.ParseFloat(vm.stack.items[vm.stack.size-1].(string), 64)
+		if err != nil {
+			vm.panic("Can't parse \"" + vm.stack.items[vm.stack.size-1].(string) + "\" as a flt.")
+		}
 		vm.stack.items[vm.stack.size-1] = float
 
 	// Trace
@@ -197,6 +688,20 @@ func necoPrintString(value any, root bool) string {
 
 		return str + necoPrintString(object.fields[len(object.fields)-1], false) + "}"
 
+		// Print variant
+	} else if variant, ok := value.(variant); ok {
+		if len(variant.payload) == 0 {
+			return *variant.identifier
+		}
+
+		str := *variant.identifier + "("
+
+		for _, payload := range variant.payload[:len(variant.payload)-1] {
+			str += necoPrintString(payload, false) + ", "
+		}
+
+		return str + necoPrintString(variant.payload[len(variant.payload)-1], false) + ")"
+
 		// Print list
 	} else if valueList, ok := value.([]any); ok {
 		if len(valueList) == 0 {
@@ -227,6 +732,23 @@ func necoPrintString(value any, root bool) string {
 
 		return str + "}"
 
+		// Print map
+	} else if valueMap, ok := value.(*orderedMap); ok {
+		str := "{"
+
+		for i, key := range valueMap.keys {
+			if i != 0 {
+				str += ", "
+			}
+			str += necoPrintString(key, false) + ": " + necoPrintString(valueMap.values[key], false)
+		}
+
+		return str + "}"
+
+		// Print function
+	} else if _, ok := value.(closure); ok {
+		return "fun"
+
 		// Print string
 	} else if valueString, ok := value.(string); ok && !root {
 		return "\"" + valueString + "\""
@@ -241,6 +763,194 @@ func necoPrintString(value any, root bool) string {
 	}
 }
 
+// Splits file content to lines. Line endings aren't included in the lines.
+func splitLines(content string) []any {
+	if content == "" {
+		return []any{}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	list := make([]any, len(lines))
+
+	for i, line := range lines {
+		list[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	return list
+}
+
+// Creates error object describing failed file or environment operation. Returns none if there is no error.
+func (vm *VirtualMachine) fileError(operation, path string, err error) any {
+	if err == nil {
+		return nil
+	}
+
+	// Error messages of file operations start with operation and path
+	reasonParts := strings.Split(err.Error(), ": ")
+	reason := []rune(reasonParts[len(reasonParts)-1])
+
+	return vm.newError(fmt.Sprintf("Failed to %s %s. %c%s.", operation, path, unicode.ToUpper(reason[0]), string(reason[1:])))
+}
+
+// Blocks execution for given number of milliseconds.
+func (vm *VirtualMachine) sleep(milliseconds int64) {
+	if milliseconds < 0 {
+		vm.panic(fmt.Sprintf("Can't sleep for negative duration %d ms.", milliseconds))
+	}
+
+	// Longer durations overflow time.Duration
+	if milliseconds > math.MaxInt64/int64(time.Millisecond) {
+		vm.panic(fmt.Sprintf("Can't sleep for %d ms. Maximum duration is %d ms.", milliseconds, math.MaxInt64/int64(time.Millisecond)))
+	}
+
+	time.Sleep(time.Duration(milliseconds) * time.Millisecond)
+}
+
+// Named layouts which can be used instead of Go reference time layouts.
+var TIME_LAYOUTS = map[string]string{
+	"RFC3339":  time.RFC3339,
+	"RFC1123":  time.RFC1123,
+	"DateTime": time.DateTime,
+	"DateOnly": time.DateOnly,
+	"TimeOnly": time.TimeOnly,
+	"Kitchen":  time.Kitchen,
+}
+
+// Returns layout with given name, or the layout itself if it isn't a name.
+func timeLayout(layout string) string {
+	if named, isName := TIME_LAYOUTS[layout]; isName {
+		return named
+	}
+	return layout
+}
+
+// Compares two ints, floats or strings.
+func compareOrdered(a, b any) int {
+	switch a := a.(type) {
+	case int64:
+		return cmp.Compare(a, b.(int64))
+	case float64:
+		return cmp.Compare(a, b.(float64))
+	default:
+		return cmp.Compare(a.(string), b.(string))
+	}
+}
+
+// Creates padding, which extends text to width characters.
+func (vm *VirtualMachine) padding(text string, width int64, padding string) string {
+	missing := width - int64(utf8.RuneCountInString(text))
+
+	if missing <= 0 {
+		return ""
+	}
+
+	if padding == "" {
+		vm.panic("Can't pad string with an empty string.")
+	}
+
+	if missing > MAX_STRING_SIZE/utf8.UTFMax {
+		vm.panic(fmt.Sprintf("Can't pad string to width %d. Maximum width is %d characters.", width, MAX_STRING_SIZE/utf8.UTFMax))
+	}
+
+	// Repeat padding and cut it to the missing number of characters
+	paddingRunes := []rune(padding)
+	result := make([]rune, missing)
+
+	for i := range result {
+		result[i] = paddingRunes[i%len(paddingRunes)]
+	}
+
+	return string(result)
+}
+
+// Replaces %s in format string with values converted to strings. %% is replaced with %.
+func (vm *VirtualMachine) format(format string, values []any) string {
+	var builder strings.Builder
+	valueIndex := 0
+	formatRunes := []rune(format)
+
+	for i := 0; i < len(formatRunes); i++ {
+		if formatRunes[i] != '%' {
+			builder.WriteRune(formatRunes[i])
+			continue
+		}
+
+		i++
+		if i == len(formatRunes) {
+			vm.panic("Format string \"" + format + "\" ends with %.")
+		}
+
+		switch formatRunes[i] {
+		case '%':
+			builder.WriteRune('%')
+
+		case 's':
+			if valueIndex == len(values) {
+				vm.panic(fmt.Sprintf("Not enough values for format string \"%s\". Got %d value/s.", format, len(values)))
+			}
+
+			builder.WriteString(necoPrintString(values[valueIndex], true))
+			valueIndex++
+
+		default:
+			vm.panic("Invalid placeholder %" + string(formatRunes[i]) + " in format string \"" + format + "\".")
+		}
+	}
+
+	if valueIndex != len(values) {
+		vm.panic(fmt.Sprintf("Too many values for format string \"%s\". It has %d placeholder/s, got %d value/s.", format, valueIndex, len(values)))
+	}
+
+	return builder.String()
+}
+
+// Converts float to int. Panics if the float is NaN, infinite or too large to fit in an int.
+func (vm *VirtualMachine) floatToInt(value float64) int64 {
+	if math.IsNaN(value) {
+		vm.panic("Can't convert NaN to an int.")
+	}
+
+	// Float of math.MaxInt64 is rounded up to 2^63, so it's out of range too
+	if value >= math.MaxInt64 || value < math.MinInt64 {
+		vm.panic(fmt.Sprintf("Can't convert %v to an int. It's out of int range.", value))
+	}
+
+	return int64(value)
+}
+
+// Raises base to exponent. Second return value is true if the result overflows.
+func checkedPowerInt64(base, exponent int64) (int64, bool) {
+	// Powers of 0, 1 and -1 don't grow
+	switch base {
+	case 0:
+		if exponent == 0 {
+			return 1, false
+		}
+		return 0, false
+	case 1:
+		return 1, false
+	case -1:
+		if exponent%2 == 0 {
+			return 1, false
+		}
+		return -1, false
+	}
+
+	// Other bases overflow in less than 64 multiplications
+	var result int64 = 1
+
+	for i := int64(0); i < exponent; i++ {
+		product := result * base
+
+		if product/base != result {
+			return 0, true
+		}
+		result = product
+	}
+
+	return result, false
+}
+
 func PowerInt64(base, exponent int64) int64 {
 	var result int64 = 1
 
@@ -272,22 +982,99 @@ var BuiltInFuncToString = map[byte]string{
 	BIF_CeilToInt:  "ceilToInt",
 	BIF_Round:      "round",
 	BIF_RoundToInt: "roundToInt",
-	BIF_AbsInt:     "absInt",
-	BIF_AbsFloat:   "absFloat",
+	BIF_AbsInt:     "abs",
+	BIF_AbsFloat:   "abs",
+
+	BIF_Sqrt:       "sqrt",
+	BIF_PowerInt:   "pow",
+	BIF_PowerFloat: "pow",
+	BIF_Exp:        "exp",
+	BIF_Log:        "log",
+	BIF_Log2:       "log2",
+	BIF_Log10:      "log10",
+
+	BIF_Sin:   "sin",
+	BIF_Cos:   "cos",
+	BIF_Tan:   "tan",
+	BIF_Asin:  "asin",
+	BIF_Acos:  "acos",
+	BIF_Atan:  "atan",
+	BIF_Atan2: "atan2",
+
+	BIF_MinInt:     "min",
+	BIF_MinFloat:   "min",
+	BIF_MaxInt:     "max",
+	BIF_MaxFloat:   "max",
+	BIF_ClampInt:   "clamp",
+	BIF_ClampFloat: "clamp",
+	BIF_SignInt:    "sign",
+	BIF_SignFloat:  "sign",
+
+	BIF_IsNaN: "isNaN",
+	BIF_IsInf: "isInf",
 
 	BIF_ReadLine: "readLine",
 	BIF_ReadChar: "readChar",
 
+	BIF_ReadFile:   "readFile",
+	BIF_ReadLines:  "readLines",
+	BIF_ListDir:    "listDir",
+	BIF_FileExists: "fileExists",
+	BIF_WriteFile:  "writeFile",
+	BIF_AppendFile: "appendFile",
+	BIF_MakeDir:    "makeDir",
+	BIF_RemoveFile: "removeFile",
+
+	BIF_GetEnv: "getEnv",
+	BIF_SetEnv: "setEnv",
+
 	BIF_StringLength: "length",
 	BIF_ListLength:   "size",
 
+	BIF_Insert:  "insert",
+	BIF_Pop:     "pop",
+	BIF_Reverse: "reverse",
+	BIF_Sort:    "sort",
+	BIF_Clear:   "clear",
+	BIF_Sorted:  "sorted",
+	BIF_Copy:    "copy",
+	BIF_Range:   "range",
+
+	BIF_MapKeys:   "keys",
+	BIF_MapValues: "values",
+
 	BIF_ToLower: "toLower",
 	BIF_ToUpper: "toUpper",
 
+	BIF_Split:      "split",
+	BIF_Join:       "join",
+	BIF_Replace:    "replace",
+	BIF_Contains:   "contains",
+	BIF_StartsWith: "startsWith",
+	BIF_EndsWith:   "endsWith",
+	BIF_IndexOf:    "indexOf",
+	BIF_Trim:       "trim",
+	BIF_TrimLeft:   "trimLeft",
+	BIF_TrimRight:  "trimRight",
+	BIF_Repeat:     "repeat",
+	BIF_PadLeft:    "padLeft",
+	BIF_PadRight:   "padRight",
+	BIF_Format:     "format",
+
 	BIF_RandomInt:      "randomInt",
 	BIF_RandomFloat:    "randomFloat",
 	BIF_RandomRangeInt: "randomRangeInt",
 
+	BIF_ToJson:   "toJson",
+	BIF_FromJson: "fromJson",
+
+	BIF_Now:        "now",
+	BIF_NowFloat:   "nowFlt",
+	BIF_Monotonic:  "monotonic",
+	BIF_Sleep:      "sleep",
+	BIF_FormatTime: "formatTime",
+	BIF_ParseTime:  "parseTime",
+
 	BIF_ParseInt:   "parseInt",
 	BIF_ParseFloat: "parseFloat",
 
@@ -17,6 +17,13 @@ type InstructionReader struct {
 	byteIndex int
 
 	virtualMachine *VirtualMachine
+
+	Version        [3]byte // Version of NeCo, which built the binary
+	ConstantCounts [3]int  // Counts of string, int and float constants
+
+	Functions        []int // Indexes of first instructions of functions
+	GlobalsOffsets   []int // Byte offsets of globals instructions in the binary
+	FunctionsOffsets []int // Byte offsets of functions instructions in the binary
 }
 
 var NO_ARGS = []int{}
@@ -24,7 +31,7 @@ var NO_ARGS = []int{}
 const OFFSET_BYTE_MASK = byte(0b0111_1111)
 
 func NewInstructionReader(filePath string, virtualMachine *VirtualMachine) *InstructionReader {
-	return &InstructionReader{filePath, nil, 0, virtualMachine}
+	return &InstructionReader{filePath, nil, 0, virtualMachine, [3]byte{}, [3]int{}, nil, nil, nil}
 }
 
 // Converts an big endian encoded uint on 3 bytes to an int.
@@ -49,15 +56,16 @@ func (ir *InstructionReader) Read() {
 	}
 
 	// Invalid magic number
-	if ir.bytes[0] != 'N' || ir.bytes[1] != 'e' || ir.bytes[2] != 'C' || ir.bytes[3] != 'o' {
-		logger.Fatal(errors.READ_PROGRAM, "File isn't a NeCo binary or is corrupted.")
+	if len(ir.bytes) < 8 || ir.bytes[0] != 'N' || ir.bytes[1] != 'e' || ir.bytes[2] != 'C' || ir.bytes[3] != 'o' {
+		ir.corrupted()
 	}
 
-	// Incompatible version
-	if ir.bytes[5] > VERSION_MAJOR || ir.bytes[6] > VERSION_MINOR || ir.bytes[7] > VERSION_PATCH {
+	// Incompatible version, instruction sets of different major or minor versions differ
+	if ir.bytes[5] != VERSION_MAJOR || ir.bytes[6] != VERSION_MINOR || ir.bytes[7] > VERSION_PATCH {
 		logger.Fatal(errors.INCOMPATIBLE_VERSION, fmt.Sprintf("Incompatible version. Binary version is %d.%d.%d, your NeCo version is %d.%d.%d.", ir.bytes[5], ir.bytes[6], ir.bytes[7], VERSION_MAJOR, VERSION_MINOR, VERSION_PATCH))
 	}
 
+	ir.Version = [3]byte{ir.bytes[5], ir.bytes[6], ir.bytes[7]}
 	ir.byteIndex = 8
 
 	// Read segments
@@ -65,22 +73,49 @@ func (ir *InstructionReader) Read() {
 	ir.readCode()
 }
 
+func (ir *InstructionReader) corrupted() {
+	logger.Fatal(errors.READ_PROGRAM, "File isn't a NeCo binary or is corrupted.")
+}
+
+// Exits if there aren't count more bytes before end.
+func (ir *InstructionReader) require(count, end int) {
+	if ir.byteIndex+count > end {
+		ir.corrupted()
+	}
+}
+
+// Reads segment header and returns index of the segment end. Size of segments with items of itemSize bytes has to be their multiple.
+func (ir *InstructionReader) readSegmentHeader(itemSize int) int {
+	ir.require(4, len(ir.bytes))
+	ir.byteIndex++
+
+	segmentSize := byte3ToInt(ir.bytes[ir.byteIndex], ir.bytes[ir.byteIndex+1], ir.bytes[ir.byteIndex+2])
+	ir.byteIndex += 3
+
+	if segmentSize%itemSize != 0 {
+		ir.corrupted()
+	}
+	ir.require(segmentSize, len(ir.bytes))
+
+	return ir.byteIndex + segmentSize
+}
+
 func (ir *InstructionReader) readConstants() {
+	ir.require(4, len(ir.bytes))
 	ir.byteIndex += 4
 
 	ir.readStringConstants()
+	ir.ConstantCounts[0] = len(ir.virtualMachine.Constants)
+
 	ir.readIntConstants()
+	ir.ConstantCounts[1] = len(ir.virtualMachine.Constants) - ir.ConstantCounts[0]
+
 	ir.readFloatConstants()
+	ir.ConstantCounts[2] = len(ir.virtualMachine.Constants) - ir.ConstantCounts[0] - ir.ConstantCounts[1]
 }
 
 func (ir *InstructionReader) readStringConstants() {
-	ir.byteIndex++
-
-	// Collect header
-	segmentSize := byte3ToInt(ir.bytes[ir.byteIndex], ir.bytes[ir.byteIndex+1], ir.bytes[ir.byteIndex+2])
-	ir.byteIndex += 3
-
-	segmentEnd := ir.byteIndex + segmentSize
+	segmentEnd := ir.readSegmentHeader(1)
 
 	// Collect strings
 	str := []byte{}
@@ -97,13 +132,7 @@ func (ir *InstructionReader) readStringConstants() {
 }
 
 func (ir *InstructionReader) readIntConstants() {
-	ir.byteIndex++
-
-	// Collect header
-	segmentSize := byte3ToInt(ir.bytes[ir.byteIndex], ir.bytes[ir.byteIndex+1], ir.bytes[ir.byteIndex+2])
-	ir.byteIndex += 3
-
-	segmentEnd := ir.byteIndex + segmentSize
+	segmentEnd := ir.readSegmentHeader(8)
 
 	// Collect ints
 	for ir.byteIndex < segmentEnd {
@@ -115,13 +144,7 @@ func (ir *InstructionReader) readIntConstants() {
 }
 
 func (ir *InstructionReader) readFloatConstants() {
-	ir.byteIndex++
-
-	// Collect header
-	segmentSize := byte3ToInt(ir.bytes[ir.byteIndex], ir.bytes[ir.byteIndex+1], ir.bytes[ir.byteIndex+2])
-	ir.byteIndex += 3
-
-	segmentEnd := ir.byteIndex + segmentSize
+	segmentEnd := ir.readSegmentHeader(8)
 
 	// Collect floats
 	for ir.byteIndex < segmentEnd {
@@ -133,6 +156,7 @@ func (ir *InstructionReader) readFloatConstants() {
 }
 
 func (ir *InstructionReader) readCode() {
+	ir.require(4, len(ir.bytes))
 	ir.byteIndex += 4
 
 	ir.readGlobals()
@@ -141,26 +165,18 @@ func (ir *InstructionReader) readCode() {
 }
 
 func (ir *InstructionReader) readGlobals() {
-	ir.byteIndex++
+	endIndex := ir.readSegmentHeader(1)
 
-	// Collect header
-	codeSize := byte3ToInt(ir.bytes[ir.byteIndex], ir.bytes[ir.byteIndex+1], ir.bytes[ir.byteIndex+2])
-	ir.byteIndex += 3
-
-	endIndex := ir.byteIndex + codeSize
-
-	ir.readInstructions(&ir.virtualMachine.GlobalsInstructions, endIndex)
+	ir.readInstructions(&ir.virtualMachine.GlobalsInstructions, &ir.GlobalsOffsets, endIndex)
 }
 
 func (ir *InstructionReader) readFunctionIndexes() {
-	ir.byteIndex++
-
-	// Collect header
-	functionsSize := byte3ToInt(ir.bytes[ir.byteIndex], ir.bytes[ir.byteIndex+1], ir.bytes[ir.byteIndex+2])
-	ir.byteIndex += 3
+	// Every function has one byte
+	functionsSize := ir.readSegmentHeader(1) - ir.byteIndex
 
 	// Allocate slice
 	ir.virtualMachine.functions = make([]int, functionsSize)
+	ir.Functions = ir.virtualMachine.functions
 
 	lastFunction := 0
 	for i := 0; i < functionsSize; i++ {
@@ -171,28 +187,26 @@ func (ir *InstructionReader) readFunctionIndexes() {
 }
 
 func (ir *InstructionReader) readFunctions() {
-	ir.byteIndex++
-
-	// Collect header
-	codeSize := byte3ToInt(ir.bytes[ir.byteIndex], ir.bytes[ir.byteIndex+1], ir.bytes[ir.byteIndex+2])
-	ir.byteIndex += 3
-
-	endIndex := ir.byteIndex + codeSize
+	endIndex := ir.readSegmentHeader(1)
 
-	ir.readInstructions(&ir.virtualMachine.FunctionsInstructions, endIndex)
+	ir.readInstructions(&ir.virtualMachine.FunctionsInstructions, &ir.FunctionsOffsets, endIndex)
 }
 
-func (ir *InstructionReader) readInstructions(target *[]ExpandedInstruction, endIndex int) {
+// Reads instructions until endIndex. Byte offset of every read instruction is appended to offsets.
+func (ir *InstructionReader) readInstructions(target *[]ExpandedInstruction, offsets *[]int, endIndex int) {
 	for ir.byteIndex < endIndex {
 		instructionType := ir.bytes[ir.byteIndex]
+		*offsets = append(*offsets, ir.byteIndex)
 
 		// 1 argument 2 byte instruction
 		if instructionType <= IT_JumpIfTrueEx {
+			ir.require(3, endIndex)
 			ir.byteIndex++
 			*target = append(*target, ExpandedInstruction{instructionType, []int{int(binary.LittleEndian.Uint16([]byte{ir.bytes[ir.byteIndex], ir.bytes[ir.byteIndex+1]}))}})
 			ir.byteIndex++
 			// 1 argument 1 byte instruction
 		} else if instructionType <= IT_JumpIfTrue {
+			ir.require(2, endIndex)
 			ir.byteIndex++
 
 			// Declarator of composite variable
@@ -200,14 +214,18 @@ func (ir *InstructionReader) readInstructions(target *[]ExpandedInstruction, end
 				// Add declare list instruction with id argument
 				*target = append(*target, ExpandedInstruction{instructionType, []int{int(ir.bytes[ir.byteIndex])}})
 				ir.byteIndex++
+				ir.require(1, endIndex)
 
 				// Add instructions for declaring composite sub-types types without arguments
 				for IsCompositeDeclarator(ir.bytes[ir.byteIndex]) {
 					*target = append(*target, ExpandedInstruction{ir.bytes[ir.byteIndex], NO_ARGS})
+					*offsets = append(*offsets, ir.byteIndex)
 					ir.byteIndex++
+					ir.require(1, endIndex)
 				}
 
 				// Add inner-most type instructions without arguments
+				*offsets = append(*offsets, ir.byteIndex)
 				*target = append(*target, ExpandedInstruction{ir.bytes[ir.byteIndex], NO_ARGS})
 
 				// Normal instruction
@@ -226,3 +244,28 @@ func (ir *InstructionReader) readInstructions(target *[]ExpandedInstruction, end
 		ir.byteIndex++
 	}
 }
+
+// Appends generated instructions to expanded instructions, the same way they would be read from a binary.
+func expandInstructions(target []ExpandedInstruction, instructions []Instruction) []ExpandedInstruction {
+	for _, instruction := range instructions {
+		switch {
+		// Skip instruction removed by code generator or optimizer
+		case instruction.InstructionType == 255:
+			continue
+
+		// 1 argument 2 byte instruction
+		case len(instruction.InstructionValue) == 2:
+			target = append(target, ExpandedInstruction{instruction.InstructionType, []int{int(binary.LittleEndian.Uint16(instruction.InstructionValue))}})
+
+		// 1 argument 1 byte instruction
+		case len(instruction.InstructionValue) == 1:
+			target = append(target, ExpandedInstruction{instruction.InstructionType, []int{int(instruction.InstructionValue[0])}})
+
+		// 0 argument instruction
+		default:
+			target = append(target, ExpandedInstruction{instruction.InstructionType, NO_ARGS})
+		}
+	}
+
+	return target
+}
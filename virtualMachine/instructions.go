@@ -15,6 +15,7 @@ const (
 
 	IT_Call
 	IT_CallBuiltInFunc
+	IT_CreateClosure
 	IT_PushScope
 
 	IT_DeclareBool
@@ -25,8 +26,12 @@ const (
 	IT_DeclareSet
 	IT_DeclareObject
 	IT_DeclareOption
+	IT_DeclareMap
+	IT_DeclareFunction
+	IT_DeclareAny
 
 	IT_SetListAtAToB // B, A <- TOP
+	IT_SetMapAtAToB  // B, A <- TOP
 
 	IT_LoadConst
 	IT_LoadConstToList
@@ -39,6 +44,12 @@ const (
 	IT_GetFieldAndPop
 	IT_SetField
 
+	IT_CreateVariant
+	IT_TestVariant
+	IT_GetPayloadAndPop
+
+	IT_RangeContains
+
 	IT_JumpBack
 	IT_Jump
 	IT_JumpIfFalse
@@ -65,6 +76,7 @@ const (
 	IT_StringConcat
 	IT_ListConcat
 
+	IT_CallValue
 	IT_Return
 
 	IT_Equal
@@ -86,6 +98,7 @@ const (
 	IT_PopScope
 
 	IT_AddField
+	IT_AddPayload
 
 	IT_CreateList
 	IT_AppendToList
@@ -93,21 +106,36 @@ const (
 	IT_ListContains
 	IT_RemoveListElement
 	IT_SetListAt
+	IT_SliceList
 
 	IT_IndexString
+	IT_SliceString
 
 	IT_CreateSet
 	IT_InsertToSet
 	IT_SetContains
 	IT_RemoveSetElement
 
+	IT_CreateMap
+	IT_InsertToMap
+	IT_IndexMap
+	IT_MapContains
+	IT_RemoveMapElement
+
 	IT_PanicIfNone
 
+	IT_PushHandler
+	IT_PopHandler
+	IT_Throw
+
 	IT_Pop
 	IT_DuplicateTop
 
 	IT_UnpackOrDefault
 
+	IT_PushArguments
+	IT_Exit
+
 	IT_LineOffset
 )
 
@@ -124,18 +152,23 @@ var InstructionTypeToString = map[byte]string{
 
 	IT_Call:            "call",
 	IT_CallBuiltInFunc: "call_builtin",
+	IT_CreateClosure:   "closure",
 	IT_PushScope:       "push_scope",
 
-	IT_DeclareBool:   "decl_bool",
-	IT_DeclareInt:    "decl_int",
-	IT_DeclareFloat:  "decl_float",
-	IT_DeclareString: "decl_string",
-	IT_DeclareList:   "decl_list",
-	IT_DeclareSet:    "decl_set",
-	IT_DeclareObject: "decl_object",
-	IT_DeclareOption: "decl_option",
+	IT_DeclareBool:     "decl_bool",
+	IT_DeclareInt:      "decl_int",
+	IT_DeclareFloat:    "decl_float",
+	IT_DeclareString:   "decl_string",
+	IT_DeclareList:     "decl_list",
+	IT_DeclareSet:      "decl_set",
+	IT_DeclareObject:   "decl_object",
+	IT_DeclareOption:   "decl_option",
+	IT_DeclareMap:      "decl_map",
+	IT_DeclareFunction: "decl_fun",
+	IT_DeclareAny:      "decl_any",
 
 	IT_SetListAtAToB: "set_list",
+	IT_SetMapAtAToB:  "set_map",
 
 	IT_LoadConst:       "load_const",
 	IT_LoadConstToList: "append_const",
@@ -148,6 +181,12 @@ var InstructionTypeToString = map[byte]string{
 	IT_GetFieldAndPop: "field_get_pop",
 	IT_SetField:       "field_set",
 
+	IT_CreateVariant:    "new_variant",
+	IT_TestVariant:      "variant_test",
+	IT_GetPayloadAndPop: "payload_get_pop",
+
+	IT_RangeContains: "range_contains",
+
 	IT_JumpBack:    "jmp_back",
 	IT_Jump:        "jmp",
 	IT_JumpIfFalse: "jmp_if_0",
@@ -174,7 +213,8 @@ var InstructionTypeToString = map[byte]string{
 	IT_StringConcat: "string_concat",
 	IT_ListConcat:   "list_concat",
 
-	IT_Return: "return",
+	IT_CallValue: "call_value",
+	IT_Return:    "return",
 
 	IT_Equal:             "equal",
 	IT_IntLower:          "int_lwr",
@@ -194,28 +234,44 @@ var InstructionTypeToString = map[byte]string{
 	IT_PushScopeUnnamed: "push_scope_unnamed",
 	IT_PopScope:         "pop_scope",
 
-	IT_AddField: "field_add",
+	IT_AddField:   "field_add",
+	IT_AddPayload: "payload_add",
 
 	IT_CreateList:        "list_new",
 	IT_AppendToList:      "list_append",
 	IT_IndexList:         "list_index",
 	IT_ListContains:      "list_contains",
 	IT_RemoveListElement: "list_remove",
+	IT_SliceList:         "list_slice",
 
 	IT_IndexString: "str_index",
+	IT_SliceString: "str_slice",
 
 	IT_CreateSet:        "set_new",
 	IT_InsertToSet:      "set_insert",
 	IT_SetContains:      "set_contains",
 	IT_RemoveSetElement: "set_remove",
 
+	IT_CreateMap:        "map_new",
+	IT_InsertToMap:      "map_insert",
+	IT_IndexMap:         "map_index",
+	IT_MapContains:      "map_contains",
+	IT_RemoveMapElement: "map_remove",
+
 	IT_PanicIfNone: "panic_if_none",
 
+	IT_PushHandler: "handler_push",
+	IT_PopHandler:  "handler_pop",
+	IT_Throw:       "throw",
+
 	IT_Pop:          "pop",
 	IT_DuplicateTop: "duplicate",
 
 	IT_UnpackOrDefault: "unpack_or_def",
 
+	IT_PushArguments: "push_arguments",
+	IT_Exit:          "exit",
+
 	IT_LineOffset: "line_offset",
 }
 
@@ -0,0 +1,413 @@
+package virtualMachine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Type of converted value created by the parser. Format of schemas is described in parser/json.go.
+type jsonSchema struct {
+	kind    byte
+	element *jsonSchema // Element of options, lists and sets, value of maps
+	name    string      // Name of struct or enum
+	fields  []jsonField // Fields of objects ordered by their numbers
+	values  []int64     // Constants of enums
+}
+
+type jsonField struct {
+	name   string
+	schema *jsonSchema
+}
+
+// Returns parsed schema. Schemas are parsed once and cached.
+func (vm *VirtualMachine) jsonSchema(schema string) *jsonSchema {
+	if parsed, exists := vm.jsonSchemas[schema]; exists {
+		return parsed
+	}
+
+	index := 0
+	parsed := parseJsonSchema(schema, &index, &[]*jsonSchema{})
+	vm.jsonSchemas[schema] = parsed
+
+	return parsed
+}
+
+func parseJsonSchema(schema string, index *int, objects *[]*jsonSchema) *jsonSchema {
+	parsed := &jsonSchema{kind: schema[*index]}
+	*index++
+
+	switch parsed.kind {
+	case 'e':
+		end := *index + strings.IndexByte(schema[*index:], ':')
+		parsed.name = schema[*index:end]
+		*index = end + 1
+
+		end = *index + strings.IndexByte(schema[*index:], ';')
+
+		for _, value := range strings.Split(schema[*index:end], ",") {
+			constant, _ := strconv.ParseInt(value, 10, 64)
+			parsed.values = append(parsed.values, constant)
+		}
+		*index = end + 1
+
+	case '?', '[', '#', '{':
+		parsed.element = parseJsonSchema(schema, index, objects)
+
+	case 'o':
+		*objects = append(*objects, parsed)
+
+		end := *index + strings.IndexByte(schema[*index:], '(')
+		parsed.name = schema[*index:end]
+		*index = end + 1
+
+		for schema[*index] != ')' {
+			if schema[*index] == ',' {
+				*index++
+			}
+
+			end = *index + strings.IndexByte(schema[*index:], ':')
+			name := schema[*index:end]
+			*index = end + 1
+
+			parsed.fields = append(parsed.fields, jsonField{name, parseJsonSchema(schema, index, objects)})
+		}
+		*index++
+
+	// Reference to already parsed object
+	case '@':
+		end := *index + strings.IndexByte(schema[*index:], ';')
+		objectIndex, _ := strconv.Atoi(schema[*index:end])
+		*index = end + 1
+
+		return (*objects)[objectIndex]
+	}
+
+	return parsed
+}
+
+// Returns readable name of type described by schema.
+func (schema *jsonSchema) String() string {
+	switch schema.kind {
+	case 'b':
+		return "bool"
+	case 'i':
+		return "int"
+	case 'f':
+		return "flt"
+	case 's':
+		return "str"
+	case '?':
+		return schema.element.String() + "?"
+	case '[':
+		return "list<" + schema.element.String() + ">"
+	case '#':
+		return "set<" + schema.element.String() + ">"
+	case '{':
+		return "map<str, " + schema.element.String() + ">"
+	default:
+		return schema.name
+	}
+}
+
+// ENCODING -------------------------------------------------------------------------------------------------
+
+func (vm *VirtualMachine) toJson(value any, schema *jsonSchema) string {
+	builder := &strings.Builder{}
+	vm.writeJson(builder, value, schema)
+
+	return builder.String()
+}
+
+func (vm *VirtualMachine) writeJson(builder *strings.Builder, value any, schema *jsonSchema) {
+	switch schema.kind {
+	case 'b':
+		builder.WriteString(strconv.FormatBool(value.(bool)))
+
+	case 'i', 'e':
+		builder.WriteString(strconv.FormatInt(value.(int64), 10))
+
+	case 'f':
+		float := value.(float64)
+		if math.IsNaN(float) || math.IsInf(float, 0) {
+			vm.panic(fmt.Sprintf("Can't convert %v to JSON.", float))
+		}
+		builder.WriteString(strconv.FormatFloat(float, 'g', -1, 64))
+
+	case 's':
+		builder.WriteString(quoteJson(value.(string)))
+
+	case '?':
+		if value == nil {
+			builder.WriteString("null")
+		} else {
+			vm.writeJson(builder, value, schema.element)
+		}
+
+	case '[':
+		builder.WriteByte('[')
+		for i, element := range value.([]any) {
+			if i != 0 {
+				builder.WriteByte(',')
+			}
+			vm.writeJson(builder, element, schema.element)
+		}
+		builder.WriteByte(']')
+
+	// Sets are sorted, so their JSON is deterministic
+	case '#':
+		elements := []any{}
+		for element := range value.(map[any]struct{}) {
+			elements = append(elements, element)
+		}
+		slices.SortFunc(elements, compareSetElements)
+
+		vm.writeJson(builder, elements, &jsonSchema{kind: '[', element: schema.element})
+
+	case '{':
+		valueMap := value.(*orderedMap)
+
+		builder.WriteByte('{')
+		for i, key := range valueMap.keys {
+			if i != 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteString(quoteJson(key.(string)))
+			builder.WriteByte(':')
+			vm.writeJson(builder, valueMap.values[key], schema.element)
+		}
+		builder.WriteByte('}')
+
+	case 'o':
+		fields := value.(object).fields
+
+		builder.WriteByte('{')
+		for i, field := range schema.fields {
+			if i != 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteString(quoteJson(field.name))
+			builder.WriteByte(':')
+			vm.writeJson(builder, fields[i], field.schema)
+		}
+		builder.WriteByte('}')
+	}
+}
+
+// Creates JSON string literal. Unlike json.Marshal, it doesn't escape HTML characters.
+func quoteJson(text string) string {
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(text)
+
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+// Compares set elements, which can be bools, ints, floats or strings.
+func compareSetElements(a, b any) int {
+	if a, isBool := a.(bool); isBool {
+		if a == b.(bool) {
+			return 0
+		} else if a {
+			return 1
+		}
+		return -1
+	}
+
+	return compareOrdered(a, b)
+}
+
+// DECODING -------------------------------------------------------------------------------------------------
+
+// Decodes JSON text to a value of type described by schema. JSON null is decoded as none.
+func (vm *VirtualMachine) fromJson(text string, schema *jsonSchema) any {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+
+	value := vm.readJson(decoder, schema, "$")
+
+	// Text has to contain only one value
+	if _, err := decoder.Token(); err != io.EOF {
+		if err == nil {
+			err = errors.New("unexpected data after JSON value")
+		}
+		vm.invalidJson(err)
+	}
+
+	return value
+}
+
+func (vm *VirtualMachine) readJson(decoder *json.Decoder, schema *jsonSchema, path string) any {
+	token, err := decoder.Token()
+	if err != nil {
+		vm.invalidJson(err)
+	}
+
+	return vm.decodeJson(decoder, token, schema, path)
+}
+
+// Decodes value starting with token. Values of composite types are read from decoder.
+func (vm *VirtualMachine) decodeJson(decoder *json.Decoder, token json.Token, schema *jsonSchema, path string) any {
+	switch schema.kind {
+	case 'b':
+		if boolean, isBool := token.(bool); isBool {
+			return boolean
+		}
+
+	case 'i':
+		if number, isNumber := token.(json.Number); isNumber {
+			if integer, err := strconv.ParseInt(number.String(), 10, 64); err == nil {
+				return integer
+			}
+		}
+
+	case 'e':
+		if number, isNumber := token.(json.Number); isNumber {
+			if integer, err := strconv.ParseInt(number.String(), 10, 64); err == nil && slices.Contains(schema.values, integer) {
+				return integer
+			}
+		}
+
+	case 'f':
+		if number, isNumber := token.(json.Number); isNumber {
+			if float, err := number.Float64(); err == nil {
+				return float
+			}
+		}
+
+	case 's':
+		if text, isString := token.(string); isString {
+			return text
+		}
+
+	// Null is none
+	case '?':
+		if token == nil {
+			return nil
+		}
+		return vm.decodeJson(decoder, token, schema.element, path)
+
+	case '[', '#':
+		if token != json.Delim('[') {
+			break
+		}
+
+		list := []any{}
+		for decoder.More() {
+			list = append(list, vm.readJson(decoder, schema.element, fmt.Sprintf("%s[%d]", path, len(list))))
+		}
+		decoder.Token() // ]
+
+		if schema.kind == '[' {
+			return list
+		}
+
+		set := map[any]struct{}{}
+		for _, element := range list {
+			set[element] = struct{}{}
+		}
+		return set
+
+	case '{':
+		if token != json.Delim('{') {
+			break
+		}
+
+		valueMap := newOrderedMap()
+		for decoder.More() {
+			key := vm.readJsonKey(decoder)
+			valueMap.Set(key, vm.readJson(decoder, schema.element, path+"."+key))
+		}
+		decoder.Token() // }
+
+		return valueMap
+
+	case 'o':
+		if token != json.Delim('{') {
+			break
+		}
+
+		fields := make([]any, len(schema.fields))
+		found := make([]bool, len(schema.fields))
+
+		for decoder.More() {
+			key := vm.readJsonKey(decoder)
+			index := slices.IndexFunc(schema.fields, func(field jsonField) bool { return field.name == key })
+
+			// Unknown fields are skipped
+			if index == -1 {
+				var skipped json.RawMessage
+				if err := decoder.Decode(&skipped); err != nil {
+					vm.invalidJson(err)
+				}
+				continue
+			}
+
+			fields[index] = vm.readJson(decoder, schema.fields[index].schema, path+"."+key)
+			found[index] = true
+		}
+		decoder.Token() // }
+
+		// Missing fields are none if they are options
+		for i, field := range schema.fields {
+			if !found[i] && field.schema.kind != '?' {
+				vm.panic(fmt.Sprintf("Can't decode JSON. Field %s.%s of type %s is missing.", path, field.name, field.schema))
+			}
+		}
+
+		name := schema.name
+		return object{&name, fields}
+	}
+
+	vm.jsonMismatch(schema, path, token)
+	return nil
+}
+
+func (vm *VirtualMachine) readJsonKey(decoder *json.Decoder) string {
+	token, err := decoder.Token()
+	if err != nil {
+		vm.invalidJson(err)
+	}
+
+	return token.(string)
+}
+
+func (vm *VirtualMachine) invalidJson(err error) {
+	if err == io.EOF {
+		err = errors.New("unexpected end of text")
+	}
+
+	reason := []rune(err.Error())
+	vm.panic(fmt.Sprintf("Can't decode invalid JSON. %c%s.", unicode.ToUpper(reason[0]), string(reason[1:])))
+}
+
+func (vm *VirtualMachine) jsonMismatch(schema *jsonSchema, path string, token json.Token) {
+	found := ""
+
+	switch token := token.(type) {
+	case nil:
+		found = "null"
+	case bool:
+		found = strconv.FormatBool(token)
+	case json.Number:
+		found = token.String()
+	case string:
+		found = quoteJson(token)
+	case json.Delim:
+		if token == '{' {
+			found = "object"
+		} else {
+			found = "array"
+		}
+	}
+
+	vm.panic(fmt.Sprintf("Can't decode JSON. Expected %s at %s, found %s.", schema, path, found))
+}
@@ -0,0 +1,63 @@
+package virtualMachine
+
+// Map that keeps it's keys in order of insertion, so iteration and printing are deterministic.
+type orderedMap struct {
+	keys   []any
+	values map[any]any
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{[]any{}, map[any]any{}}
+}
+
+func (m *orderedMap) Set(key, value any) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *orderedMap) Get(key any) (any, bool) {
+	value, exists := m.values[key]
+	return value, exists
+}
+
+func (m *orderedMap) Contains(key any) bool {
+	_, exists := m.values[key]
+	return exists
+}
+
+func (m *orderedMap) Remove(key any) {
+	if _, exists := m.values[key]; !exists {
+		return
+	}
+
+	delete(m.values, key)
+
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+func (m *orderedMap) Size() int {
+	return len(m.keys)
+}
+
+// Returns a copy of the map's keys.
+func (m *orderedMap) Keys() []any {
+	keys := make([]any, len(m.keys))
+	copy(keys, m.keys)
+	return keys
+}
+
+// Returns map's values in the order of it's keys.
+func (m *orderedMap) Values() []any {
+	values := make([]any, len(m.keys))
+	for i, key := range m.keys {
+		values[i] = m.values[key]
+	}
+	return values
+}
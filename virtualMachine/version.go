@@ -2,6 +2,6 @@ package virtualMachine
 
 const (
 	VERSION_MAJOR = 0
-	VERSION_MINOR = 1
+	VERSION_MINOR = 2
 	VERSION_PATCH = 0
 )
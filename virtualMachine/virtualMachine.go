@@ -6,6 +6,9 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
 
 	data "github.com/DanielNos/neco/dataStructures"
 )
@@ -15,14 +18,18 @@ const (
 	STACK_RETURN_INDEX_SIZE = 1024
 	STACK_SCOPES_SIZE       = 256
 	SYMBOL_MAP_SIZE         = 100
+	MAX_STRING_SIZE         = 1 << 30 // Maximum size of strings created by repeating or padding in bytes
 )
 
 var InstructionToDataType = map[byte]data.PrimitiveType{
-	IT_DeclareBool:   data.DT_Bool,
-	IT_DeclareInt:    data.DT_Int,
-	IT_DeclareFloat:  data.DT_Float,
-	IT_DeclareString: data.DT_String,
-	IT_DeclareList:   data.DT_List,
+	IT_DeclareBool:     data.DT_Bool,
+	IT_DeclareInt:      data.DT_Int,
+	IT_DeclareFloat:    data.DT_Float,
+	IT_DeclareString:   data.DT_String,
+	IT_DeclareList:     data.DT_List,
+	IT_DeclareMap:      data.DT_Map,
+	IT_DeclareFunction: data.DT_Function,
+	IT_DeclareAny:      data.DT_Any,
 }
 
 type VirtualMachine struct {
@@ -38,8 +45,9 @@ type VirtualMachine struct {
 
 	stack *Stack[any]
 
-	reg_returnIndex     int
-	stack_returnIndexes []int
+	reg_returnIndex          int
+	stack_returnIndexes      []int
+	stack_returnSymbolTables []*data.StackNode
 
 	reg_scopeIndex int
 	stack_scopes   []string
@@ -47,18 +55,25 @@ type VirtualMachine struct {
 	reg_symbolIndex    int
 	stack_symbolTables *data.Stack
 
-	filePath string
-	reader   *bufio.Reader
+	stack_handlers []handler
+
+	filePath  string
+	arguments []string // Program arguments passed to entry function
+	reader    *bufio.Reader
+	startTime time.Time // Start of execution, used as origin of monotonic clock
+
+	jsonSchemas map[string]*jsonSchema // Parsed schemas of values converted to and from JSON
 }
 
-func NewVirtualMachine(filePath string) *VirtualMachine {
+func NewVirtualMachine(filePath string, arguments []string) *VirtualMachine {
 	virtualMachine := &VirtualMachine{
 		instructionIndex: 0,
 
 		stack: NewStack[any](STACK_SIZE),
 
-		reg_returnIndex:     0,
-		stack_returnIndexes: make([]int, STACK_RETURN_INDEX_SIZE),
+		reg_returnIndex:          0,
+		stack_returnIndexes:      make([]int, STACK_RETURN_INDEX_SIZE),
+		stack_returnSymbolTables: make([]*data.StackNode, STACK_RETURN_INDEX_SIZE),
 
 		reg_scopeIndex: 0,
 		stack_scopes:   make([]string, STACK_SCOPES_SIZE),
@@ -66,8 +81,14 @@ func NewVirtualMachine(filePath string) *VirtualMachine {
 		reg_symbolIndex:    0,
 		stack_symbolTables: data.NewStack(),
 
-		filePath: filePath,
-		reader:   bufio.NewReader(os.Stdin),
+		stack_handlers: []handler{},
+
+		filePath:  filePath,
+		arguments: arguments,
+		reader:    bufio.NewReader(os.Stdin),
+		startTime: time.Now(),
+
+		jsonSchemas: map[string]*jsonSchema{},
 	}
 
 	virtualMachine.stack_symbolTables.Push(NewSymbolMap(SYMBOL_MAP_SIZE))
@@ -87,6 +108,32 @@ type object struct {
 	fields     []any
 }
 
+// Value of an enum with payloads. Tag is the value of it's variant.
+type variant struct {
+	identifier *string
+	tag        int64
+	payload    []any
+}
+
+// State of the virtual machine at the start of a try block. Thrown errors restore it and jump to the catch block.
+type handler struct {
+	catchIndex       int
+	instructions     *[]ExpandedInstruction
+	stackSize        int
+	returnIndex      int
+	scopeIndex       int
+	symbolTables     *data.StackNode
+	symbolTableCount int
+}
+
+var errorIdentifier = "Error"
+
+// Function value with symbol tables of the scope it was created in.
+type closure struct {
+	function    int
+	environment *data.StackNode
+}
+
 // Reads bytecode from file and runs it.
 func (vm *VirtualMachine) Execute() {
 	// Read instructions
@@ -101,15 +148,84 @@ func (vm *VirtualMachine) Execute() {
 
 	// Interpret instructions
 	vm.instructions = &vm.GlobalsInstructions
-	for vm.instructionIndex < len(vm.GlobalsInstructions) {
-		vm.executeInstruction()
-	}
+	vm.run()
 
 	vm.instructions = &vm.FunctionsInstructions
 	vm.instructionIndex = 0
-	for vm.instructionIndex < len(vm.FunctionsInstructions) {
+	vm.run()
+}
+
+// Executes input of an interactive session. Functions of the input are added after functions of previous inputs and
+// it's statements are executed after them. Errors which aren't caught by the input are printed and execution of the session continues.
+func (vm *VirtualMachine) ExecuteInput(constants []any, functions []Instruction, functionStarts []int, statements []Instruction) {
+	vm.Constants = constants
+
+	// Inputs are executed like a body of the entry function, because returning to the root scope ends the program
+	if vm.reg_scopeIndex == 0 {
+		for vm.reg_scopeIndex < 2 {
+			vm.stack_scopes[vm.reg_scopeIndex] = vm.filePath
+			vm.reg_scopeIndex++
+
+			vm.stack_symbolTables.Push(NewSymbolMap(SYMBOL_MAP_SIZE))
+		}
+		vm.reg_returnIndex = 1
+	}
+
+	// Calls and returns jump only inside of current instructions, so statements are stored with the functions
+	functionsStart := len(vm.FunctionsInstructions)
+	for _, functionStart := range functionStarts {
+		vm.functions = append(vm.functions, functionsStart+functionStart)
+	}
+
+	vm.FunctionsInstructions = expandInstructions(vm.FunctionsInstructions, functions)
+	vm.instructionIndex = len(vm.FunctionsInstructions)
+
+	vm.FunctionsInstructions = expandInstructions(vm.FunctionsInstructions, statements)
+	vm.instructions = &vm.FunctionsInstructions
+
+	// Uncaught errors jump to the end of the statements
+	stackSize := vm.stack.size
+	vm.stack_handlers = append(vm.stack_handlers, handler{len(vm.FunctionsInstructions), vm.instructions, stackSize, vm.reg_returnIndex, vm.reg_scopeIndex, vm.stack_symbolTables.Top, vm.stack_symbolTables.Size})
+	handlerCount := len(vm.stack_handlers)
+
+	vm.run()
+
+	if len(vm.stack_handlers) == handlerCount {
+		vm.stack_handlers = vm.stack_handlers[:handlerCount-1]
+	} else {
+		thrownError := vm.stack.Pop().(object)
+		fmt.Println("\033[91mPanic: " + thrownError.fields[0].(string) + "\033[0m")
+		fmt.Print(thrownError.fields[1].(string))
+	}
+
+	// Discard values which weren't used by statements
+	vm.stack.size = stackSize
+}
+
+// Returns reader of standard input used by the program. Interactive sessions read their input from it too.
+func (vm *VirtualMachine) InputReader() *bufio.Reader {
+	return vm.reader
+}
+
+// Executes instructions until the end of current instructions. Thrown errors are passed to their handlers.
+func (vm *VirtualMachine) run() {
+	for !vm.runUntilThrow() {
+	}
+}
+
+// Executes instructions until the end of current instructions or until an error is thrown. Returns true if the end was reached.
+func (vm *VirtualMachine) runUntilThrow() bool {
+	defer func() {
+		if thrown := recover(); thrown != nil {
+			vm.catch(thrown)
+		}
+	}()
+
+	for vm.instructionIndex < len(*vm.instructions) {
 		vm.executeInstruction()
 	}
+
+	return true
 }
 
 // Executes current instruction and prints debugging information
@@ -179,8 +295,9 @@ func (vm *VirtualMachine) executeInstruction() {
 
 	// Call functions
 	case IT_Call:
-		// Push return address to stack
+		// Push return address and symbol tables to stack
 		vm.stack_returnIndexes[vm.reg_returnIndex] = vm.instructionIndex + 1
+		vm.stack_returnSymbolTables[vm.reg_returnIndex] = vm.stack_symbolTables.Top
 		vm.reg_returnIndex++
 
 		// Return address stack overflow
@@ -195,6 +312,9 @@ func (vm *VirtualMachine) executeInstruction() {
 	case IT_CallBuiltInFunc:
 		vm.callBuiltInFunction(instruction.InstructionValue[0])
 
+	case IT_CreateClosure:
+		vm.stack.Push(closure{instruction.InstructionValue[0], vm.stack_symbolTables.Top})
+
 	case IT_PushScope:
 		vm.stack_scopes[vm.reg_scopeIndex] = vm.Constants[instruction.InstructionValue[0]].(string)
 		vm.reg_scopeIndex++
@@ -239,10 +359,23 @@ func (vm *VirtualMachine) executeInstruction() {
 	case IT_DeclareOption:
 		vm.stack_symbolTables.Top.Value.(*SymbolMap).Insert(instruction.InstructionValue[0], &Symbol{ST_Variable, &VariableSymbol{data.DataType{data.DT_Option, nil}, nil}})
 
+	case IT_DeclareMap:
+		vm.stack_symbolTables.Top.Value.(*SymbolMap).Insert(instruction.InstructionValue[0], &Symbol{ST_Variable, &VariableSymbol{data.DataType{data.DT_Map, nil}, newOrderedMap()}})
+
+	case IT_DeclareFunction:
+		vm.stack_symbolTables.Top.Value.(*SymbolMap).Insert(instruction.InstructionValue[0], &Symbol{ST_Variable, &VariableSymbol{data.DataType{data.DT_Function, nil}, nil}})
+
+	case IT_DeclareAny:
+		vm.stack_symbolTables.Top.Value.(*SymbolMap).Insert(instruction.InstructionValue[0], &Symbol{ST_Variable, &VariableSymbol{data.DataType{data.DT_Any, nil}, nil}})
+
 	// Set and load list at index
 	case IT_SetListAtAToB:
 		vm.findSymbol().symbolValue.(*VariableSymbol).value.([]any)[vm.stack.Pop().(int64)] = vm.stack.Pop()
 
+	// Set map value at key
+	case IT_SetMapAtAToB:
+		vm.findSymbol().symbolValue.(*VariableSymbol).value.(*orderedMap).Set(vm.stack.Pop(), vm.stack.Pop())
+
 	// Load and store
 	case IT_LoadConst:
 		vm.stack.Push(vm.Constants[instruction.InstructionValue[0]])
@@ -274,6 +407,21 @@ func (vm *VirtualMachine) executeInstruction() {
 		vm.stack.size--
 		vm.stack.items[vm.stack.size-1].(object).fields[instruction.InstructionValue[0]] = vm.stack.items[vm.stack.size]
 
+	// Tagged unions
+	case IT_CreateVariant:
+		identifier := vm.Constants[instruction.InstructionValue[0]].(string)
+		vm.stack.items[vm.stack.size-1] = variant{&identifier, vm.stack.items[vm.stack.size-1].(int64), []any{}}
+
+	case IT_TestVariant:
+		vm.stack.items[vm.stack.size-1] = vm.stack.items[vm.stack.size-1].(variant).tag == vm.Constants[instruction.InstructionValue[0]].(int64)
+
+	case IT_GetPayloadAndPop:
+		vm.stack.items[vm.stack.size-1] = vm.stack.items[vm.stack.size-1].(variant).payload[instruction.InstructionValue[0]]
+
+	case IT_RangeContains:
+		vm.stack.size -= 3
+		vm.stack.items[vm.stack.size-1] = vm.rangeContains(vm.stack.items[vm.stack.size-1].(int64), vm.stack.items[vm.stack.size].(int64), vm.stack.items[vm.stack.size+1].(int64), vm.stack.items[vm.stack.size+2], instruction.InstructionValue[0] == 1)
+
 	// NO ARGUMENT INSTRUCTIONS -------------------------------------------------------------------------
 
 	// Integer operations
@@ -344,16 +492,36 @@ func (vm *VirtualMachine) executeInstruction() {
 		vm.stack.size--
 		vm.stack.items[vm.stack.size-1] = append(vm.stack.items[vm.stack.size-1].([]any), vm.stack.items[vm.stack.size].([]any)...)
 
+	// Call closure from the top of the stack
+	case IT_CallValue:
+		currentClosure := vm.stack.Pop().(closure)
+
+		// Push return address and symbol tables to stack
+		vm.stack_returnIndexes[vm.reg_returnIndex] = vm.instructionIndex + 1
+		vm.stack_returnSymbolTables[vm.reg_returnIndex] = vm.stack_symbolTables.Top
+		vm.reg_returnIndex++
+
+		// Return address stack overflow
+		if vm.reg_returnIndex == STACK_RETURN_INDEX_SIZE {
+			vm.panic("Function return address stack overflow.")
+		}
+
+		// Switch to symbol tables of the closure and jump to function
+		vm.stack_symbolTables.Top = currentClosure.environment
+		vm.instructionIndex = vm.functions[currentClosure.function]
+		return
+
 	// Return from a function
 	case IT_Return:
-		vm.stack_symbolTables.Pop()
 		vm.reg_scopeIndex--
 
 		if vm.reg_scopeIndex <= 1 {
 			os.Exit(0)
 		}
 
+		// Restore symbol tables of the caller
 		vm.reg_returnIndex--
+		vm.stack_symbolTables.Top = vm.stack_returnSymbolTables[vm.reg_returnIndex]
 		vm.instructionIndex = vm.stack_returnIndexes[vm.reg_returnIndex] - 1
 
 	// Comparison instructions
@@ -429,6 +597,14 @@ func (vm *VirtualMachine) executeInstruction() {
 		currentObject.fields = append(currentObject.fields, vm.stack.items[vm.stack.size])
 		vm.stack.items[vm.stack.size-1] = currentObject
 
+	// Adding payload values to a variant
+	case IT_AddPayload:
+		vm.stack.size--
+
+		currentVariant := vm.stack.items[vm.stack.size-1].(variant)
+		currentVariant.payload = append(currentVariant.payload, vm.stack.items[vm.stack.size])
+		vm.stack.items[vm.stack.size-1] = currentVariant
+
 	// List operations
 	case IT_CreateList:
 		vm.stack.Push([]any{})
@@ -469,15 +645,32 @@ func (vm *VirtualMachine) executeInstruction() {
 
 		vm.stack.items[vm.stack.size-1] = append(currentSlice[:currentInt64], currentSlice[currentInt64+1:]...)
 
+	case IT_SliceList:
+		vm.stack.size -= 2
+		currentSlice = vm.stack.items[vm.stack.size-1].([]any)
+		start, end := vm.sliceBounds("List", len(currentSlice), vm.stack.items[vm.stack.size], vm.stack.items[vm.stack.size+1])
+
+		// Copy elements, so the slice doesn't share memory with the list
+		vm.stack.items[vm.stack.size-1] = append([]any{}, currentSlice[start:end]...)
+
 	// String operations
 	case IT_IndexString:
 		vm.stack.size--
+		currentRunes := []rune(vm.stack.items[vm.stack.size-1].(string))
+		currentInt64 = vm.stack.items[vm.stack.size].(int64)
 
-		if int64(len(vm.stack.items[vm.stack.size-1].(string)))-1 < vm.stack.items[vm.stack.size].(int64) {
-			vm.panic(fmt.Sprintf("String index out of range. Length is %d, index is %d.", len(vm.stack.items[vm.stack.size-1].(string)), vm.stack.items[vm.stack.size].(int64)))
+		if currentInt64 < 0 || currentInt64 >= int64(len(currentRunes)) {
+			vm.panic(fmt.Sprintf("String index out of range. Length is %d, index is %d.", len(currentRunes), currentInt64))
 		}
 
-		vm.stack.items[vm.stack.size-1] = string([]rune(vm.stack.items[vm.stack.size-1].(string))[vm.stack.items[vm.stack.size].(int64)])
+		vm.stack.items[vm.stack.size-1] = string(currentRunes[currentInt64])
+
+	case IT_SliceString:
+		vm.stack.size -= 2
+		currentRunes := []rune(vm.stack.items[vm.stack.size-1].(string))
+		start, end := vm.sliceBounds("String", len(currentRunes), vm.stack.items[vm.stack.size], vm.stack.items[vm.stack.size+1])
+
+		vm.stack.items[vm.stack.size-1] = string(currentRunes[start:end])
 
 	// Set operations
 	case IT_CreateSet:
@@ -495,12 +688,55 @@ func (vm *VirtualMachine) executeInstruction() {
 		vm.stack.size--
 		delete(vm.stack.items[vm.stack.size-1].(map[any]struct{}), vm.stack.items[vm.stack.size])
 
+	// Map operations
+	case IT_CreateMap:
+		vm.stack.Push(newOrderedMap())
+
+	case IT_InsertToMap:
+		vm.stack.size -= 2
+		vm.stack.items[vm.stack.size-1].(*orderedMap).Set(vm.stack.items[vm.stack.size], vm.stack.items[vm.stack.size+1])
+
+	case IT_IndexMap:
+		vm.stack.size--
+
+		value, exists := vm.stack.items[vm.stack.size-1].(*orderedMap).Get(vm.stack.items[vm.stack.size])
+		if !exists {
+			vm.panic("Map doesn't contain key " + necoPrintString(vm.stack.items[vm.stack.size], false) + ".")
+		}
+
+		vm.stack.items[vm.stack.size-1] = value
+
+	case IT_MapContains:
+		vm.stack.size--
+		vm.stack.items[vm.stack.size-1] = vm.stack.items[vm.stack.size-1].(*orderedMap).Contains(vm.stack.items[vm.stack.size])
+
+	case IT_RemoveMapElement:
+		vm.stack.size--
+		vm.stack.items[vm.stack.size-1].(*orderedMap).Remove(vm.stack.items[vm.stack.size])
+
 	// Panic
 	case IT_PanicIfNone:
 		if vm.stack.items[vm.stack.size-1] == nil {
 			vm.panic("Unwrapped option doesn't have a value.")
 		}
 
+	// Error handling
+	case IT_PushHandler:
+		vm.stack_handlers = append(vm.stack_handlers, handler{vm.instructionIndex + 2, vm.instructions, vm.stack.size, vm.reg_returnIndex, vm.reg_scopeIndex, vm.stack_symbolTables.Top, vm.stack_symbolTables.Size})
+
+	case IT_PopHandler:
+		vm.stack_handlers = vm.stack_handlers[:len(vm.stack_handlers)-1]
+
+	case IT_Throw:
+		thrown := vm.stack.Pop()
+
+		// Rethrow error
+		if thrownError, ok := thrown.(object); ok {
+			panic(thrownError)
+		}
+
+		vm.panic(thrown.(string))
+
 	// Stack
 	case IT_Pop:
 		vm.stack.size--
@@ -515,6 +751,22 @@ func (vm *VirtualMachine) executeInstruction() {
 			vm.stack.items[vm.stack.size-1] = vm.stack.items[vm.stack.size]
 		}
 
+	// Program arguments and exit code
+	case IT_PushArguments:
+		arguments := make([]any, len(vm.arguments))
+		for i, argument := range vm.arguments {
+			arguments[i] = argument
+		}
+		vm.stack.Push(arguments)
+
+	case IT_Exit:
+		exitCode := vm.stack.Pop().(int64)
+
+		if exitCode < 0 || exitCode > math.MaxUint8 {
+			vm.panic(fmt.Sprintf("Exit code %d is out of range. Exit codes have to be between 0 and 255.", exitCode))
+		}
+		os.Exit(int(exitCode))
+
 	// Ignore line offsets
 	case IT_LineOffset:
 
@@ -545,39 +797,131 @@ func (vm *VirtualMachine) findSymbol() *Symbol {
 	return value
 }
 
-// Prints panic message, traceback and exits program with exit code 1.
-func (vm *VirtualMachine) panic(message string) {
-	fmt.Println("\033[91mPanic in function " + vm.stack_scopes[vm.reg_scopeIndex-1] + ": " + message + "\033[0m")
+// Checks if value is one of the values of a range. Omitted (none) step is one.
+func (vm *VirtualMachine) rangeContains(value, start, end int64, step any, inclusive bool) bool {
+	stepInt64 := int64(1)
+	if step != nil {
+		stepInt64 = step.(int64)
+	}
 
-	// Get file and line
-	lines := map[int]int{}
-	file := 0
+	if stepInt64 == 0 {
+		vm.panic("Range step can't be zero.")
+	}
 
-	for i := 0; i < vm.instructionIndex; i++ {
-		if (*vm.instructions)[i].InstructionType == IT_FileMarker {
-			file = (*vm.instructions)[i].InstructionValue[0]
-			lines[file] = (*vm.instructions)[i+1].InstructionValue[0]
-			i++
-		} else if (*vm.instructions)[i].InstructionType == IT_LineOffset {
-			lines[file] += (*vm.instructions)[i].InstructionValue[0]
+	// Descending range, distances are compared as unsigned, so they don't overflow
+	if stepInt64 < 0 {
+		if value > start || value < end || value == end && !inclusive {
+			return false
+		}
+
+		return uint64(start-value)%uint64(-stepInt64) == 0
+	}
+
+	if value < start || value > end || value == end && !inclusive {
+		return false
+	}
+
+	return uint64(value-start)%uint64(stepInt64) == 0
+}
+
+// Converts slice indexes to positions in a sequence of given length. Omitted (none) indexes default to its start and end, negative indexes are counted from its end.
+func (vm *VirtualMachine) sliceBounds(sequence string, length int, start, end any) (int, int) {
+	startIndex, endIndex := int64(0), int64(length)
+
+	if start != nil {
+		startIndex = start.(int64)
+		if startIndex < 0 {
+			startIndex += int64(length)
+		}
+	}
+
+	if end != nil {
+		endIndex = end.(int64)
+		if endIndex < 0 {
+			endIndex += int64(length)
 		}
 	}
 
+	if startIndex < 0 || endIndex > int64(length) || startIndex > endIndex {
+		vm.panic(fmt.Sprintf("%s slice out of range. Length is %d, slice is [%s:%s].", sequence, length, sliceIndexString(start), sliceIndexString(end)))
+	}
+
+	return int(startIndex), int(endIndex)
+}
+
+func sliceIndexString(index any) string {
+	if index == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d", index.(int64))
+}
+
+// Throws error with message. If the error isn't caught, panic message and traceback are printed and program exits with exit code 1.
+func (vm *VirtualMachine) panic(message string) {
+	panic(vm.newError(message))
+}
+
+// Creates error object with message and traceback of current position.
+func (vm *VirtualMachine) newError(message string) object {
 	// Put current position on return stack
 	vm.stack_returnIndexes[vm.reg_returnIndex] = vm.instructionIndex
 	vm.reg_returnIndex++
 
-	vm.traceback()
-	os.Exit(1)
+	traceback := vm.traceback()
+	vm.reg_returnIndex--
+
+	return object{&errorIdentifier, []any{message, traceback}}
 }
 
-// Prints all functions on scope stack.
-func (vm *VirtualMachine) traceback() {
-	fmt.Println("Traceback:")
+// Passes thrown error to the innermost handler. Exits program if there is no handler.
+func (vm *VirtualMachine) catch(thrown any) {
+	thrownError, ok := thrown.(object)
+
+	// Convert Go runtime errors (integer division by zero, ...) to errors
+	if !ok {
+		runtimeError, isRuntimeError := thrown.(runtime.Error)
+
+		if !isRuntimeError {
+			panic(thrown)
+		}
+
+		message := strings.TrimPrefix(runtimeError.Error(), "runtime error: ")
+		thrownError = vm.newError(strings.ToUpper(message[:1]) + message[1:] + ".")
+	}
+
+	// Uncaught error
+	if len(vm.stack_handlers) == 0 {
+		fmt.Println("\033[91mPanic in function " + vm.stack_scopes[vm.reg_scopeIndex-1] + ": " + thrownError.fields[0].(string) + "\033[0m")
+		fmt.Print(thrownError.fields[1].(string))
+		os.Exit(1)
+	}
+
+	// Restore state of the handler
+	handler := vm.stack_handlers[len(vm.stack_handlers)-1]
+	vm.stack_handlers = vm.stack_handlers[:len(vm.stack_handlers)-1]
+
+	vm.instructions = handler.instructions
+	vm.instructionIndex = handler.catchIndex
+	vm.stack.size = handler.stackSize
+	vm.reg_returnIndex = handler.returnIndex
+	vm.reg_scopeIndex = handler.scopeIndex
+	vm.stack_symbolTables.Top = handler.symbolTables
+	vm.stack_symbolTables.Size = handler.symbolTableCount
+
+	// Push error for the catch block
+	vm.stack.Push(thrownError)
+}
+
+// Returns traceback of all functions on scope stack.
+func (vm *VirtualMachine) traceback() string {
+	traceback := "Traceback:\n"
+
 	for i := vm.reg_returnIndex - 1; i > 0; i-- {
 		line, file := vm.traceLine(vm.stack_returnIndexes[i])
-		fmt.Printf("   %d file %s.neco, line %d, function %s()\n", i, file, line, vm.stack_scopes[i])
+		traceback += fmt.Sprintf("   %d file %s.neco, line %d, function %s()\n", i, file, line, vm.stack_scopes[i])
 	}
+
+	return traceback
 }
 
 // Calculates file and line of instruction at instructionIndex.